@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/git"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+// analysisOptions selects what analyzeDirectory looks for and how targets are chosen
+type analysisOptions struct {
+	kind             string
+	providerCompat   string
+	terraformVersion string
+	planner          *planner // nil updates every module to its latest compatible version
+	skipUnpinned     bool     // Do not look for modules without a version attribute
+	quiet            bool     // No progress messages or warnings, e.g. when printing a diff
+}
+
+// analysis holds the dependencies found in a directory and their available versions
+type analysis struct {
+	usages           []finder.ModuleWithPath
+	unpinned         []finder.ModuleWithPath
+	providers        []finder.ProviderUsage
+	sources          map[string]*source.Source
+	fetcher          *registry.VersionFetcher
+	latestVersions   map[string][]string
+	providerVersions map[string][]string
+	summary          *report.UpdateSummary
+}
+
+// analyzeDirectory finds the modules and providers of a directory, fetches their
+// versions and builds the update summary with the target version of each module, sorted by severity
+// Returns nil when the directory has no versioned dependencies
+func analyzeDirectory(dirPath string, opts analysisOptions) (*analysis, error) {
+	a := &analysis{}
+	pl := opts.planner
+	if pl == nil {
+		pl = &planner{}
+	}
+	progressf := func(c color.Color, format string, args ...interface{}) {
+		if !opts.quiet {
+			output.Fprintf(os.Stderr, c, format, args...)
+		}
+	}
+
+	// Find all modules with versions
+	if includesModules(opts.kind) {
+		progressf(color.Blue, "Finding modules in %s...\n", dirPath)
+		var err error
+		a.usages, err = finder.FindModulesWithVersions(dirPath, pl.filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find modules: %w", err)
+		}
+
+		if !opts.skipUnpinned {
+			a.unpinned, err = finder.FindUnpinnedModules(dirPath, pl.filter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find unpinned modules: %w", err)
+			}
+		}
+	}
+
+	// Find provider requirements
+	if includesProviders(opts.kind) {
+		progressf(color.Blue, "Finding provider requirements in %s...\n", dirPath)
+		var err error
+		a.providers, err = findProviders(dirPath, pl.filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find providers: %w", err)
+		}
+	}
+
+	if len(a.usages) == 0 && len(a.unpinned) == 0 && len(a.providers) == 0 {
+		return nil, nil
+	}
+
+	if includesModules(opts.kind) {
+		progressf(color.Green, "Found %d module invocations\n", len(a.usages))
+	}
+	if includesProviders(opts.kind) {
+		progressf(color.Green, "Found %d provider requirements\n", len(a.providers))
+	}
+
+	// Analyze sources
+	progressf(color.Blue, "Analyzing module sources...\n")
+	resolver := source.NewResolver()
+	a.sources = make(map[string]*source.Source)
+	supportedSources := []*source.Source{}
+
+	for _, usage := range append(append([]finder.ModuleWithPath{}, a.usages...), a.unpinned...) {
+		if _, exists := a.sources[usage.Usage.Source]; !exists {
+			src, err := resolver.Resolve(usage.Usage.Source)
+			if err != nil {
+				progressf(color.BoldYellow, "Warning: failed to parse source %s: %v\n", usage.Usage.Source, err)
+				continue
+			}
+			a.sources[usage.Usage.Source] = src
+
+			if src.Supported {
+				supportedSources = append(supportedSources, src)
+			}
+		}
+	}
+
+	// Fetch latest versions
+	progressf(color.Blue, "Fetching latest versions from registries...\n")
+	if cacheStore != nil {
+		// Use version fetcher with cache
+		client := registry.NewClientWithCache(cacheStore)
+		a.fetcher = registry.NewVersionFetcherWithClient(client, 4)
+	} else {
+		// Fall back to version fetcher without cache
+		a.fetcher = registry.NewVersionFetcher(4)
+	}
+	a.latestVersions = a.fetcher.FetchMultipleVersions(context.Background(), supportedSources)
+	a.providerVersions = fetchProviderVersions(a.fetcher, a.providers, !opts.quiet)
+
+	// Build summary
+	builder := report.NewBuilder()
+	builder.AddModuleUsages(a.usages)
+	builder.AddUnpinnedModules(a.unpinned)
+	builder.AddSourceInfo(a.sources)
+	builder.AddLatestVersions(a.latestVersions)
+	compatibility, err := newCompatibility(dirPath, a.usages, a.fetcher, opts.providerCompat, opts.terraformVersion)
+	if err != nil {
+		return nil, err
+	}
+	compatibility.apply(builder, a.sources, a.latestVersions)
+	builder.TargetModules(func(mod *report.ModuleReport, current, compatible string) (string, bool) {
+		return pl.target(mod, current, compatible, a.latestVersions[mod.Source], compatibility, a.sources[mod.Source], opts.quiet)
+	})
+	builder.AddProviderUsages(a.providers)
+	builder.AddProviderVersions(a.providerVersions)
+	builder.TargetProviders(func(provider *report.ProviderReport, constraint string) (string, bool) {
+		return pl.providerTarget(provider.Source, constraint, a.providerVersions[provider.Source], opts.quiet)
+	})
+	a.summary = builder.Build()
+	a.summary.Registry = registryStats(a.fetcher)
+	a.summary.SourceRoot = sourceRoot(dirPath)
+	measureStaleness(a.summary, a.fetcher, a.sources)
+	report.SortBySeverity(a.summary)
+
+	return a, nil
+}
+
+// sourceRoot returns the root of the repository containing dirPath, or dirPath
+// outside of a repository, which report locations are relative to
+func sourceRoot(dirPath string) string {
+	if repo, err := git.Open(dirPath); err == nil {
+		return repo.Root()
+	}
+	return dirPath
+}
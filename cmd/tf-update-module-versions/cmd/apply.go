@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+)
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply <plan.json> [path]",
+	Short: "Apply the edits recorded by plan --out",
+	Long: `Apply exactly the edits of a plan file written by plan --out.
+Files changed since the plan was made are refused and nothing is written.
+path overrides the directory the plan was made in`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runApply,
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	var dirPath string
+	if len(args) == 2 {
+		dirPath = args[1]
+		if _, err := os.Stat(dirPath); err != nil {
+			return fmt.Errorf("invalid path: %w", err)
+		}
+	}
+
+	p, err := plan.Load(args[0], dirPath)
+	if err != nil {
+		return err
+	}
+	if len(p.Edits) == 0 {
+		fmt.Printf("%s\n", output.Success("Nothing to apply."))
+		return nil
+	}
+
+	if err := p.Verify(); err != nil {
+		return fmt.Errorf("refusing to apply %s: %w", args[0], err)
+	}
+
+	warnInterrupted()
+	output.Fprintf(os.Stderr, color.Blue, "Applying %s...\n", args[0])
+	reverted, err := applyPlan(p, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progress, "\nFiles Updated: %s\n", output.Status("%d", len(p.Files())-len(reverted)))
+	fmt.Fprintf(progress, "Total Changes: %s\n\n", output.Status("%d", len(keptEdits(p.Edits, reverted))))
+	if len(reverted) > 0 {
+		return fmt.Errorf("validation failed: changes to %d files were reverted", len(reverted))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	addHookFlags(applyCmd.Flags())
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/vdesjardins/terraform-module-versions/internal/changelog"
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+// defaultReleaseNotesTokenEnv is the environment variable holding the release notes API token
+const defaultReleaseNotesTokenEnv = "GITHUB_TOKEN"
+
+// newReleaseNotesSource creates the release notes source from flags and configuration
+// Returns nil when release notes are disabled with an empty URL
+func newReleaseNotesSource(apiURL, tokenEnv string) changelog.NotesSource {
+	if apiURL == "" {
+		return nil
+	}
+	if tokenEnv == "" {
+		tokenEnv = defaultReleaseNotesTokenEnv
+	}
+	return changelog.NewGitHubReleases(apiURL, os.Getenv(tokenEnv))
+}
+
+// collectChangelogs gathers the releases between the oldest current version with a pending
+// update and the upcoming version of each outdated module
+func collectChangelogs(
+	summary *report.UpdateSummary,
+	fetcher *registry.VersionFetcher,
+	sources map[string]*source.Source,
+	notes changelog.NotesSource,
+) {
+	ctx := context.Background()
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		src := sources[mod.Source]
+		if mod.UpdateCount == 0 || src == nil {
+			continue
+		}
+
+		pending := make(map[string]int, len(mod.Targets))
+		for ver := range mod.Targets {
+			pending[ver] = mod.CurrentVersions[ver]
+		}
+		from := oldestVersion(pending)
+		if from == "" {
+			continue
+		}
+
+		module := fetcher.GetModule(src.Namespace, src.Name, src.Provider)
+		entries, err := changelog.Collect(ctx, module, from, mod.UpcomingVersion, notes)
+		if err != nil {
+			output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to fetch release notes for %s: %v\n", mod.Source, err)
+			entries, _ = changelog.Collect(ctx, module, from, mod.UpcomingVersion, nil)
+		}
+		mod.Changelog = entries
+	}
+}
+
+// oldestVersion returns the lowest valid version among current versions
+// Versions that only differ in their text, e.g. "1.0" and "1.0.0", are ordered by text
+func oldestVersion(versions map[string]int) string {
+	var oldest string
+	var oldestParsed *semver.Version
+	for v := range versions {
+		parsed, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if oldestParsed == nil {
+			oldest, oldestParsed = v, parsed
+			continue
+		}
+		if cmp := parsed.Compare(oldestParsed); cmp < 0 || (cmp == 0 && v < oldest) {
+			oldest, oldestParsed = v, parsed
+		}
+	}
+	return oldest
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import "testing"
+
+func TestOldestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions map[string]int
+		want     string
+	}{
+		{"semver order", map[string]int{"5.1.0": 1, "4.10.0": 2, "4.9.1": 1}, "4.9.1"},
+		{"prefix and short versions", map[string]int{"v3.2": 1, "3.10.0": 1}, "v3.2"},
+		{"invalid versions skipped", map[string]int{"latest": 1, "main": 1, "2.0.0": 1}, "2.0.0"},
+		{"equal versions by text", map[string]int{"1.0.0": 1, "1.0": 1}, "1.0"},
+		{"no valid version", map[string]int{"main": 1}, ""},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := oldestVersion(tt.versions); got != tt.want {
+				t.Errorf("oldestVersion(%v) = %q, want %q", tt.versions, got, tt.want)
+			}
+		})
+	}
+}
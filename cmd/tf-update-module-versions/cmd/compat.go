@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/compat"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// Provider compatibility modes selectable with --provider-compat
+const (
+	compatHold = "hold"
+	compatWarn = "warn"
+	compatOff  = "off"
+)
+
+func validateProviderCompat(mode string) error {
+	switch mode {
+	case compatHold, compatWarn, compatOff:
+		return nil
+	default:
+		return fmt.Errorf("invalid provider compatibility mode %q: must be 'hold', 'warn' or 'off'", mode)
+	}
+}
+
+// compatibility bundles the checks applied to candidate module versions
+type compatibility struct {
+	providerMode string
+	providers    *compat.ProviderChecker
+	core         *compat.CoreChecker
+	fetcher      *registry.VersionFetcher
+	usages       []finder.ModuleWithPath
+}
+
+// newCompatibility loads the provider requirements, lock files and required_version
+// of the directories calling modules
+func newCompatibility(
+	dirPath string,
+	usages []finder.ModuleWithPath,
+	fetcher *registry.VersionFetcher,
+	providerMode string,
+	terraformVersion string,
+) (*compatibility, error) {
+	c := &compatibility{
+		providerMode: providerMode,
+		fetcher:      fetcher,
+		usages:       usages,
+	}
+
+	dirs := moduleDirs(usages, "")
+
+	if providerMode != compatOff {
+		requirements, err := finder.FindProviderRequirements(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find providers: %w", err)
+		}
+		c.providers = compat.NewProviderChecker(requirements, dirs)
+	}
+
+	core, err := compat.NewCoreChecker(terraformVersion, dirs)
+	if err != nil {
+		return nil, err
+	}
+	c.core = core
+
+	return c, nil
+}
+
+// filters returns the filters excluding incompatible versions of a module source
+func (c *compatibility) filters(src *source.Source) []versionpkg.Filter {
+	if src == nil {
+		return nil
+	}
+
+	module := c.fetcher.GetModule(src.Namespace, src.Name, src.Provider)
+	dirs := moduleDirs(c.usages, src.Original)
+
+	var filters []versionpkg.Filter
+	if f := c.core.Filter(module, dirs); f != nil {
+		filters = append(filters, f)
+	}
+	if c.providerMode == compatHold {
+		if f := c.providers.Filter(module, dirs); f != nil {
+			filters = append(filters, f)
+		}
+	}
+	return filters
+}
+
+// warning returns why a version would force a provider major upgrade, in warn mode
+func (c *compatibility) warning(src *source.Source, version string) string {
+	if c.providerMode != compatWarn || src == nil {
+		return ""
+	}
+
+	module := c.fetcher.GetModule(src.Namespace, src.Name, src.Provider)
+	f := c.providers.Filter(module, moduleDirs(c.usages, src.Original))
+	if f == nil {
+		return ""
+	}
+	if ok, reason := f(version); !ok {
+		return fmt.Sprintf("%s %s", version, reason)
+	}
+	return ""
+}
+
+// apply holds back, or warns about, module versions incompatible with the calling configurations
+func (c *compatibility) apply(builder *report.Builder, sources map[string]*source.Source, latestVersions map[string][]string) {
+	for sourceStr, availableVersions := range latestVersions {
+		src := sources[sourceStr]
+		if len(availableVersions) == 0 {
+			continue
+		}
+
+		if filters := c.filters(src); len(filters) > 0 {
+			selected, exclusions, _ := versionpkg.SelectVersionWithExclusions("", availableVersions, versionpkg.StrategyLatest, nil, filters...)
+			if len(exclusions) > 0 {
+				// Report the first blocked version above the held one
+				first := exclusions[len(exclusions)-1]
+				builder.HoldModule(sourceStr, selected, fmt.Sprintf("%s %s", first.Version, first.Reason))
+				if selected == "" {
+					continue
+				}
+				availableVersions = []string{selected}
+			}
+		}
+
+		if warning := c.warning(src, availableVersions[0]); warning != "" {
+			builder.WarnModule(sourceStr, warning)
+		}
+	}
+}
+
+// moduleDirs returns the distinct directories calling a module source, or all modules when source is empty
+func moduleDirs(usages []finder.ModuleWithPath, sourceStr string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+
+	for _, usage := range usages {
+		if sourceStr != "" && usage.Usage.Source != sourceStr {
+			continue
+		}
+		if !seen[usage.FilePath] {
+			seen[usage.FilePath] = true
+			dirs = append(dirs, usage.FilePath)
+		}
+	}
+
+	return dirs
+}
@@ -9,12 +9,17 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/vdesjardins/terraform-module-versions/internal/group"
 )
 
 // Config represents the TOML configuration file.
 type Config struct {
-	Diff  DiffConfig  `toml:"diff"`
-	Cache CacheConfig `toml:"cache"`
+	Diff         DiffConfig         `toml:"diff"`
+	Cache        CacheConfig        `toml:"cache"`
+	ReleaseNotes ReleaseNotesConfig `toml:"release_notes"`
+	Groups       []group.Rule       `toml:"group"`
+	Forge        ForgeConfig        `toml:"forge"`
+	Hooks        HooksConfig        `toml:"hooks"`
 }
 
 type DiffConfig struct {
@@ -26,6 +31,30 @@ type CacheConfig struct {
 	TTL string `toml:"ttl"`
 }
 
+// ForgeConfig selects where update --open-pr opens pull requests
+type ForgeConfig struct {
+	Type             string            `toml:"type"`               // github, gitlab or gitea, detected from the remote host when empty
+	URL              string            `toml:"url"`                // API base URL, derived from the remote host when empty
+	Repository       string            `toml:"repository"`         // owner/name, read from the remote URL when empty
+	Remote           string            `toml:"remote"`             // Remote pushed to, "origin" by default
+	Base             string            `toml:"base"`               // Target branch, the branch checked out before the run by default
+	TokenEnv         string            `toml:"token_env"`          // Environment variable holding the API token
+	Labels           []string          `toml:"labels"`             // Labels added to every pull request
+	ChangeTypeLabels map[string]string `toml:"change_type_labels"` // Change type -> label
+	Reviewers        []string          `toml:"reviewers"`          // Reviewers requested on new pull requests
+}
+
+// HooksConfig lists the commands run in each directory an update touched
+type HooksConfig struct {
+	PostUpdate []string `toml:"post_update"` // Shell commands, e.g., "terraform fmt"
+	Timeout    string   `toml:"timeout"`     // Timeout of each command, e.g., "5m"
+}
+
+type ReleaseNotesConfig struct {
+	URL      string `toml:"url"`
+	TokenEnv string `toml:"token_env"`
+}
+
 func loadConfigFile() (*Config, string, error) {
 	path, err := defaultConfigPath()
 	if err != nil {
@@ -115,6 +144,25 @@ func applyConfigDefaults(cmd *cobra.Command, cfg *Config) error {
 		}
 	}
 
+	if cfg != nil {
+		if flag := findFlag(cmd, "release-notes-url"); flag != nil && !flag.Changed && cfg.ReleaseNotes.URL != "" {
+			releaseNotesURL = cfg.ReleaseNotes.URL
+		}
+		releaseNotesTokenEnv = cfg.ReleaseNotes.TokenEnv
+		groupRules = cfg.Groups
+		forgeConfig = cfg.Forge
+		if !flagChanged(cmd, "hook") {
+			postUpdateHooks = cfg.Hooks.PostUpdate
+		}
+		if !flagChanged(cmd, "hook-timeout") && cfg.Hooks.Timeout != "" {
+			timeout, err := time.ParseDuration(cfg.Hooks.Timeout)
+			if err != nil {
+				return fmt.Errorf("invalid hooks.timeout in config: %w", err)
+			}
+			hookTimeout = timeout
+		}
+	}
+
 	if cmd != nil && cmd.Name() == "update" {
 		if flag := findFlag(cmd, "diff-tool"); flag != nil && !flag.Changed {
 			if cfg != nil && cfg.Diff.Tool != "" {
@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/group"
+	"github.com/vdesjardins/terraform-module-versions/internal/journal"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/updater"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// pendingModuleUpdates lists the module updates of an analysis, one per module and current
+// version in a stable order. With a group name, only the blocks in that group are updated
+func pendingModuleUpdates(dirPath string, result *analysis, grouper *group.Grouper, groupName string) []moduleUpdate {
+	var updates []moduleUpdate
+	for i := range result.summary.Modules {
+		mod := &result.summary.Modules[i]
+		if mod.UpdateCount == 0 {
+			continue
+		}
+
+		currentVersions := make([]string, 0, len(mod.CurrentVersions))
+		for currentVer := range mod.CurrentVersions {
+			currentVersions = append(currentVersions, currentVer)
+		}
+		sort.Strings(currentVersions)
+
+		for _, currentVer := range currentVersions {
+			targetVersion := mod.Target(currentVer)
+			if targetVersion == "" {
+				continue
+			}
+
+			var files []string
+			refs := versionRefs(result.usages, mod.Source, currentVer)
+			if groupName != "" {
+				var dirs map[string]bool
+				files, dirs = groupFiles(grouper, dirPath, mod, currentVer, groupName)
+				if len(files) == 0 {
+					continue
+				}
+				refs = refsInDirs(refs, dirPath, dirs)
+			}
+
+			updates = append(updates, moduleUpdate{mod: mod, refs: refs, from: currentVer, to: targetVersion, files: files})
+		}
+	}
+	return updates
+}
+
+// planModuleUpdates plans the edits of each module update, warning about the ones that fail unless quiet
+func planModuleUpdates(p *plan.Plan, updates []moduleUpdate, quiet bool) {
+	for _, update := range updates {
+		if err := planModuleUpdate(p, update.mod, update.refs, update.from, update.to, update.files); err != nil && !quiet {
+			output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to update %s: %v\n", update.mod.Source, err)
+		}
+	}
+}
+
+// planPins plans pinning each unpinned registry module to its latest version within constraints
+func planPins(
+	p *plan.Plan,
+	unpinned []report.UnpinnedModule,
+	latestVersions map[string][]string,
+	constraints versionpkg.Constraints,
+	style string,
+	quiet bool,
+) error {
+	for _, mod := range unpinned {
+		selectedVersion, err := versionpkg.SelectVersion("", latestVersions[mod.Source], versionpkg.StrategyLatest, constraints)
+		if err != nil {
+			if !quiet {
+				output.Fprintf(os.Stderr, color.BoldYellow, "Warning: could not select version to pin %s: %v\n", mod.Source, err)
+			}
+			continue
+		}
+
+		pinned, err := versionpkg.PinConstraint(selectedVersion, versionpkg.PinStyle(style))
+		if err != nil {
+			return err
+		}
+
+		if _, err := planPin(p, mod, pinned); err != nil && !quiet {
+			output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to pin %s: %v\n", mod.Source, err)
+		}
+	}
+	return nil
+}
+
+// planModuleUpdate plans the edits moving the blocks of a module from one version to target:
+// literal version attributes and the locals/variables versions are read from
+// files restricts the module blocks to those declared in the given files, when not nil
+func planModuleUpdate(
+	p *plan.Plan,
+	mod *report.ModuleReport,
+	refs []*finder.VersionRef,
+	from, target string,
+	files []string,
+) error {
+	for _, usage := range mod.Usages {
+		if usage.Version != from || usage.Definition != "" {
+			continue
+		}
+		if files != nil && !containsString(files, usage.File) {
+			continue
+		}
+
+		edit := plan.Edit{
+			Kind:   plan.KindModule,
+			Line:   usage.Line,
+			Block:  "module." + usage.BlockName,
+			Source: mod.Source,
+			From:   from,
+			To:     target,
+		}
+		_, err := p.Add(edit, usage.File, func(content []byte) ([]byte, bool, error) {
+			return updater.ReplaceModuleVersion(content, usage.File, usage.BlockName, from, target)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ref := range refs {
+		edit := plan.Edit{
+			Kind:   plan.KindReference,
+			Line:   ref.Line,
+			Block:  ref.String(),
+			Source: mod.Source,
+			From:   from,
+			To:     target,
+		}
+		_, err := p.Add(edit, ref.FilePath, func(content []byte) ([]byte, bool, error) {
+			return updater.ReplaceReference(content, ref, from, target)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planPin plans inserting a version attribute into an unpinned module block
+func planPin(p *plan.Plan, mod report.UnpinnedModule, pinned string) (bool, error) {
+	edit := plan.Edit{
+		Kind:   plan.KindPin,
+		Line:   mod.Line,
+		Block:  "module." + mod.BlockName,
+		Source: mod.Source,
+		To:     pinned,
+	}
+	return p.Add(edit, mod.File, func(content []byte) ([]byte, bool, error) {
+		return updater.InsertVersion(content, mod.File, mod.BlockName, pinned)
+	})
+}
+
+// planProviderConstraint plans rewriting a provider version constraint
+func planProviderConstraint(p *plan.Plan, usage finder.ProviderUsage, newConstraint string) (bool, error) {
+	edit := plan.Edit{
+		Kind:   plan.KindProvider,
+		Line:   usage.Line,
+		Block:  "provider." + usage.Name,
+		Source: usage.Source,
+		From:   usage.Constraint,
+		To:     newConstraint,
+	}
+	return p.Add(edit, usage.File, func(content []byte) ([]byte, bool, error) {
+		return updater.ReplaceProviderConstraint(content, usage.File, usage.Name, usage.Constraint, newConstraint)
+	})
+}
+
+// applyPlan writes the edits of a plan as one transaction and prints one line per kept edit
+// Every new content is staged first; the original content of each file is journaled
+// before it is written, and restored when any write or commit fails, together with the
+// commits and branch of the git session. Written files are then validated; the changes
+// of files failing validation are reverted and returned
+func applyPlan(p *plan.Plan, session *gitSession) ([]reversal, error) {
+	for _, file := range p.Files() {
+		if _, _, err := p.Render(file); err != nil {
+			return nil, err
+		}
+	}
+
+	run, err := openJournal().Begin(p.Directory, p.Edits, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	reverted, err := applyEdits(p, run, session)
+	if err != nil {
+		restored, rollbackErr := run.Rollback()
+		if rollbackErr != nil {
+			return nil, fmt.Errorf("%w; rollback failed: %v (run %s can be retried with undo)", err, rollbackErr, run.ID)
+		}
+		output.Fprintf(os.Stderr, color.BoldYellow, "Rolled back %d files\n", len(restored))
+		if gitErr := session.rollback(); gitErr != nil {
+			return nil, fmt.Errorf("%w; git rollback failed: %v", err, gitErr)
+		}
+		return nil, err
+	}
+
+	printReversals(p, reverted)
+	return reverted, run.Complete(keptEdits(p.Edits, reverted))
+}
+
+// applyEdits writes every edit of a plan through a journaled run and validates the result,
+// then records the kept edits with the git session, module by module
+func applyEdits(p *plan.Plan, run *journal.Run, session *gitSession) ([]reversal, error) {
+	baseline := parseBaseline(p)
+	if err := p.NewApplier(run.Write).Apply(p.Edits); err != nil {
+		return nil, err
+	}
+
+	reverted, err := validateWrites(p, run, baseline)
+	if err != nil {
+		return nil, err
+	}
+	edits := keptEdits(p.Edits, reverted)
+
+	// Commits per module need the files as they were after each module; the last step
+	// of each file writes its validated content back, including changes made by hooks
+	var applier *plan.Applier
+	final := make(map[string][]byte)
+	lastStep := make(map[string]int)
+	step := 0
+	if session != nil && session.commit && session.perModule {
+		for i, edit := range edits {
+			path := p.Path(edit.File)
+			if _, ok := final[path]; !ok {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+				}
+				final[path] = content
+			}
+			lastStep[path] = stepOf(edits, i)
+		}
+		applier = p.NewApplier(func(path string, content []byte) error {
+			if lastStep[path] == step {
+				content = final[path]
+			}
+			return run.Write(path, content)
+		})
+	}
+
+	for start := 0; start < len(edits); step++ {
+		end := start + 1
+		for end < len(edits) && sameCommit(edits[start], edits[end]) {
+			end++
+		}
+
+		if applier != nil {
+			if err := applier.Apply(edits[start:end]); err != nil {
+				return nil, err
+			}
+		}
+		for _, edit := range edits[start:end] {
+			from := edit.From
+			if edit.Kind == plan.KindPin {
+				from = "unpinned"
+			}
+			if err := session.record(edit.Source, from, edit.To, p.Path(edit.File)); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(progress, "%s %s\n", output.Success("✓"), describeEdit(p, edit))
+		}
+
+		// Provider constraints are committed with the rest of the run
+		if edits[start].Kind != plan.KindProvider {
+			if err := session.commitModule(); err != nil {
+				return nil, err
+			}
+		}
+		start = end
+	}
+
+	return reverted, session.finish()
+}
+
+// stepOf returns the index of the commit step holding edits[i]
+func stepOf(edits []plan.Edit, i int) int {
+	step := 0
+	for j := 1; j <= i; j++ {
+		if !sameCommit(edits[j-1], edits[j]) {
+			step++
+		}
+	}
+	return step
+}
+
+// sameCommit reports whether two consecutive edits belong to the same module commit
+func sameCommit(a, b plan.Edit) bool {
+	if a.Kind == plan.KindProvider || b.Kind == plan.KindProvider {
+		return a.Kind == b.Kind
+	}
+	return a.Source == b.Source
+}
+
+// printPlannedEdits prints one line per edit of a plan without writing files
+func printPlannedEdits(p *plan.Plan) {
+	for _, edit := range p.Edits {
+		fmt.Fprintf(progress, "%s %s\n", output.Info("•"), describeEdit(p, edit))
+	}
+}
+
+// describeEdit describes an edit, e.g., "main.tf:3: module.vpc terraform-aws-modules/vpc/aws 4.0.0 → 5.0.0"
+func describeEdit(p *plan.Plan, edit plan.Edit) string {
+	location := fmt.Sprintf("%s:%d", p.Path(edit.File), edit.Line)
+	switch edit.Kind {
+	case plan.KindPin:
+		return fmt.Sprintf("%s: %s pinned to %q", location, edit.Source, edit.To)
+	case plan.KindProvider:
+		return fmt.Sprintf("%s: provider %s %q → %q", location, edit.Source, edit.From, edit.To)
+	default:
+		return fmt.Sprintf("%s: %s %s %s → %s", location, edit.Block, edit.Source, edit.From, edit.To)
+	}
+}
+
+// writePlanDiff writes one unified diff per file edited by a plan, ordered by path and
+// rendered for display, each after a header listing what changed in the file
+func writePlanDiff(writer io.Writer, p *plan.Plan) error {
+	for _, file := range p.Files() {
+		diffOutput, err := fileDiff(p, file)
+		if err != nil {
+			return err
+		}
+		if diffOutput == "" {
+			continue
+		}
+
+		formatted, err := report.RenderOutput(diffOutput)
+		if err != nil {
+			return err
+		}
+
+		changes := fileChanges(p, file)
+		noun := "changes"
+		if len(changes) == 1 {
+			noun = "change"
+		}
+		header := output.Sprintf(color.BoldCyan, "%s (%d %s)", filepath.ToSlash(file), len(changes), noun) + "\n"
+		for _, change := range changes {
+			header += fmt.Sprintf("  %s %s\n", output.Info("•"), change)
+		}
+		if _, err := io.WriteString(writer, header+formatted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileChanges describes the dependency changes of the edits to a file, once each,
+// e.g. "terraform-aws-modules/vpc/aws 4.0.0 → 5.0.0" for a module and its version local
+func fileChanges(p *plan.Plan, file string) []string {
+	var changes []string
+	seen := make(map[string]bool)
+	for _, edit := range p.FileEdits(file) {
+		var change string
+		switch edit.Kind {
+		case plan.KindPin:
+			change = fmt.Sprintf("%s pinned to %q", edit.Source, edit.To)
+		case plan.KindProvider:
+			change = fmt.Sprintf("provider %s %q → %q", edit.Source, edit.From, edit.To)
+		default:
+			change = fmt.Sprintf("%s %s → %s", edit.Source, edit.From, edit.To)
+		}
+		if !seen[change] {
+			seen[change] = true
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// writePatchFile writes the diffs of a plan to a patch file applied with git apply
+// from the plan directory
+func writePatchFile(path string, p *plan.Plan) error {
+	var patch strings.Builder
+	for _, file := range p.Files() {
+		diffOutput, err := fileDiff(p, file)
+		if err != nil {
+			return err
+		}
+		patch.WriteString(diffOutput)
+	}
+
+	if err := os.WriteFile(path, []byte(patch.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write patch %s: %w", path, err)
+	}
+	output.Fprintf(os.Stderr, color.Green, "Patch written to %s: %d edits in %d files\n", path, len(p.Edits), len(p.Files()))
+	return nil
+}
+
+// fileDiff returns the unified diff of the edits of a plan to one file, with a/ and b/
+// paths relative to the plan directory
+func fileDiff(p *plan.Plan, file string) (string, error) {
+	content, updated, err := p.Render(file)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.ToSlash(file)
+	return report.UnifiedDiff("a/"+name, "b/"+name, string(content), string(updated), diffContext), nil
+}
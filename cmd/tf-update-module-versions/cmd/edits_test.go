@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+)
+
+func TestWritePlanDiff(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.tf": `module "vpc_a" {
+  source  = "acme/vpc/aws"
+  version = "1.0.0"
+}
+
+module "vpc_b" {
+  source  = "acme/vpc/aws"
+  version = "1.0.0"
+}
+
+module "eks" {
+  source  = "acme/eks/aws"
+  version = "2.0.0"
+}
+`,
+		"a.tf": `module "vpc_c" {
+  source  = "acme/vpc/aws"
+  version = "1.0.0"
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	a, b := filepath.Join(dir, "a.tf"), filepath.Join(dir, "b.tf")
+
+	// b.tf is planned first, and gets several edits of two modules
+	vpc := &report.ModuleReport{
+		Source: "acme/vpc/aws",
+		Usages: []report.UsageReport{
+			{BlockName: "vpc_a", File: b, Line: 1, Version: "1.0.0"},
+			{BlockName: "vpc_b", File: b, Line: 6, Version: "1.0.0"},
+			{BlockName: "vpc_c", File: a, Line: 1, Version: "1.0.0"},
+		},
+	}
+	eks := &report.ModuleReport{
+		Source: "acme/eks/aws",
+		Usages: []report.UsageReport{{BlockName: "eks", File: b, Line: 11, Version: "2.0.0"}},
+	}
+
+	p, err := plan.New(dir, time.Now())
+	if err != nil {
+		t.Fatalf("plan.New returned error: %v", err)
+	}
+	if err := planModuleUpdate(p, vpc, nil, "1.0.0", "1.1.0", nil); err != nil {
+		t.Fatalf("planModuleUpdate returned error: %v", err)
+	}
+	if err := planModuleUpdate(p, eks, nil, "2.0.0", "3.0.0", nil); err != nil {
+		t.Fatalf("planModuleUpdate returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := writePlanDiff(&out, p); err != nil {
+		t.Fatalf("writePlanDiff returned error: %v", err)
+	}
+	diff := out.String()
+
+	// One diff per file, in path order
+	for _, name := range []string{"a.tf", "b.tf"} {
+		if count := strings.Count(diff, "--- a/"+name+"\n"); count != 1 {
+			t.Errorf("diff of %s printed %d times, want once:\n%s", name, count, diff)
+		}
+	}
+	if strings.Index(diff, "--- a/a.tf") > strings.Index(diff, "b.tf (") {
+		t.Errorf("a.tf is not printed before b.tf:\n%s", diff)
+	}
+
+	// The header of b.tf lists each module change once, though vpc has two blocks there
+	header := diff[strings.Index(diff, "b.tf ("):strings.Index(diff, "--- a/b.tf")]
+	wantHeader := []string{"b.tf (2 changes)", "acme/vpc/aws 1.0.0 → 1.1.0", "acme/eks/aws 2.0.0 → 3.0.0"}
+	for _, want := range wantHeader {
+		if count := strings.Count(header, want); count != 1 {
+			t.Errorf("header of b.tf lists %q %d times, want once:\n%s", want, count, header)
+		}
+	}
+	if !strings.Contains(diff, "a.tf (1 change)\n") {
+		t.Errorf("header of a.tf missing:\n%s", diff)
+	}
+
+	// Every edit of b.tf is in its single diff
+	if count := strings.Count(diff, `+  version = "1.1.0"`); count != 3 {
+		t.Errorf("diff has %d vpc version changes, want 3:\n%s", count, diff)
+	}
+	if count := strings.Count(diff, `+  version = "3.0.0"`); count != 1 {
+		t.Errorf("diff has %d eks version changes, want 1:\n%s", count, diff)
+	}
+}
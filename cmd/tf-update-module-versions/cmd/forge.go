@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/forge"
+	"github.com/vdesjardins/terraform-module-versions/internal/git"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// forgeConfig is the [forge] section of the configuration file
+var forgeConfig ForgeConfig
+
+// defaultChangeTypeLabels label pull requests when change_type_labels is not configured
+var defaultChangeTypeLabels = map[string]string{
+	string(versionpkg.ChangeMajor): "semver:major",
+	string(versionpkg.ChangeMinor): "semver:minor",
+	string(versionpkg.ChangePatch): "semver:patch",
+}
+
+// defaultTokenEnv is the environment variable holding each forge's API token
+var defaultTokenEnv = map[string]string{
+	forge.KindGitHub: "GITHUB_TOKEN",
+	forge.KindGitLab: "GITLAB_TOKEN",
+	forge.KindGitea:  "GITEA_TOKEN",
+}
+
+// openPullRequest pushes the run's branch and opens or updates its pull request,
+// with the Markdown summary as body
+func (s *gitSession) openPullRequest(summary *report.UpdateSummary) error {
+	if s == nil || !openPullRequest {
+		return nil
+	}
+	if len(s.committed) == 0 {
+		fmt.Fprintf(progress, "%s nothing committed, no pull request opened\n", output.Info("•"))
+		return nil
+	}
+
+	remote := forgeConfig.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	client, err := newForge(s.repo, remote)
+	if err != nil {
+		return err
+	}
+
+	title, err := git.Render(s.message, git.CommitData{Changes: s.committed})
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	if err := report.WriteMarkdown(&body, summary); err != nil {
+		return err
+	}
+
+	base := forgeConfig.Base
+	if base == "" {
+		base = s.base
+	}
+	req := forge.Request{
+		Head:      s.head,
+		Base:      base,
+		Title:     firstLine(title),
+		Body:      body.String(),
+		Group:     s.group,
+		Labels:    append(append([]string{}, forgeConfig.Labels...), changeLabels(s.committed)...),
+		Reviewers: forgeConfig.Reviewers,
+	}
+
+	ctx := context.Background()
+	existing, err := forge.FindExisting(ctx, client, req)
+	if err != nil {
+		return err
+	}
+
+	// A run with an open pull request for its branch or group replaces the pull request's branch
+	remoteBranch, expected := s.head, ""
+	if existing != nil {
+		remoteBranch = existing.Head
+		expected, err = s.ownedBranch(remote, existing)
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Push(remote, s.head, remoteBranch, expected); err != nil {
+		return err
+	}
+	fmt.Fprintf(progress, "%s pushed %s to %s/%s\n", output.Success("✓"), s.head, remote, remoteBranch)
+
+	pr, created, err := forge.Publish(ctx, client, req, existing)
+	if pr == nil {
+		return err
+	}
+	if err != nil {
+		output.Fprintf(os.Stderr, color.BoldYellow, "Warning: %v\n", err)
+	}
+
+	action := "updated"
+	if created {
+		action = "opened"
+	}
+	fmt.Fprintf(progress, "%s %s pull request #%d %s\n", output.Success("✓"), action, pr.Number, pr.URL)
+	return nil
+}
+
+// ownedBranch fetches the branch of an existing pull request and returns the commit it
+// points at, refusing branches with commits not made by update runs, e.g. review fixes
+func (s *gitSession) ownedBranch(remote string, existing *forge.PullRequest) (string, error) {
+	head, err := s.repo.FetchBranch(remote, existing.Head)
+	if err != nil {
+		return "", err
+	}
+	base, err := s.repo.FetchBranch(remote, existing.Base)
+	if err != nil {
+		return "", err
+	}
+
+	messages, err := s.repo.CommitMessages(head, base)
+	if err != nil {
+		return "", err
+	}
+	for _, message := range messages {
+		if !git.HasTrailer(message) {
+			return "", fmt.Errorf("branch %s of pull request #%d has commits not made by update runs (%q): "+
+				"merge or close the pull request before updating it", existing.Head, existing.Number, firstLine(message))
+		}
+	}
+	return head, nil
+}
+
+// newForge returns the forge API configured for the repository, defaulting to the remote's host
+func newForge(repo *git.Repository, remote string) (forge.Forge, error) {
+	kind, baseURL, repository := forgeConfig.Type, forgeConfig.URL, forgeConfig.Repository
+
+	if kind == "" || baseURL == "" || repository == "" {
+		remoteURL, err := repo.RemoteURL(remote)
+		if err != nil {
+			return nil, err
+		}
+		host, path, err := forge.ParseRemoteURL(remoteURL)
+		if err != nil {
+			return nil, err
+		}
+
+		if kind == "" {
+			kind = forge.DetectKind(host)
+			if kind == "" {
+				return nil, fmt.Errorf("cannot detect the forge of %s: set forge.type in the configuration file", host)
+			}
+		}
+		if baseURL == "" {
+			baseURL = forge.APIURL(kind, host)
+		}
+		if repository == "" {
+			repository = path
+		}
+	}
+
+	tokenEnv := forgeConfig.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = defaultTokenEnv[kind]
+	}
+
+	return forge.New(kind, baseURL, repository, os.Getenv(tokenEnv))
+}
+
+// changeLabels returns the labels of the change types among committed changes
+func changeLabels(changes []git.Change) []string {
+	labels := forgeConfig.ChangeTypeLabels
+	if labels == nil {
+		labels = defaultChangeTypeLabels
+	}
+
+	var changeTypes []string
+	for _, change := range changes {
+		if changeType, ok := classifyChange(change.From, change.To); ok {
+			changeTypes = append(changeTypes, string(changeType))
+		}
+	}
+	return forge.ChangeTypeLabels(labels, changeTypes)
+}
+
+// classifyChange classifies a module version or provider constraint change
+func classifyChange(from, to string) (versionpkg.ChangeType, bool) {
+	if changeType, err := versionpkg.ClassifyChange(from, to); err == nil {
+		return changeType, true
+	}
+
+	// Provider constraints, e.g., "~> 5.31" → "~> 6.0"
+	fromBase, err := versionpkg.ConstraintBase(from)
+	if err != nil {
+		return "", false
+	}
+	toBase, err := versionpkg.ConstraintBase(to)
+	if err != nil {
+		return "", false
+	}
+	changeType, err := versionpkg.ClassifyChange(fromBase, toBase)
+	return changeType, err == nil
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/git"
+)
+
+// How commits are split with --git-commit-per
+const (
+	commitPerRun    = "run"
+	commitPerModule = "module"
+)
+
+var (
+	gitBranch             string
+	gitCommit             bool
+	gitCommitPer          string
+	commitMessageTemplate string
+	openPullRequest       bool
+)
+
+// gitSession applies an update run on a new branch and commits its changes
+type gitSession struct {
+	repo      *git.Repository
+	branch    string // Branch created before the first change, empty to stay on the current branch
+	commit    bool
+	perModule bool
+	message   *template.Template
+	changes   []git.Change // Changes not committed yet
+	committed []git.Change // Changes committed during the run
+	base      string       // Branch checked out when the run started
+	start     string       // Commit checked out when the run started
+	created   string       // Branch created by the run, deleted on rollback
+	head      string       // Branch holding the run's commits
+	group     string       // Update group applied with --group
+}
+
+// newGitSession prepares the git integration requested with flags
+// A group's branch template is used when --git-branch is not set
+// Returns nil when there is neither a branch to create nor --git-commit
+func newGitSession(dirPath, groupName, groupBranch string) (*gitSession, error) {
+	branchTemplate := gitBranch
+	if branchTemplate == "" {
+		branchTemplate = groupBranch
+	}
+	if branchTemplate == "" && !gitCommit {
+		return nil, nil
+	}
+	if !writesFiles() {
+		// Group branches are only created by runs writing files
+		if gitBranch == "" && !gitCommit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot use --git-branch or --git-commit with --dry-run, --diff or --patch")
+	}
+	if openPullRequest && (branchTemplate == "" || !gitCommit) {
+		return nil, fmt.Errorf("--open-pr requires --git-commit and a branch, from --git-branch or the group")
+	}
+	if gitCommitPer != commitPerRun && gitCommitPer != commitPerModule {
+		return nil, fmt.Errorf("invalid --git-commit-per %q: must be 'run' or 'module'", gitCommitPer)
+	}
+
+	repo, err := git.Open(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	clean, err := repo.IsClean()
+	if err != nil {
+		return nil, err
+	}
+	if !clean {
+		return nil, fmt.Errorf("working tree %s has uncommitted changes: commit or stash them first", repo.Root())
+	}
+
+	session := &gitSession{
+		repo:      repo,
+		commit:    gitCommit,
+		perModule: gitCommitPer == commitPerModule,
+		group:     groupName,
+	}
+	session.base, err = repo.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	session.head = session.base
+	session.start, err = repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	if branchTemplate != "" {
+		tmpl, err := git.ParseTemplate("branch", branchTemplate)
+		if err != nil {
+			return nil, err
+		}
+		data := git.NewBranchData(dirPath, time.Now())
+		data.Group = groupName
+		session.branch, err = git.Render(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		session.head = session.branch
+	}
+
+	messageText := git.DefaultCommitMessageTemplate
+	if commitMessageTemplate != "" {
+		content, err := os.ReadFile(commitMessageTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit message template: %w", err)
+		}
+		messageText = string(content)
+	}
+	session.message, err = git.ParseTemplate("commit message", messageText)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// record adds a file changed by an update, creating the branch on the first change
+func (s *gitSession) record(name, from, to, file string) error {
+	if s == nil {
+		return nil
+	}
+
+	if s.branch != "" {
+		if err := s.repo.CreateBranch(s.branch); err != nil {
+			return err
+		}
+		fmt.Fprintf(progress, "%s created branch %s\n", output.Success("✓"), s.branch)
+		s.created = s.branch
+		s.branch = ""
+	}
+
+	path, err := s.repo.RelativePath(file)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.changes {
+		change := &s.changes[i]
+		if change.Name == name && change.From == from && change.To == to {
+			change.AddFile(path)
+			return nil
+		}
+	}
+	s.changes = append(s.changes, git.Change{Name: name, From: from, To: to, Files: []string{path}})
+	return nil
+}
+
+// commitModule commits the changes of the module just updated, with --git-commit-per module
+func (s *gitSession) commitModule() error {
+	if s == nil || !s.perModule {
+		return nil
+	}
+	return s.flush()
+}
+
+// finish commits the remaining changes of the run
+func (s *gitSession) finish() error {
+	if s == nil {
+		return nil
+	}
+	return s.flush()
+}
+
+func (s *gitSession) flush() error {
+	if !s.commit || len(s.changes) == 0 {
+		return nil
+	}
+
+	message, err := git.Render(s.message, git.CommitData{Changes: s.changes})
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	for _, change := range s.changes {
+		files = append(files, change.Files...)
+	}
+
+	committed, err := s.repo.Commit(git.WithTrailer(message), files)
+	if err != nil {
+		return err
+	}
+	if committed {
+		fmt.Fprintf(progress, "%s committed %s\n", output.Success("✓"), firstLine(message))
+		s.committed = append(s.committed, s.changes...)
+	}
+
+	s.changes = nil
+	return nil
+}
+
+// rollback undoes the commits of a failed run and deletes the branch it created,
+// checking out the branch the run started on
+// The working tree is left alone; the journal restores the content of changed files
+func (s *gitSession) rollback() error {
+	if s == nil {
+		return nil
+	}
+
+	// Resetting also unstages the files of a failed commit
+	if err := s.repo.Reset(s.start); err != nil {
+		return err
+	}
+	s.committed = nil
+	s.changes = nil
+
+	if s.created == "" {
+		return nil
+	}
+	base := s.base
+	if base == "" {
+		base = s.start
+	}
+	if err := s.repo.Checkout(base); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteBranch(s.created); err != nil {
+		return err
+	}
+	output.Fprintf(os.Stderr, color.BoldYellow, "Deleted branch %s and checked out %s\n", s.created, base)
+	s.created = ""
+	s.head = s.base
+	return nil
+}
+
+func firstLine(text string) string {
+	for i, r := range text {
+		if r == '\n' {
+			return text[:i]
+		}
+	}
+	return text
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/forge"
+	"github.com/vdesjardins/terraform-module-versions/internal/git"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+)
+
+// gitRun runs a git command in dir and returns its trimmed output
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v: %s", args[0], err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// moduleFile writes a .tf file calling one module at version 1.0.0 and returns its report
+func moduleFile(t *testing.T, dir, name, sourceStr string) *report.ModuleReport {
+	t.Helper()
+	file := filepath.Join(dir, name+".tf")
+	content := "module \"" + name + "\" {\n  source  = \"" + sourceStr + "\"\n  version = \"1.0.0\"\n}\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+	return &report.ModuleReport{
+		Source:          sourceStr,
+		CurrentVersions: map[string]int{"1.0.0": 1},
+		Usages:          []report.UsageReport{{BlockName: name, File: file, Line: 1, Version: "1.0.0"}},
+	}
+}
+
+func TestApplyPlanRollsBackGitSession(t *testing.T) {
+	cacheDir = t.TempDir()
+	skipHooks = true
+	defer func() { skipHooks = false }()
+
+	dir := t.TempDir()
+	vpc := moduleFile(t, dir, "vpc", "acme/vpc/aws")
+	sg := moduleFile(t, dir, "sg", "acme/sg/aws")
+	gitRun(t, dir, "init", "--quiet", "--initial-branch", "main")
+	gitRun(t, dir, "config", "user.name", "Test")
+	gitRun(t, dir, "config", "user.email", "test@example.com")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "--quiet", "-m", "Initial commit")
+
+	// The commit of the second module fails after the first one was made
+	hook := filepath.Join(dir, ".git", "hooks", "pre-commit")
+	script := "#!/bin/sh\ngit diff --cached --name-only | grep -q vpc.tf && exit 1\nexit 0\n"
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	p, err := plan.New(dir, time.Now())
+	if err != nil {
+		t.Fatalf("plan.New returned error: %v", err)
+	}
+	for _, mod := range []*report.ModuleReport{sg, vpc} {
+		if err := planModuleUpdate(p, mod, nil, "1.0.0", "1.1.0", nil); err != nil {
+			t.Fatalf("planModuleUpdate returned error: %v", err)
+		}
+	}
+
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatalf("git.Open returned error: %v", err)
+	}
+	message, _ := git.ParseTemplate("commit message", git.DefaultCommitMessageTemplate)
+	start := gitRun(t, dir, "rev-parse", "HEAD")
+	session := &gitSession{
+		repo:      repo,
+		branch:    "tfmv/update",
+		commit:    true,
+		perModule: true,
+		message:   message,
+		base:      "main",
+		start:     start,
+		head:      "tfmv/update",
+	}
+
+	if _, err := applyPlan(p, session); err == nil {
+		t.Fatal("applyPlan succeeded, want the failing commit's error")
+	}
+
+	if branch := gitRun(t, dir, "branch", "--show-current"); branch != "main" {
+		t.Errorf("current branch = %q, want main", branch)
+	}
+	if head := gitRun(t, dir, "rev-parse", "HEAD"); head != start {
+		t.Errorf("HEAD = %s, want %s", head, start)
+	}
+	if branches := gitRun(t, dir, "branch", "--list", "tfmv/update"); branches != "" {
+		t.Errorf("branch tfmv/update still exists: %s", branches)
+	}
+	if status := gitRun(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("working tree not clean after rollback:\n%s", status)
+	}
+}
+
+func TestOwnedBranchRefusesForeignCommits(t *testing.T) {
+	dir := t.TempDir()
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "--quiet", "--bare", "--initial-branch", "main")
+	moduleFile(t, dir, "vpc", "acme/vpc/aws")
+	gitRun(t, dir, "init", "--quiet", "--initial-branch", "main")
+	gitRun(t, dir, "config", "user.name", "Test")
+	gitRun(t, dir, "config", "user.email", "test@example.com")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "--quiet", "-m", "Initial commit")
+	gitRun(t, dir, "remote", "add", "origin", remote)
+	gitRun(t, dir, "push", "--quiet", "origin", "main")
+
+	// A previous run opened a pull request from tfmv/update
+	gitRun(t, dir, "checkout", "--quiet", "-b", "tfmv/update")
+	gitRun(t, dir, "commit", "--quiet", "--allow-empty", "-m", git.WithTrailer("Update acme/vpc/aws to 1.1.0"))
+	gitRun(t, dir, "push", "--quiet", "origin", "tfmv/update")
+
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatalf("git.Open returned error: %v", err)
+	}
+	session := &gitSession{repo: repo}
+	existing := &forge.PullRequest{Number: 3, Head: "tfmv/update", Base: "main"}
+
+	head, err := session.ownedBranch("origin", existing)
+	if err != nil {
+		t.Fatalf("ownedBranch returned error: %v", err)
+	}
+	if want := gitRun(t, dir, "rev-parse", "tfmv/update"); head != want {
+		t.Errorf("ownedBranch = %s, want %s", head, want)
+	}
+
+	// A reviewer pushed a fix to the branch
+	gitRun(t, dir, "commit", "--quiet", "--allow-empty", "-m", "Fix variable name")
+	gitRun(t, dir, "push", "--quiet", "origin", "tfmv/update")
+
+	if _, err := session.ownedBranch("origin", existing); err == nil || !strings.Contains(err.Error(), "Fix variable name") {
+		t.Errorf("ownedBranch error = %v, want the reviewer's commit to be refused", err)
+	}
+}
+
+func TestOwnedBranchCustomCommitMessage(t *testing.T) {
+	dir := t.TempDir()
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "--quiet", "--bare", "--initial-branch", "main")
+	vpc := moduleFile(t, dir, "vpc", "acme/vpc/aws")
+	gitRun(t, dir, "init", "--quiet", "--initial-branch", "main")
+	gitRun(t, dir, "config", "user.name", "Test")
+	gitRun(t, dir, "config", "user.email", "test@example.com")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "--quiet", "-m", "Initial commit")
+	gitRun(t, dir, "remote", "add", "origin", remote)
+	gitRun(t, dir, "push", "--quiet", "origin", "main")
+
+	// The template ends with a trailer of its own, and a hook appends another after the tool's
+	tmpl := filepath.Join(t.TempDir(), "commit.tmpl")
+	text := "chore(deps): bump {{ range .Changes }}{{ .Name }} to {{ .To }}{{ end }}\n\nSigned-off-by: Bot <bot@example.com>\n"
+	if err := os.WriteFile(tmpl, []byte(text), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	hook := filepath.Join(dir, ".git", "hooks", "commit-msg")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho 'Change-Id: I0123456789abcdef' >> \"$1\"\n"), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	gitBranch, gitCommit, commitMessageTemplate = "tfmv/update", true, tmpl
+	defer func() { gitBranch, gitCommit, commitMessageTemplate = "", false, "" }()
+	session, err := newGitSession(dir, "", "")
+	if err != nil {
+		t.Fatalf("newGitSession returned error: %v", err)
+	}
+	file := vpc.Usages[0].File
+	if err := os.WriteFile(file, []byte(strings.Replace(readTestFile(t, file), "1.0.0", "1.1.0", 1)), 0644); err != nil {
+		t.Fatalf("failed to update %s: %v", file, err)
+	}
+	if err := session.record(vpc.Source, "1.0.0", "1.1.0", file); err != nil {
+		t.Fatalf("record returned error: %v", err)
+	}
+	if err := session.finish(); err != nil {
+		t.Fatalf("finish returned error: %v", err)
+	}
+	gitRun(t, dir, "push", "--quiet", "origin", "tfmv/update")
+
+	message := gitRun(t, dir, "log", "-1", "--format=%B")
+	want := "chore(deps): bump acme/vpc/aws to 1.1.0\n\nSigned-off-by: Bot <bot@example.com>\n" +
+		git.ToolTrailer + "\nChange-Id: I0123456789abcdef"
+	if message != want {
+		t.Errorf("commit message = %q, want %q", message, want)
+	}
+
+	existing := &forge.PullRequest{Number: 3, Head: "tfmv/update", Base: "main"}
+	if _, err := session.ownedBranch("origin", existing); err != nil {
+		t.Errorf("ownedBranch returned error: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/group"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// groupRules are the [[group]] rules of the configuration file
+var groupRules []group.Rule
+
+// pendingUpdates lists the pending updates of a module, one per current version and directory
+func pendingUpdates(dirPath string, mod *report.ModuleReport) []group.Update {
+	var updates []group.Update
+	index := make(map[string]int)
+
+	for _, usage := range mod.Usages {
+		update, ok := usageUpdate(dirPath, mod.Source, usage, mod.Target(usage.Version))
+		if !ok {
+			continue
+		}
+
+		key := update.From + "\x00" + update.Directory
+		i, exists := index[key]
+		if !exists {
+			i = len(updates)
+			index[key] = i
+			updates = append(updates, update)
+		}
+		if !containsString(updates[i].Files, usage.File) {
+			updates[i].Files = append(updates[i].Files, usage.File)
+		}
+	}
+
+	for i := range updates {
+		sort.Strings(updates[i].Files)
+	}
+	return updates
+}
+
+// usageUpdate describes the update of one module block to target
+// Returns false when the block is already at or past target
+func usageUpdate(dirPath, source string, usage report.UsageReport, target string) (group.Update, bool) {
+	if target == "" {
+		return group.Update{}, false
+	}
+	if newer, err := versionpkg.IsNewer(usage.Version, target); err != nil || !newer {
+		return group.Update{}, false
+	}
+
+	changeType, err := versionpkg.ClassifyChange(usage.Version, target)
+	if err != nil {
+		return group.Update{}, false
+	}
+
+	return group.Update{
+		Source:     source,
+		From:       usage.Version,
+		To:         target,
+		ChangeType: changeType,
+		Directory:  relativeDir(dirPath, usage.File),
+	}, true
+}
+
+// assignGroups records the group of each module usage with a pending update
+// Nothing is recorded when no grouping rules are configured
+func assignGroups(summary *report.UpdateSummary, dirPath string, grouper *group.Grouper) {
+	if !grouper.HasRules() {
+		return
+	}
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		if mod.UpdateCount == 0 {
+			continue
+		}
+		for j := range mod.Usages {
+			usage := &mod.Usages[j]
+			if update, ok := usageUpdate(dirPath, mod.Source, *usage, mod.Target(usage.Version)); ok {
+				usage.Group, _ = grouper.GroupOf(update)
+			}
+		}
+	}
+}
+
+// groupFiles returns the files declaring the blocks of a module updated from one version
+// that belong to the named group, and the directories holding them
+func groupFiles(
+	grouper *group.Grouper,
+	dirPath string,
+	mod *report.ModuleReport,
+	from, name string,
+) ([]string, map[string]bool) {
+	var files []string
+	dirs := make(map[string]bool)
+
+	for _, update := range pendingUpdates(dirPath, mod) {
+		if update.From != from {
+			continue
+		}
+		if groupName, _ := grouper.GroupOf(update); groupName != name {
+			continue
+		}
+		files = append(files, update.Files...)
+		dirs[update.Directory] = true
+	}
+
+	return files, dirs
+}
+
+// refsInDirs keeps the version references defined in the given directories
+func refsInDirs(refs []*finder.VersionRef, dirPath string, dirs map[string]bool) []*finder.VersionRef {
+	var kept []*finder.VersionRef
+	for _, ref := range refs {
+		if dirs[relativeDir(dirPath, ref.FilePath)] {
+			kept = append(kept, ref)
+		}
+	}
+	return kept
+}
+
+// relativeDir returns the directory of a file relative to the analyzed path
+func relativeDir(dirPath, file string) string {
+	dir := filepath.Dir(file)
+	base, err := filepath.Abs(dirPath)
+	if err != nil {
+		return dir
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	if rel, err := filepath.Rel(base, abs); err == nil {
+		return rel
+	}
+	return dir
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/impact"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// analyzeImpact compares the registry details of current and upcoming module versions
+// and records the breaking changes affecting each module call in the summary
+func analyzeImpact(
+	summary *report.UpdateSummary,
+	usages []finder.ModuleWithPath,
+	fetcher *registry.VersionFetcher,
+	sources map[string]*source.Source,
+) {
+	calls := make(map[string]map[string]*finder.ModuleCall) // Directory -> block name -> call
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		src := sources[mod.Source]
+		if mod.UpdateCount == 0 || src == nil {
+			continue
+		}
+
+		module := fetcher.GetModule(src.Namespace, src.Name, src.Provider)
+
+		for _, usage := range usages {
+			if usage.Usage.Source != mod.Source {
+				continue
+			}
+			targetVersion := mod.Target(usage.Usage.Version)
+			if newer, err := versionpkg.IsNewer(usage.Usage.Version, targetVersion); err != nil || !newer {
+				continue
+			}
+			target := module.FindVersion(targetVersion)
+			if target == nil {
+				continue
+			}
+
+			if _, ok := calls[usage.FilePath]; !ok {
+				calls[usage.FilePath] = finder.LoadModuleCalls(usage.FilePath)
+			}
+
+			current := module.FindVersion(usage.Usage.Version)
+			findings := impact.Analyze(current, target, calls[usage.FilePath][usage.Usage.BlockName])
+
+			var removedResources []string
+			if changes := impact.Diff(current.Details(), target.Details()); changes != nil {
+				removedResources = changes.RemovedResources
+			}
+
+			if len(findings) == 0 && len(removedResources) == 0 {
+				continue
+			}
+
+			mod.Impacts = append(mod.Impacts, report.ModuleImpact{
+				BlockName:        usage.Usage.BlockName,
+				File:             usage.Usage.BlockFile,
+				Line:             usage.Usage.BlockLine,
+				FromVersion:      usage.Usage.Version,
+				ToVersion:        targetVersion,
+				Findings:         findings,
+				RemovedResources: removedResources,
+			})
+		}
+	}
+}
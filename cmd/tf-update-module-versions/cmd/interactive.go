@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/interactive"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+// moduleUpdate moves the blocks of a module at one version to a target version
+type moduleUpdate struct {
+	mod   *report.ModuleReport
+	refs  []*finder.VersionRef
+	from  string
+	to    string
+	files []string // Files the module blocks are restricted to, nil for all
+}
+
+// selectUpdates lets the user review the pending module updates
+// Returns the selected updates with their chosen target, and false when the user quit
+func selectUpdates(
+	dirPath string,
+	updates []moduleUpdate,
+	latestVersions map[string][]string,
+	fetcher *registry.VersionFetcher,
+	sources map[string]*source.Source,
+) ([]moduleUpdate, bool, error) {
+	items := make([]interactive.Item, len(updates))
+	for i, update := range updates {
+		items[i] = interactive.Item{
+			Source:     update.mod.Source,
+			Files:      updateFiles(update),
+			From:       update.from,
+			To:         update.to,
+			Candidates: interactive.NewerVersions(update.from, latestVersions[update.mod.Source]),
+			Published:  publishDates(fetcher, sources[update.mod.Source]),
+			Selected:   true,
+		}
+	}
+
+	preview := func(index int) (string, error) {
+		update := updates[index]
+		p, err := plan.New(dirPath, time.Now())
+		if err != nil {
+			return "", err
+		}
+		if err := planModuleUpdate(p, update.mod, update.refs, update.from, items[index].To, update.files); err != nil {
+			return "", err
+		}
+		var diff bytes.Buffer
+		if err := writePlanDiff(&diff, p); err != nil {
+			return "", err
+		}
+		return diff.String(), nil
+	}
+
+	applied, err := interactive.Select(os.Stdin, os.Stderr, items, preview)
+	if err != nil || !applied {
+		return nil, false, err
+	}
+
+	var selected []moduleUpdate
+	for i, item := range items {
+		if item.Selected {
+			update := updates[i]
+			update.to = item.To
+			selected = append(selected, update)
+		}
+	}
+	return selected, true, nil
+}
+
+// updateFiles returns the files an update edits, sorted
+func updateFiles(update moduleUpdate) []string {
+	seen := make(map[string]bool)
+	for _, usage := range update.mod.Usages {
+		if usage.Version != update.from || usage.Definition != "" {
+			continue
+		}
+		if update.files != nil && !containsString(update.files, usage.File) {
+			continue
+		}
+		seen[usage.File] = true
+	}
+	for _, ref := range update.refs {
+		seen[ref.FilePath] = true
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// publishDates returns the publish date of each version of a module, when the registry reports it
+func publishDates(fetcher *registry.VersionFetcher, src *source.Source) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	if src == nil {
+		return dates
+	}
+
+	module := fetcher.GetModule(src.Namespace, src.Name, src.Provider)
+	if module == nil {
+		return dates
+	}
+	for _, v := range module.Versions {
+		if v.RegistryModuleInfo == nil {
+			continue
+		}
+		if published, err := time.Parse(time.RFC3339, v.RegistryModuleInfo.PublishedAt); err == nil {
+			dates[v.Version] = published
+		}
+	}
+	return dates
+}
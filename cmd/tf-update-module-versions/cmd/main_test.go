@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+)
+
+func TestMain(m *testing.M) {
+	// Commands set these up before running
+	output = color.New()
+	progress = io.Discard
+	os.Exit(m.Run())
+}
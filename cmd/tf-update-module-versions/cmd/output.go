@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+)
+
+// summaryFormatter returns the formatter selected with --output or --template
+func summaryFormatter(format, templatePath string) (report.Formatter, error) {
+	if templatePath == "" {
+		return report.LookupFormatter(format)
+	}
+	if format != report.FormatText {
+		return nil, fmt.Errorf("cannot use both --output and --template")
+	}
+	return report.NewTemplateFormatter(templatePath)
+}
+
+// outputFormats lists the registered formats for flag help
+func outputFormats() string {
+	return "'" + strings.Join(report.FormatterNames(), "', '") + "'"
+}
+
+// registryStats reports the registry lookups made by the fetcher
+func registryStats(fetcher *registry.VersionFetcher) report.RegistryStats {
+	stats := fetcher.Stats()
+	return report.RegistryStats{
+		Requests:  stats.Requests,
+		CacheHits: stats.CacheHits,
+		Errors:    stats.Errors,
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/group"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+var (
+	planFormat         string
+	planConstraint     string
+	planConstraintFile string
+	planKind           string
+	planProviderCompat string
+	planTerraformVer   string
+	planPinUnversioned bool
+	planPinStyle       string
+	planOut            string
+)
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan <path>",
+	Short: "Partition pending module updates into update groups",
+	Long: `Group pending module updates with the [[group]] rules of the configuration file.
+Each group can then be applied on its own with update --group <name>.
+With --out, every edit update would make with the same flags is also written to a plan file
+for apply: module updates, pins of unversioned modules and provider constraints`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlan,
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	dirPath := args[0]
+
+	// Validate path
+	if _, err := os.Stat(dirPath); err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	if planFormat != "text" && planFormat != "json" {
+		return fmt.Errorf("invalid plan format %q: must be 'text' or 'json'", planFormat)
+	}
+
+	// Module strategies and constraints select the target versions, as in update
+	pl, err := newPlanner(planConstraint, planConstraintFile)
+	if err != nil {
+		return err
+	}
+	if len(pl.constraints) > 0 {
+		output.Fprintf(os.Stderr, color.Cyan, "Applied constraints: %v\n", pl.constraints)
+	}
+
+	if err := validateKind(planKind); err != nil {
+		return err
+	}
+	if err := validateProviderCompat(planProviderCompat); err != nil {
+		return err
+	}
+	if planPinUnversioned && !versionpkg.IsValidPinStyle(planPinStyle) {
+		return fmt.Errorf("invalid pin style %q: must be 'exact' or 'pessimistic'", planPinStyle)
+	}
+	grouper, err := group.NewGrouper(groupRules)
+	if err != nil {
+		return err
+	}
+
+	result, err := analyzeDirectory(dirPath, analysisOptions{
+		kind:             planKind,
+		providerCompat:   planProviderCompat,
+		terraformVersion: planTerraformVer,
+		planner:          pl,
+		skipUnpinned:     !planPinUnversioned,
+	})
+	if err != nil {
+		return err
+	}
+
+	var updates []group.Update
+	if result != nil {
+		for i := range result.summary.Modules {
+			mod := &result.summary.Modules[i]
+			if mod.UpdateCount == 0 {
+				continue
+			}
+			updates = append(updates, pendingUpdates(dirPath, mod)...)
+		}
+	}
+	groups := grouper.Partition(updates)
+
+	if planOut != "" {
+		if err := writePlanFile(planOut, dirPath, result, pl); err != nil {
+			return err
+		}
+	}
+
+	if planFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if groups == nil {
+			groups = []*group.Group{}
+		}
+		return encoder.Encode(groups)
+	}
+
+	printGroups(os.Stdout, dirPath, groups)
+	return nil
+}
+
+// writePlanFile records the edits update would make with the same flags: module updates,
+// pins of unversioned modules and provider constraints
+func writePlanFile(path, dirPath string, result *analysis, pl *planner) error {
+	p, err := plan.New(dirPath, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if result != nil {
+		planModuleUpdates(p, pendingModuleUpdates(dirPath, result, nil, ""), false)
+		if planPinUnversioned {
+			err := planPins(p, result.summary.UnpinnedModules, result.latestVersions, pl.constraints, planPinStyle, false)
+			if err != nil {
+				return err
+			}
+		}
+		planProviders(p, result.providers, result.summary.Providers, false)
+	}
+
+	if err := p.Save(path); err != nil {
+		return err
+	}
+	output.Fprintf(os.Stderr, color.Green, "Plan written to %s: %d edits in %d files\n", path, len(p.Edits), len(p.Files()))
+	return nil
+}
+
+// printGroups lists each group with its updates and the command applying it
+func printGroups(writer io.Writer, dirPath string, groups []*group.Group) {
+	if len(groups) == 0 {
+		fmt.Fprintf(writer, "%s\n", output.Success("No pending module updates."))
+		return
+	}
+
+	fmt.Fprintln(writer, output.Sprintf(color.BoldBlue, "\nUpdate Groups"))
+	fmt.Fprintln(writer, output.Sprintf(color.Blue, "─────────────"))
+
+	for _, grp := range groups {
+		count := len(grp.Updates)
+		noun := "updates"
+		if count == 1 {
+			noun = "update"
+		}
+		fmt.Fprintf(writer, "\n%s (%d %s)\n", output.Sprintf(color.BoldCyan, "%s", grp.Name), count, noun)
+
+		for _, update := range grp.Updates {
+			fmt.Fprintf(writer, "  %s %s → %s (%s) in %s\n",
+				update.Source, update.From, output.Info("%s", update.To), update.ChangeType, update.Directory)
+			for _, file := range update.Files {
+				fmt.Fprintf(writer, "    - %s\n", file)
+			}
+		}
+		fmt.Fprintf(writer, "  Apply: %s\n", output.Status("%s update --group %s %s", rootCmd.Name(), grp.Name, dirPath))
+	}
+	fmt.Fprintln(writer)
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	flags := planCmd.Flags()
+	flags.StringVar(&planFormat, "format", "text", "Plan format: 'text' or 'json'")
+	flags.StringSliceVar(&modulePatterns, "module", []string{},
+		`Filter modules to plan. Format: "pattern=version_type" where version_type is 'minor' or 'latest'.
+The target of each module is the version update would write with the same flags`)
+	flags.StringVar(&globalVersion, "version", "",
+		`Plan updates of all modules to this version type: 'minor' or 'latest'.
+Mutually exclusive with --module`)
+	flags.StringVar(&planConstraint, "constraint", "",
+		`Version constraints to filter available versions. Format: ">=1.0.0,<2.0.0".
+Example: --constraint ">=1.2.3"`)
+	flags.StringVar(&planConstraintFile, "constraint-file", "",
+		`Path to file containing version constraints (one per line).
+Mutually exclusive with --constraint`)
+	flags.StringVar(&planKind, "kind", kindModules,
+		`Dependencies to plan: 'modules', 'providers' or 'all'. Update groups only hold module updates`)
+	flags.StringVar(&planProviderCompat, "provider-compat", compatHold,
+		`How to treat module versions that require a provider major upgrade: 'hold', 'warn' or 'off'`)
+	flags.StringVar(&planTerraformVer, "terraform-version", "",
+		`Terraform version the configurations run with (defaults to the lowest allowed by required_version)`)
+	flags.BoolVar(&planPinUnversioned, "pin-unversioned", false,
+		"Record inserting a version attribute into registry modules that have none in the plan file")
+	flags.StringVar(&planPinStyle, "pin-style", string(versionpkg.PinStyleExact),
+		`How versions are pinned with --pin-unversioned: 'exact' ("5.1.2") or 'pessimistic' ("~> 5.1")`)
+	flags.StringVarP(&planOut, "out", "o", "", "Write every edit of the pending updates to this plan file, applied with apply")
+}
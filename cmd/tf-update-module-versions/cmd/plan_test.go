@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+)
+
+func TestWritePlanFileRecordsEveryEdit(t *testing.T) {
+	dir := t.TempDir()
+	vpc := moduleFile(t, dir, "vpc", "acme/vpc/aws")
+	vpc.Targets = map[string]string{"1.0.0": "1.2.0"}
+	vpc.UpdateCount = 1
+
+	unpinned := filepath.Join(dir, "s3.tf")
+	if err := os.WriteFile(unpinned, []byte("module \"s3\" {\n  source = \"acme/s3/aws\"\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", unpinned, err)
+	}
+	versions := filepath.Join(dir, "versions.tf")
+	providers := `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`
+	if err := os.WriteFile(versions, []byte(providers), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", versions, err)
+	}
+
+	planPinUnversioned = true
+	defer func() { planPinUnversioned = false }()
+	pl := testPlanner(t, nil, "", "")
+	result := &analysis{
+		summary: &report.UpdateSummary{
+			Modules:         []report.ModuleReport{*vpc},
+			UnpinnedModules: []report.UnpinnedModule{{Source: "acme/s3/aws", BlockName: "s3", File: unpinned, Line: 1}},
+			Providers: []report.ProviderReport{{
+				Source:  "hashicorp/aws",
+				Targets: map[string]string{"~> 5.0": "~> 6.0"},
+			}},
+		},
+		providers: []finder.ProviderUsage{{
+			Name: "aws", Source: "hashicorp/aws", Constraint: "~> 5.0", FilePath: dir, File: versions, Line: 3,
+		}},
+		latestVersions: map[string][]string{"acme/s3/aws": {"2.1.0", "2.0.0"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := writePlanFile(path, dir, result, pl); err != nil {
+		t.Fatalf("writePlanFile returned error: %v", err)
+	}
+	p, err := plan.Load(path, "")
+	if err != nil {
+		t.Fatalf("plan.Load returned error: %v", err)
+	}
+
+	want := map[string]string{
+		plan.KindModule:   "1.2.0",
+		plan.KindPin:      "2.1.0",
+		plan.KindProvider: "~> 6.0",
+	}
+	if len(p.Edits) != len(want) {
+		t.Fatalf("plan has %d edits, want %d: %+v", len(p.Edits), len(want), p.Edits)
+	}
+	for _, edit := range p.Edits {
+		if edit.To != want[edit.Kind] {
+			t.Errorf("%s edit of %s goes to %q, want %q", edit.Kind, edit.Source, edit.To, want[edit.Kind])
+		}
+	}
+}
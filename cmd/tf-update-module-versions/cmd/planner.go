@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/filter"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// planner selects the version each module is updated to from the --module and --version
+// strategies and the version constraints, so that show reports the targets update writes
+type planner struct {
+	filter      *filter.ModuleFilter // nil to update every module to its latest compatible version
+	constraints versionpkg.Constraints
+}
+
+// newPlanner builds a planner from the --module and --version flags and the given constraints
+func newPlanner(constraint, constraintFile string) (*planner, error) {
+	constraints, err := parseConstraints(constraint, constraintFile)
+	if err != nil {
+		return nil, err
+	}
+	moduleFilter, err := buildModuleFilter()
+	if err != nil {
+		return nil, err
+	}
+	return &planner{filter: moduleFilter, constraints: constraints}, nil
+}
+
+// parseConstraints parses version constraints from a flag or a file
+func parseConstraints(constraint, constraintFile string) (versionpkg.Constraints, error) {
+	if constraint != "" && constraintFile != "" {
+		return nil, fmt.Errorf("cannot use both --constraint and --constraint-file")
+	}
+
+	if constraint != "" {
+		constraints, err := versionpkg.ParseConstraints(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint: %w", err)
+		}
+		return constraints, nil
+	}
+
+	if constraintFile != "" {
+		// Read constraint file
+		data, err := os.ReadFile(constraintFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read constraint file: %w", err)
+		}
+		constraints, err := versionpkg.ParseConstraints(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraints in file: %w", err)
+		}
+		return constraints, nil
+	}
+
+	return nil, nil
+}
+
+// target returns the version usages of a module at version current are updated to,
+// false when they are left alone
+// Without a strategy, modules go to their latest compatible version
+func (pl *planner) target(
+	mod *report.ModuleReport,
+	current, compatible string,
+	availableVersions []string,
+	compatibility *compatibility,
+	src *source.Source,
+	quiet bool,
+) (string, bool) {
+	if pl.filter == nil {
+		return compatible, true
+	}
+
+	strategy, matched := pl.filter.GetVersionStrategy(mod.Source)
+	if !matched {
+		// Module didn't match filter, skip it
+		return "", false
+	}
+
+	// If strategy is specified, use it to select version
+	if strategy == "" || len(availableVersions) == 0 {
+		return compatible, true
+	}
+
+	selectedVersion, err := versionpkg.SelectVersion(
+		current,
+		availableVersions,
+		versionpkg.Strategy(strategy),
+		pl.constraints,
+		compatibility.filters(src)...,
+	)
+	if err != nil {
+		if !quiet {
+			output.Fprintf(os.Stderr, color.BoldYellow, "Warning: could not select version for %s %s: %v\n", mod.Source, current, err)
+		}
+		return "", false
+	}
+	return selectedVersion, true
+}
+
+// providerTarget returns the constraint a provider constraint is rewritten to, false when it is
+// left alone. The target version stays within the constraint's other terms, e.g. its upper bound.
+func (pl *planner) providerTarget(sourceStr, constraint string, availableVersions []string, quiet bool) (string, bool) {
+	if len(availableVersions) == 0 {
+		return "", false
+	}
+
+	strategy := versionpkg.StrategyLatest
+	if pl.filter != nil {
+		if s, matched := pl.filter.GetVersionStrategy(sourceStr); matched && s != "" {
+			strategy = versionpkg.Strategy(s)
+		}
+	}
+
+	warn := func(format string, args ...interface{}) {
+		if !quiet {
+			output.Fprintf(os.Stderr, color.BoldYellow, format, args...)
+		}
+	}
+
+	currentVersion, err := versionpkg.ConstraintBase(constraint)
+	if err != nil {
+		warn("Warning: skipping provider %s: %v\n", sourceStr, err)
+		return "", false
+	}
+	bounds, err := versionpkg.ConstraintBounds(constraint)
+	if err != nil {
+		warn("Warning: skipping provider %s: %v\n", sourceStr, err)
+		return "", false
+	}
+
+	constraints := append(append(versionpkg.Constraints{}, pl.constraints...), bounds...)
+	targetVersion, err := versionpkg.SelectVersion(currentVersion, availableVersions, strategy, constraints)
+	if err != nil {
+		warn("Warning: could not select version for %s %q: %v\n", sourceStr, constraint, err)
+		return "", false
+	}
+
+	newConstraint, err := versionpkg.BumpConstraint(constraint, targetVersion)
+	if err != nil {
+		warn("Warning: skipping provider %s: %v\n", sourceStr, err)
+		return "", false
+	}
+	return newConstraint, newConstraint != constraint
+}
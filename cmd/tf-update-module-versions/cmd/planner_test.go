@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+// plannerAvailable are the registry versions of the test modules, newest first
+var plannerAvailable = []string{"5.1.0", "5.0.0", "4.3.0", "4.2.0", "4.0.0"}
+
+// testPlanner builds a planner from --module, --version and --constraint values
+func testPlanner(t *testing.T, modules []string, version, constraint string) *planner {
+	t.Helper()
+	modulePatterns, globalVersion = modules, version
+	t.Cleanup(func() { modulePatterns, globalVersion = nil, "" })
+
+	pl, err := newPlanner(constraint, "")
+	if err != nil {
+		t.Fatalf("newPlanner returned error: %v", err)
+	}
+	return pl
+}
+
+// plannedModule builds the report of a module called at versions, with the targets the
+// planner chooses through the report builder; show prints these targets and update
+// writes them
+func plannedModule(t *testing.T, pl *planner, sourceStr, held string, versions ...string) report.ModuleReport {
+	t.Helper()
+	var usages []finder.ModuleWithPath
+	for _, ver := range versions {
+		usages = append(usages, finder.ModuleWithPath{
+			FilePath: "main.tf",
+			Usage:    finder.ModuleUsage{Source: sourceStr, Version: ver, BlockName: "mod_" + ver, BlockFile: "main.tf"},
+		})
+	}
+
+	src, err := source.NewResolver().Resolve(sourceStr)
+	if err != nil {
+		t.Fatalf("Resolve(%s) returned error: %v", sourceStr, err)
+	}
+
+	builder := report.NewBuilder()
+	builder.AddModuleUsages(usages)
+	builder.AddSourceInfo(map[string]*source.Source{sourceStr: src})
+	builder.AddLatestVersions(map[string][]string{sourceStr: plannerAvailable})
+	if held != "" {
+		builder.HoldModule(sourceStr, held, "5.1.0 requires terraform >= 1.9")
+	}
+	builder.TargetModules(func(mod *report.ModuleReport, current, compatible string) (string, bool) {
+		return pl.target(mod, current, compatible, plannerAvailable, nil, nil, true)
+	})
+	return builder.Build().Modules[0]
+}
+
+func TestPlannerTargets(t *testing.T) {
+	tests := []struct {
+		name       string
+		modules    []string // --module
+		version    string   // --version
+		constraint string   // --constraint
+		source     string
+		held       string // Latest compatible version, when older than the latest
+		want       map[string]string
+	}{
+		{
+			name:   "no strategy updates to the latest version",
+			source: "acme/vpc/aws",
+			want:   map[string]string{"4.0.0": "5.1.0", "5.0.0": "5.1.0"},
+		},
+		{
+			name:   "no strategy updates to the latest compatible version",
+			source: "acme/vpc/aws",
+			held:   "5.0.0",
+			want:   map[string]string{"4.0.0": "5.0.0"},
+		},
+		{
+			name:    "--version minor stays within each major version",
+			version: "minor",
+			source:  "acme/vpc/aws",
+			want:    map[string]string{"4.0.0": "4.3.0", "5.0.0": "5.1.0"},
+		},
+		{
+			name:    "matched --module pattern",
+			modules: []string{"acme/vpc/aws=minor"},
+			source:  "acme/vpc/aws",
+			want:    map[string]string{"4.0.0": "4.3.0", "5.0.0": "5.1.0"},
+		},
+		{
+			name:    "unmatched --module pattern leaves the module alone",
+			modules: []string{"acme/vpc/aws=minor"},
+			source:  "acme/eks/aws",
+			want:    map[string]string{},
+		},
+		{
+			name:       "--constraint bounds the strategy",
+			version:    "latest",
+			constraint: "< 5.0.0",
+			source:     "acme/vpc/aws",
+			want:       map[string]string{"4.0.0": "4.3.0", "5.0.0": "4.3.0", "5.1.0": "4.3.0"},
+		},
+		{
+			name:       "no version within --constraint",
+			version:    "latest",
+			constraint: ">= 6.0.0",
+			source:     "acme/vpc/aws",
+			want:       map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := testPlanner(t, tt.modules, tt.version, tt.constraint)
+			mod := plannedModule(t, pl, tt.source, tt.held, "4.0.0", "5.0.0", "5.1.0")
+
+			if len(mod.Targets) != len(tt.want) {
+				t.Errorf("Targets = %v, want %v", mod.Targets, tt.want)
+			}
+			for _, current := range []string{"4.0.0", "5.0.0", "5.1.0"} {
+				if got := mod.Target(current); got != tt.want[current] {
+					t.Errorf("Target(%s) = %q, want %q", current, got, tt.want[current])
+				}
+			}
+		})
+	}
+}
+
+func TestPlannerTargetAgreesWithReport(t *testing.T) {
+	pl := testPlanner(t, nil, "minor", "!= 4.3.0")
+	mod := plannedModule(t, pl, "acme/vpc/aws", "", "4.0.0", "5.0.0")
+
+	// The update loop reads the report's targets; each matches the planner's own choice
+	for current := range mod.CurrentVersions {
+		want, ok := pl.target(&mod, current, mod.LatestVersion, plannerAvailable, nil, nil, true)
+		if !ok {
+			t.Fatalf("target(%s) left the module alone", current)
+		}
+		if got := mod.Target(current); got != want {
+			t.Errorf("report target of %s = %q, planner chose %q", current, got, want)
+		}
+	}
+	if got := mod.Target("4.0.0"); got != "4.2.0" {
+		t.Errorf("Target(4.0.0) = %q, want 4.2.0 as 4.3.0 is excluded", got)
+	}
+}
+
+func TestNewPlannerRejectsModuleAndVersion(t *testing.T) {
+	modulePatterns, globalVersion = []string{"acme/vpc/aws=minor"}, "latest"
+	defer func() { modulePatterns, globalVersion = nil, "" }()
+
+	if _, err := newPlanner("", ""); err == nil {
+		t.Error("newPlanner succeeded with both --module and --version")
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/filter"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+// Kinds of dependencies selectable with --kind
+const (
+	kindModules   = "modules"
+	kindProviders = "providers"
+	kindAll       = "all"
+)
+
+func validateKind(kind string) error {
+	switch kind {
+	case kindModules, kindProviders, kindAll:
+		return nil
+	default:
+		return fmt.Errorf("invalid kind %q: must be 'modules', 'providers' or 'all'", kind)
+	}
+}
+
+func includesModules(kind string) bool {
+	return kind == kindModules || kind == kindAll
+}
+
+func includesProviders(kind string) bool {
+	return kind == kindProviders || kind == kindAll
+}
+
+// findProviders finds required_providers entries, keeping those matching the filter
+func findProviders(dirPath string, moduleFilter *filter.ModuleFilter) ([]finder.ProviderUsage, error) {
+	usages, err := finder.FindProviderRequirements(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if moduleFilter == nil {
+		return usages, nil
+	}
+
+	var filtered []finder.ProviderUsage
+	for _, usage := range usages {
+		if _, matched := moduleFilter.GetVersionStrategy(usage.Source); matched {
+			filtered = append(filtered, usage)
+		}
+	}
+	return filtered, nil
+}
+
+// fetchProviderVersions resolves provider sources and fetches their versions
+func fetchProviderVersions(fetcher *registry.VersionFetcher, usages []finder.ProviderUsage, warn bool) map[string][]string {
+	resolver := source.NewResolver()
+	seen := make(map[string]bool)
+	var sources []*source.Source
+
+	for _, usage := range usages {
+		if seen[usage.Source] {
+			continue
+		}
+		seen[usage.Source] = true
+
+		src, err := resolver.ResolveProvider(usage.Source)
+		if err != nil {
+			if warn {
+				output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to parse provider source %s: %v\n", usage.Source, err)
+			}
+			continue
+		}
+		sources = append(sources, src)
+	}
+
+	return fetcher.FetchMultipleProviderVersions(context.Background(), sources)
+}
+
+// planProviders plans the rewrite of provider constraints to their targets in the summary
+func planProviders(p *plan.Plan, usages []finder.ProviderUsage, providers []report.ProviderReport, quiet bool) {
+	targets := make(map[string]*report.ProviderReport, len(providers))
+	for i := range providers {
+		targets[providers[i].Source] = &providers[i]
+	}
+
+	for _, usage := range usages {
+		provider, ok := targets[usage.Source]
+		if !ok {
+			continue
+		}
+		newConstraint := provider.Target(usage.Constraint)
+		if newConstraint == "" {
+			continue
+		}
+
+		if _, err := planProviderConstraint(p, usage, newConstraint); err != nil && !quiet {
+			output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to update provider %s: %v\n", usage.Source, err)
+		}
+	}
+}
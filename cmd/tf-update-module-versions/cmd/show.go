@@ -1,21 +1,28 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/vdesjardins/terraform-module-versions/internal/finder"
-	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/changelog"
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/group"
 	"github.com/vdesjardins/terraform-module-versions/internal/report"
-	"github.com/vdesjardins/terraform-module-versions/internal/source"
-	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
 )
 
 var (
-	showConstraint     string
-	showConstraintFile string
+	showConstraint       string
+	showConstraintFile   string
+	showKind             string
+	showProviderCompat   string
+	showTerraformVer     string
+	showImpact           bool
+	showChangelog        bool
+	showOutput           string
+	showTemplate         string
+	releaseNotesURL      string
+	releaseNotesTokenEnv string
 )
 
 // showCmd represents the show command
@@ -35,102 +42,74 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	// Parse constraints
-	var constraints versionpkg.Constraints
-	if showConstraint != "" && showConstraintFile != "" {
-		return fmt.Errorf("cannot use both --constraint and --constraint-file")
+	// Module strategies and constraints select the target versions, as in update
+	pl, err := newPlanner(showConstraint, showConstraintFile)
+	if err != nil {
+		return err
 	}
 
-	if showConstraint != "" {
-		var err error
-		constraints, err = versionpkg.ParseConstraints(showConstraint)
-		if err != nil {
-			return fmt.Errorf("invalid constraint: %w", err)
-		}
-	} else if showConstraintFile != "" {
-		// Read constraint file
-		data, err := os.ReadFile(showConstraintFile)
-		if err != nil {
-			return fmt.Errorf("failed to read constraint file: %w", err)
-		}
-		var err2 error
-		constraints, err2 = versionpkg.ParseConstraints(string(data))
-		if err2 != nil {
-			return fmt.Errorf("invalid constraints in file: %w", err2)
-		}
+	// Display applied constraints if any
+	if len(pl.constraints) > 0 {
+		output.Fprintf(os.Stderr, color.Cyan, "Applied constraints: %v\n", pl.constraints)
 	}
 
-	// Display applied constraints if any
-	if len(constraints) > 0 {
-		fmt.Fprintf(os.Stderr, "Applied constraints: %v\n", constraints)
+	if err := validateKind(showKind); err != nil {
+		return err
+	}
+	if err := validateProviderCompat(showProviderCompat); err != nil {
+		return err
+	}
+	formatter, err := summaryFormatter(showOutput, showTemplate)
+	if err != nil {
+		return err
 	}
 
-	// Find all modules with versions
-	fmt.Fprintf(os.Stderr, "Finding modules in %s...\n", dirPath)
-	usages, err := finder.FindModulesWithVersions(dirPath, nil)
+	grouper, err := group.NewGrouper(groupRules)
 	if err != nil {
-		return fmt.Errorf("failed to find modules: %w", err)
+		return err
 	}
 
-	if len(usages) == 0 {
+	result, err := analyzeDirectory(dirPath, analysisOptions{
+		kind:             showKind,
+		providerCompat:   showProviderCompat,
+		terraformVersion: showTerraformVer,
+		planner:          pl,
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
 		fmt.Println("No modules with version constraints found.")
 		return nil
 	}
+	summary := result.summary
 
-	fmt.Fprintf(os.Stderr, "Found %d module invocations\n", len(usages))
-
-	// Analyze sources
-	fmt.Fprintf(os.Stderr, "Analyzing module sources...\n")
-	resolver := source.NewResolver()
-	sources := make(map[string]*source.Source)
-	supportedSources := []*source.Source{}
-
-	for _, usage := range usages {
-		if _, exists := sources[usage.Usage.Source]; !exists {
-			src, err := resolver.Resolve(usage.Usage.Source)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to parse source %s: %v\n", usage.Usage.Source, err)
-				continue
-			}
-			sources[usage.Usage.Source] = src
-
-			if src.Supported {
-				supportedSources = append(supportedSources, src)
-			}
-		}
-	}
+	assignGroups(summary, dirPath, grouper)
 
-	// Fetch latest versions
-	fmt.Fprintf(os.Stderr, "Fetching latest versions from registries...\n")
-	var fetcher *registry.VersionFetcher
-	if cacheStore != nil {
-		// Use version fetcher with cache
-		client := registry.NewClientWithCache(cacheStore)
-		fetcher = registry.NewVersionFetcherWithClient(client, 4)
-	} else {
-		// Fall back to version fetcher without cache
-		fetcher = registry.NewVersionFetcher(4)
+	if showImpact {
+		analyzeImpact(summary, result.usages, result.fetcher, result.sources)
+	}
+	if showChangelog {
+		fmt.Fprintf(os.Stderr, "Collecting release notes...\n")
+		collectChangelogs(summary, result.fetcher, result.sources, newReleaseNotesSource(releaseNotesURL, releaseNotesTokenEnv))
 	}
-	latestVersions := fetcher.FetchMultipleVersions(context.Background(), supportedSources)
-
-	// Build summary
-	builder := report.NewBuilder()
-	builder.AddModuleUsages(usages)
-	builder.AddSourceInfo(sources)
-	builder.AddLatestVersions(latestVersions)
-	summary := builder.Build()
 
 	// Print report
-	printer := report.NewPrinter(summary)
-	printer.Print(nil)
-
-	return nil
+	return formatter.Format(os.Stdout, summary)
 }
 
 func init() {
 	rootCmd.AddCommand(showCmd)
 
 	flags := showCmd.Flags()
+	flags.StringSliceVar(&modulePatterns, "module", []string{},
+		`Filter modules to show. Format: "pattern=version_type" where version_type is 'minor' or 'latest'.
+The target of each module is the version update would write with the same flags`)
+
+	flags.StringVar(&globalVersion, "version", "",
+		`Show updates of all modules to this version type: 'minor' or 'latest'.
+Mutually exclusive with --module`)
+
 	flags.StringVar(&showConstraint, "constraint", "",
 		`Version constraints to filter available versions. Format: ">=1.0.0,<2.0.0".
 Example: --constraint ">=1.2.3"`)
@@ -138,4 +117,19 @@ Example: --constraint ">=1.2.3"`)
 	flags.StringVar(&showConstraintFile, "constraint-file", "",
 		`Path to file containing version constraints (one per line).
 Mutually exclusive with --constraint`)
+
+	flags.StringVar(&showKind, "kind", kindModules, "Dependencies to analyze: 'modules', 'providers' or 'all'")
+	flags.StringVar(&showProviderCompat, "provider-compat", compatHold,
+		`How to treat module versions that require a provider major upgrade: 'hold', 'warn' or 'off'`)
+	flags.StringVar(&showTerraformVer, "terraform-version", "",
+		`Terraform version the configurations run with (defaults to the lowest allowed by required_version)`)
+	flags.BoolVar(&showImpact, "impact", false,
+		"Analyze breaking changes (removed inputs and outputs, new required inputs) affecting module calls")
+	flags.BoolVar(&showChangelog, "changelog", false, "Include release notes between current and target versions")
+	flags.StringVar(&releaseNotesURL, "release-notes-url", changelog.DefaultGitHubAPI,
+		`GitHub API used to fetch release notes of GitHub-hosted modules (empty to use registry metadata only).
+The token is read from $GITHUB_TOKEN`)
+	flags.StringVarP(&showOutput, "output", "o", report.FormatText, "Output format: "+outputFormats())
+	flags.StringVar(&showTemplate, "template", "",
+		`Render the report through a Go text/template file instead of --output`)
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/report"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+	"github.com/vdesjardins/terraform-module-versions/internal/staleness"
+)
+
+// measureStaleness computes how far each module usage is behind the latest release
+// and totals libyears per directory
+func measureStaleness(
+	summary *report.UpdateSummary,
+	fetcher *registry.VersionFetcher,
+	sources map[string]*source.Source,
+) {
+	now := time.Now()
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		src := sources[mod.Source]
+		if src == nil || mod.LatestVersion == "" {
+			continue
+		}
+
+		module := fetcher.GetModule(src.Namespace, src.Name, src.Provider)
+		for j := range mod.Usages {
+			usage := &mod.Usages[j]
+			metrics, err := staleness.Measure(module, usage.Version, mod.LatestVersion, now)
+			if err != nil {
+				continue
+			}
+			usage.Staleness = &metrics
+		}
+	}
+
+	report.SummarizeStaleness(summary)
+}
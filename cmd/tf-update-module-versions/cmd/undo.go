@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/journal"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo [run-id]",
+	Short: "Revert the edits of an update run",
+	Long: `Revert the edits of an update run, as recorded in the journal of the cache directory.
+Without run-id, the latest run that was not undone is reverted.
+A completed run is only reverted when none of its files changed since; an interrupted run
+restores every file it wrote that was not changed since. Runs are listed by history`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List previous update runs",
+	Long:  "List the update runs recorded in the journal of the cache directory, newest first",
+	Args:  cobra.NoArgs,
+	RunE:  runHistory,
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	j := openJournal()
+
+	var run *journal.Run
+	var err error
+	if len(args) == 1 {
+		run, err = j.Load(args[0])
+	} else {
+		run, err = j.Latest()
+	}
+	if errors.Is(err, journal.ErrNoRun) && len(args) == 0 {
+		fmt.Printf("%s\n", output.Success("No update run to undo."))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var restored []string
+	switch run.State {
+	case journal.StatePending:
+		restored, err = run.Rollback()
+	case journal.StateCompleted:
+		restored, err = run.Undo()
+	default:
+		return fmt.Errorf("run %s is already %s", run.ID, run.State)
+	}
+	for _, file := range restored {
+		fmt.Fprintf(progress, "%s restored %s\n", output.Success("✓"), file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to undo run %s: %w", run.ID, err)
+	}
+
+	fmt.Fprintf(progress, "\nRun %s of %s undone: %s edits reverted in %s files\n",
+		run.ID, run.Directory, output.Status("%d", len(run.Edits)), output.Status("%d", len(restored)))
+	return nil
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	runs, err := openJournal().Runs()
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Printf("%s\n", output.Success("No update runs recorded."))
+		return nil
+	}
+
+	fmt.Println(output.Sprintf(color.BoldBlue, "\nUpdate History"))
+	fmt.Println(output.Sprintf(color.Blue, "──────────────"))
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		fmt.Printf("%s  %s  %-11s  %d edits in %d files  %s\n",
+			output.Sprintf(color.BoldCyan, "%s", run.ID),
+			run.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			run.State, len(run.Edits), len(run.Files), run.Directory)
+	}
+	fmt.Println()
+	return nil
+}
+
+// openJournal returns the journal of update runs, stored in the cache directory
+func openJournal() *journal.Journal {
+	return journal.Open(filepath.Join(cacheDir, "journal"))
+}
+
+// warnInterrupted reports an interrupted run whose files may be half-updated
+func warnInterrupted() {
+	if run, err := openJournal().Interrupted(); err == nil {
+		output.Fprintf(os.Stderr, color.BoldYellow, "Warning: update run %s of %s was interrupted: run undo %s to restore its files\n", run.ID, run.Directory, run.ID)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(historyCmd)
+}
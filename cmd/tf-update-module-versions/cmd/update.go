@@ -1,20 +1,20 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vdesjardins/terraform-module-versions/internal/changelog"
 	"github.com/vdesjardins/terraform-module-versions/internal/color"
 	"github.com/vdesjardins/terraform-module-versions/internal/filter"
 	"github.com/vdesjardins/terraform-module-versions/internal/finder"
-	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/group"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
 	"github.com/vdesjardins/terraform-module-versions/internal/report"
-	"github.com/vdesjardins/terraform-module-versions/internal/source"
-	"github.com/vdesjardins/terraform-module-versions/internal/updater"
 	versionpkg "github.com/vdesjardins/terraform-module-versions/internal/version"
 )
 
@@ -26,6 +26,19 @@ var (
 	dryRun               bool
 	showDiff             bool
 	diffTool             string
+	pinUnversioned       bool
+	pinStyle             string
+	updateKind           string
+	updateProviderCompat string
+	updateTerraformVer   string
+	updateOutput         string
+	updateTemplate       string
+	updateChangelog      bool
+	updateGroup          string
+	updateInteractive    bool
+	updatePatch          string
+	diffContext          int       = report.DefaultDiffContext
+	progress             io.Writer = os.Stdout // Per-change lines, moved to stderr for structured output
 )
 
 // updateCmd represents the update command
@@ -45,108 +58,87 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	// Parse constraints
-	var constraints versionpkg.Constraints
-	if updateConstraint != "" && updateConstraintFile != "" {
-		return fmt.Errorf("cannot use both --constraint and --constraint-file")
-	}
-
-	if updateConstraint != "" {
-		var err error
-		constraints, err = versionpkg.ParseConstraints(updateConstraint)
-		if err != nil {
-			return fmt.Errorf("invalid constraint: %w", err)
-		}
-	} else if updateConstraintFile != "" {
-		// Read constraint file
-		data, err := os.ReadFile(updateConstraintFile)
-		if err != nil {
-			return fmt.Errorf("failed to read constraint file: %w", err)
-		}
-		var err2 error
-		constraints, err2 = versionpkg.ParseConstraints(string(data))
-		if err2 != nil {
-			return fmt.Errorf("invalid constraints in file: %w", err2)
-		}
+	// Module strategies and constraints select the target versions
+	pl, err := newPlanner(updateConstraint, updateConstraintFile)
+	if err != nil {
+		return err
 	}
 
 	// Display applied constraints if any
-	if len(constraints) > 0 && !showDiff {
-		output.Fprintf(os.Stderr, color.Cyan, "Applied constraints: %v\n", constraints)
+	if len(pl.constraints) > 0 && !showDiff {
+		output.Fprintf(os.Stderr, color.Cyan, "Applied constraints: %v\n", pl.constraints)
 	}
 
-	// Build module filter
-	moduleFilter, err := buildModuleFilter()
+	if err := validateKind(updateKind); err != nil {
+		return err
+	}
+	if err := validateProviderCompat(updateProviderCompat); err != nil {
+		return err
+	}
+	formatter, err := summaryFormatter(updateOutput, updateTemplate)
 	if err != nil {
 		return err
 	}
-
-	// Find all modules with versions
-	if !showDiff {
-		output.Fprintf(os.Stderr, color.Blue, "Finding modules in %s...\n", dirPath)
+	if updateOutput != report.FormatText || updateTemplate != "" {
+		progress = os.Stderr
 	}
-	usages, err := finder.FindModulesWithVersions(dirPath, moduleFilter)
+	grouper, err := group.NewGrouper(groupRules)
 	if err != nil {
-		return fmt.Errorf("failed to find modules: %w", err)
+		return err
 	}
-
-	if len(usages) == 0 {
-		if !showDiff {
-			fmt.Printf("%s\n", output.Warning("No modules with version constraints found."))
+	var groupBranch string
+	if updateGroup != "" {
+		if !includesModules(updateKind) {
+			return fmt.Errorf("--group applies module updates: use it with --kind modules or all")
+		}
+		if rule := grouper.Rule(updateGroup); rule != nil {
+			groupBranch = rule.Branch
+		} else if updateGroup != group.DefaultName {
+			return fmt.Errorf("unknown update group %q: run plan to list groups", updateGroup)
 		}
-		return nil
 	}
-
-	if !showDiff {
-		output.Fprintf(os.Stderr, color.Green, "Found %d module invocations\n", len(usages))
+	if updateInteractive {
+		if !includesModules(updateKind) {
+			return fmt.Errorf("--interactive selects module updates: use it with --kind modules or all")
+		}
+		if showDiff {
+			return fmt.Errorf("cannot use both --interactive and --diff: preview diffs from the interactive view")
+		}
 	}
-
-	// Analyze sources
-	if !showDiff {
-		output.Fprintf(os.Stderr, color.Blue, "Analyzing module sources...\n")
+	if pinUnversioned && !versionpkg.IsValidPinStyle(pinStyle) {
+		return fmt.Errorf("invalid pin style %q: must be 'exact' or 'pessimistic'", pinStyle)
 	}
-	resolver := source.NewResolver()
-	sources := make(map[string]*source.Source)
-	supportedSources := []*source.Source{}
-
-	for _, usage := range usages {
-		if _, exists := sources[usage.Usage.Source]; !exists {
-			src, err := resolver.Resolve(usage.Usage.Source)
-			if err != nil {
-				if !showDiff {
-					output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to parse source %s: %v\n", usage.Usage.Source, err)
-				}
-				continue
-			}
-			sources[usage.Usage.Source] = src
-
-			if src.Supported {
-				supportedSources = append(supportedSources, src)
-			}
-		}
+	session, err := newGitSession(dirPath, updateGroup, groupBranch)
+	if err != nil {
+		return err
+	}
+	if writesFiles() {
+		warnInterrupted()
 	}
 
-	// Fetch latest versions
-	if !showDiff {
-		output.Fprintf(os.Stderr, color.Blue, "Fetching latest versions from registries...\n")
+	result, err := analyzeDirectory(dirPath, analysisOptions{
+		kind:             updateKind,
+		providerCompat:   updateProviderCompat,
+		terraformVersion: updateTerraformVer,
+		planner:          pl,
+		skipUnpinned:     !pinUnversioned,
+		quiet:            showDiff,
+	})
+	if err != nil {
+		return err
 	}
-	var fetcher *registry.VersionFetcher
-	if cacheStore != nil {
-		// Use version fetcher with cache
-		client := registry.NewClientWithCache(cacheStore)
-		fetcher = registry.NewVersionFetcherWithClient(client, 4)
-	} else {
-		// Fall back to version fetcher without cache
-		fetcher = registry.NewVersionFetcher(4)
+	if result == nil {
+		if !showDiff {
+			fmt.Printf("%s\n", output.Warning("No modules with version constraints found."))
+		}
+		return nil
 	}
-	latestVersions := fetcher.FetchMultipleVersions(context.Background(), supportedSources)
+	summary := result.summary
+	assignGroups(summary, dirPath, grouper)
 
-	// Build summary
-	builder := report.NewBuilder()
-	builder.AddModuleUsages(usages)
-	builder.AddSourceInfo(sources)
-	builder.AddLatestVersions(latestVersions)
-	summary := builder.Build()
+	if updateChangelog {
+		collectChangelogs(summary, result.fetcher, result.sources, newReleaseNotesSource(releaseNotesURL, releaseNotesTokenEnv))
+	}
 
 	var summaryWriter io.Writer = os.Stdout
 	if showDiff {
@@ -158,116 +150,100 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	// Print what will be updated
 	if !showDiff {
-		printer := report.NewPrinter(summary)
-		printer.Print(summaryWriter)
-	}
-
-	// Actually perform updates
-	if !showDiff {
-		if dryRun {
-			output.Fprintf(os.Stderr, color.Blue, "\nDry-run: planned updates...\n")
-		} else {
-			output.Fprintf(os.Stderr, color.Blue, "\nApplying updates...\n")
+		if err := formatter.Format(summaryWriter, summary); err != nil {
+			return err
 		}
 	}
-	fileUpdater := updater.NewFileUpdater()
-
-	updatesApplied := 0
-	for _, mod := range summary.Modules {
-		if mod.UpdateCount == 0 {
-			continue
-		}
 
-		// Determine target version based on filter strategy
-		targetVersion := mod.LatestVersion
-		if moduleFilter != nil {
-			strategy, matched := moduleFilter.GetVersionStrategy(mod.Source)
-			if !matched {
-				// Module didn't match filter, skip it
-				continue
-			}
+	// Collect the pending module updates before planning their edits
+	updates := pendingModuleUpdates(dirPath, result, grouper, updateGroup)
 
-			// If strategy is specified, use it to select version
-			if strategy != "" {
-				availableVersions := latestVersions[mod.Source]
-				if availableVersions != nil && len(availableVersions) > 0 {
-					// Get current version (pick first from map since they're all the same version)
-					var currentVersion string
-					for ver := range mod.CurrentVersions {
-						currentVersion = ver
-						break
-					}
-
-					selectedVersion, err := versionpkg.SelectVersion(
-						currentVersion,
-						availableVersions,
-						versionpkg.Strategy(strategy),
-						constraints,
-					)
-					if err != nil {
-						if !showDiff {
-							output.Fprintf(os.Stderr, color.BoldYellow, "Warning: could not select version for %s: %v\n", mod.Source, err)
-						}
-						continue
-					}
-					targetVersion = selectedVersion
-				}
-			}
+	if updateInteractive {
+		if len(updates) == 0 {
+			fmt.Printf("%s\n", output.Success("No pending module updates."))
+			return nil
+		}
+		var applied bool
+		updates, applied, err = selectUpdates(dirPath, updates, result.latestVersions, result.fetcher, result.sources)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			output.Fprintf(os.Stderr, color.Yellow, "Interactive update cancelled, no files were changed\n")
+			return nil
+		}
+		if len(updates) == 0 {
+			output.Fprintf(os.Stderr, color.Yellow, "No updates selected\n")
+			return nil
 		}
+	}
 
-		// Update each current version
-		for currentVer := range mod.CurrentVersions {
-			if currentVer == targetVersion {
-				continue
-			}
+	// Plan every edit before writing anything
+	var reverted []reversal
+	p, err := plan.New(dirPath, time.Now())
+	if err != nil {
+		return err
+	}
 
-			if showDiff {
-				if err := printDiffForModule(summaryWriter, fileUpdater, dirPath, mod.Source, currentVer, targetVersion); err != nil {
-					return err
-				}
-			}
+	planModuleUpdates(p, updates, showDiff)
 
-			var updates map[string]int
-			var err error
-			if dryRun {
-				updates, err = fileUpdater.CountDirectory(dirPath, mod.Source, currentVer)
-			} else {
-				updates, err = fileUpdater.UpdateDirectory(dirPath, mod.Source, currentVer, targetVersion)
-			}
-			if err != nil {
-				if !showDiff {
-					output.Fprintf(os.Stderr, color.BoldYellow, "Warning: failed to update %s: %v\n", mod.Source, err)
-				}
-				continue
+	// Pins and provider constraints are left out of update groups and interactive selections
+	if updateGroup == "" && !updateInteractive {
+		if pinUnversioned {
+			if err := planPins(p, summary.UnpinnedModules, result.latestVersions, pl.constraints, pinStyle, showDiff); err != nil {
+				return err
 			}
+		}
+		planProviders(p, result.providers, summary.Providers, showDiff)
+	}
 
-			for file, count := range updates {
-				if !showDiff {
-					if dryRun {
-						fmt.Printf("%s %s: %s %s → %s (%d changes)\n", output.Info("•"), file, mod.Source, currentVer, targetVersion, count)
-					} else {
-						fmt.Printf("%s %s: %s %s → %s (%d changes)\n", output.Success("✓"), file, mod.Source, currentVer, targetVersion, count)
-					}
-				}
-				updatesApplied += count
-			}
+	if updatePatch != "" {
+		if err := writePatchFile(updatePatch, p); err != nil {
+			return err
 		}
 	}
+	if showDiff {
+		return writePlanDiff(summaryWriter, p)
+	}
+	if updatePatch != "" {
+		return nil
+	}
 
-	if !showDiff {
-		if dryRun {
-			fmt.Printf("\nDry-run: planned updates\n")
-			fmt.Printf("Files Planned: %s\n", output.Status("%d", updatesApplied))
-			fmt.Printf("Total Planned Changes: %s\n\n", output.Status("%d", updatesApplied))
-		} else {
-			fmt.Printf("\nFiles Updated: %s\n", output.Status("%d", updatesApplied))
-			fmt.Printf("Total Changes: %s\n\n", output.Status("%d", updatesApplied))
+	if dryRun {
+		output.Fprintf(os.Stderr, color.Blue, "\nDry-run: planned updates...\n")
+		printPlannedEdits(p)
+	} else {
+		output.Fprintf(os.Stderr, color.Blue, "\nApplying updates...\n")
+		if reverted, err = applyPlan(p, session); err != nil {
+			return err
 		}
 	}
 
+	if err := session.openPullRequest(summary); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Fprintf(progress, "\nDry-run: planned updates\n")
+		fmt.Fprintf(progress, "Files Planned: %s\n", output.Status("%d", len(p.Files())))
+		fmt.Fprintf(progress, "Total Planned Changes: %s\n\n", output.Status("%d", len(p.Edits)))
+	} else {
+		edits := keptEdits(p.Edits, reverted)
+		fmt.Fprintf(progress, "\nFiles Updated: %s\n", output.Status("%d", len(p.Files())-len(reverted)))
+		fmt.Fprintf(progress, "Total Changes: %s\n\n", output.Status("%d", len(edits)))
+	}
+
+	if len(reverted) > 0 {
+		return fmt.Errorf("validation failed: changes to %d files were reverted", len(reverted))
+	}
 	return nil
 }
 
+// writesFiles reports whether the update run writes files, rather than showing or exporting its edits
+func writesFiles() bool {
+	return !dryRun && !showDiff && updatePatch == ""
+}
+
 func configurePager() error {
 	if pager != nil {
 		return nil
@@ -285,11 +261,27 @@ func configurePager() error {
 	return nil
 }
 
-func printDiffForModule(writer io.Writer, fileUpdater *updater.FileUpdater, dirPath, source, currentVer, targetVersion string) error {
-	if diffTool != "" {
-		return fileUpdater.WriteDiffWithTool(writer, dirPath, source, currentVer, targetVersion, diffTool)
+// versionRefs returns the distinct local/variable definitions that hold the
+// version of the given source
+func versionRefs(usages []finder.ModuleWithPath, source, version string) []*finder.VersionRef {
+	seen := make(map[string]bool)
+	var refs []*finder.VersionRef
+
+	for _, usage := range usages {
+		ref := usage.Usage.VersionRef
+		if ref == nil || usage.Usage.Source != source || usage.Usage.Version != version {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%s", ref.FilePath, ref)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, ref)
 	}
-	return fileUpdater.WriteDiff(writer, dirPath, source, currentVer, targetVersion)
+
+	return refs
 }
 
 // buildModuleFilter creates ModuleFilter from parsed flags
@@ -363,5 +355,45 @@ Mutually exclusive with --constraint`)
 
 	flags.BoolVarP(&dryRun, "dry-run", "n", false, "Show planned updates without writing files")
 	flags.BoolVar(&showDiff, "diff", false, "Show update diff output")
+	flags.IntVar(&diffContext, "diff-context", report.DefaultDiffContext, "Unchanged lines shown around each change in diffs and patches")
+	flags.StringVar(&updatePatch, "patch", "",
+		`Write the planned edits to this patch file instead of updating files.
+Paths are relative to <path>: apply it with "git apply" from there`)
 	flags.StringVar(&diffTool, "diff-tool", "", "External diff command to render output (defaults to built-in diff)")
+	flags.StringVar(&updateKind, "kind", kindModules,
+		`Dependencies to update: 'modules', 'providers' or 'all'.
+--module patterns and --version also apply to provider sources (e.g. "hashicorp/aws")`)
+	flags.StringVar(&updateProviderCompat, "provider-compat", compatHold,
+		`How to treat module versions that require a provider major upgrade: 'hold', 'warn' or 'off'`)
+	flags.StringVar(&updateTerraformVer, "terraform-version", "",
+		`Terraform version the configurations run with (defaults to the lowest allowed by required_version)`)
+	flags.StringVarP(&updateOutput, "output", "o", report.FormatText,
+		"Summary format: "+outputFormats()+". With other formats than text, per-change lines go to stderr")
+	flags.StringVar(&updateTemplate, "template", "",
+		`Render the summary through a Go text/template file instead of --output`)
+	flags.BoolVar(&updateChangelog, "changelog", false, "Link release notes of target versions in the summary")
+	flags.StringVar(&releaseNotesURL, "release-notes-url", changelog.DefaultGitHubAPI,
+		`GitHub API used to fetch release notes of GitHub-hosted modules (empty to use registry metadata only)`)
+	flags.BoolVar(&pinUnversioned, "pin-unversioned", false, "Insert a version attribute into registry modules that have none")
+	flags.StringVar(&pinStyle, "pin-style", string(versionpkg.PinStyleExact),
+		`How versions are pinned with --pin-unversioned: 'exact' ("5.1.2") or 'pessimistic' ("~> 5.1")`)
+	flags.StringVar(&updateGroup, "group", "",
+		`Only apply the module updates of this update group, as listed by plan.
+Uses the group's branch template from the configuration file when --git-branch is not set`)
+	flags.BoolVarP(&updateInteractive, "interactive", "i", false,
+		`Review pending module updates before applying them: toggle updates, pick other versions and preview diffs.
+Uses a line-based prompt when stdin or stderr is not a terminal, and on platforms other than Linux. Pins and provider constraints are left out`)
+	flags.StringVar(&gitBranch, "git-branch", "",
+		`Create and switch to this branch before writing changes. Go template with .Date, .Timestamp, .Directory and .Group.
+Example: --git-branch "tfmv/{{.Directory}}-{{.Date}}"`)
+	flags.BoolVar(&gitCommit, "git-commit", false, "Commit the updated files with a message listing each change")
+	flags.StringVar(&gitCommitPer, "git-commit-per", commitPerRun, `With --git-commit, create one commit per 'run' or per 'module'`)
+	flags.BoolVar(&openPullRequest, "open-pr", false,
+		`Push the branch and open a pull request (merge request on GitLab) with the Markdown summary,
+or update the open one for the same branch or group. Configured in the [forge] section`)
+	flags.StringVar(&commitMessageTemplate, "commit-message-template", "",
+		`Go template file for commit messages, rendered with .Changes (each with .Name, .From, .To and .Files).
+An "Updated-by: terraform-module-versions" trailer is appended to the rendered message, joining its trailers if it ends with some;
+--open-pr only replaces branches whose commits all carry it`)
+	addHookFlags(flags)
 }
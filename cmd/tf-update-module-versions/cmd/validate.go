@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/journal"
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/validate"
+)
+
+var (
+	postUpdateHooks []string
+	hookTimeout     = 10 * time.Minute
+	skipHooks       bool
+)
+
+// reversal records why the changes of a file were reverted
+type reversal struct {
+	file   string // File relative to the plan directory
+	reason string
+}
+
+// parseBaseline returns the files of a plan that fail to parse before any edit,
+// so that validation only reports errors introduced by the update
+func parseBaseline(p *plan.Plan) map[string]bool {
+	baseline := make(map[string]bool)
+	dirs, byDir := touchedDirs(p)
+	for _, dir := range dirs {
+		for path := range validate.ParseErrors(dir, planPaths(p, byDir[dir])) {
+			baseline[path] = true
+		}
+	}
+	return baseline
+}
+
+// validateWrites re-parses every file written by a run and runs the post-update hooks
+// in each touched directory; files that no longer parse, and every file of a directory
+// whose hook fails, are restored to their original content
+// Terraform files and lock files changed by hooks are restored with their directory,
+// or journaled so that rollback and undo restore them
+func validateWrites(p *plan.Plan, run *journal.Run, baseline map[string]bool) ([]reversal, error) {
+	dirs, byDir := touchedDirs(p)
+
+	var reverted []reversal
+	for _, dir := range dirs {
+		files := byDir[dir]
+
+		// Files that no longer parse are reverted on their own
+		parseErrors := validate.ParseErrors(dir, planPaths(p, files))
+		var kept []string
+		for _, file := range files {
+			if err, failed := parseErrors[p.Path(file)]; failed && !baseline[p.Path(file)] {
+				if restoreErr := run.Restore(p.Path(file)); restoreErr != nil {
+					return reverted, restoreErr
+				}
+				reverted = append(reverted, reversal{file: file, reason: fmt.Sprintf("no longer parses: %v", err)})
+				continue
+			}
+			kept = append(kept, file)
+		}
+		if len(kept) == 0 || skipHooks {
+			continue
+		}
+
+		// Hooks may change other files of the directory, e.g., terraform fmt or the lock file
+		snapshot, err := validate.TakeSnapshot(dir)
+		if err != nil {
+			return reverted, err
+		}
+		reason := runHooks(dir)
+		sideEffects, err := hookSideEffects(p, snapshot, kept)
+		if err != nil {
+			return reverted, err
+		}
+
+		if reason != "" {
+			for _, file := range kept {
+				if err := run.Restore(p.Path(file)); err != nil {
+					return reverted, err
+				}
+				reverted = append(reverted, reversal{file: file, reason: reason})
+			}
+			for _, path := range sideEffects {
+				if err := snapshot.Restore(path); err != nil {
+					return reverted, err
+				}
+				output.Fprintf(os.Stderr, color.BoldRed, "✗ %s: hook changes reverted\n", path)
+			}
+			continue
+		}
+
+		// Journal what the hooks wrote, e.g., terraform fmt, so undo still applies
+		for _, file := range kept {
+			content, err := os.ReadFile(p.Path(file))
+			if err != nil {
+				return reverted, fmt.Errorf("failed to read file %s: %w", p.Path(file), err)
+			}
+			if err := run.Write(p.Path(file), content); err != nil {
+				return reverted, err
+			}
+		}
+		for _, path := range sideEffects {
+			original, existed := snapshot.Original(path)
+			if err := run.Track(path, original, existed); err != nil {
+				return reverted, err
+			}
+		}
+	}
+
+	return reverted, nil
+}
+
+// hookSideEffects returns the files changed by hooks since a snapshot, other than
+// the planned files of the directory
+func hookSideEffects(p *plan.Plan, snapshot *validate.Snapshot, planned []string) ([]string, error) {
+	changed, err := snapshot.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(planned))
+	for _, file := range planned {
+		abs, err := filepath.Abs(p.Path(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", p.Path(file), err)
+		}
+		skip[abs] = true
+	}
+
+	var sideEffects []string
+	for _, path := range changed {
+		if !skip[path] {
+			sideEffects = append(sideEffects, path)
+		}
+	}
+	return sideEffects, nil
+}
+
+// touchedDirs returns the directories of the files edited by a plan, sorted, and their files
+func touchedDirs(p *plan.Plan) ([]string, map[string][]string) {
+	byDir := make(map[string][]string)
+	for _, file := range p.Files() {
+		dir := filepath.Dir(p.Path(file))
+		byDir[dir] = append(byDir[dir], file)
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, byDir
+}
+
+// planPaths returns the paths of planned files
+func planPaths(p *plan.Plan, files []string) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = p.Path(file)
+	}
+	return paths
+}
+
+// runHooks runs the post-update hooks in a directory, stopping at the first failure
+// Returns why the directory failed, empty when every hook succeeded
+func runHooks(dir string) string {
+	for _, hook := range postUpdateHooks {
+		out, err := validate.RunHook(context.Background(), dir, hook, hookTimeout)
+		if err != nil {
+			reason := err.Error()
+			if out = strings.TrimSpace(out); out != "" {
+				reason += "\n" + indent(out, "    ")
+			}
+			return reason
+		}
+		fmt.Fprintf(progress, "%s %s: %s\n", output.Success("✓"), dir, hook)
+	}
+	return ""
+}
+
+// printReversals reports the files whose changes were reverted
+func printReversals(p *plan.Plan, reverted []reversal) {
+	for _, r := range reverted {
+		output.Fprintf(os.Stderr, color.BoldRed, "✗ %s: changes reverted, %s\n", p.Path(r.file), r.reason)
+	}
+}
+
+// keptEdits returns the edits of files that were not reverted
+func keptEdits(edits []plan.Edit, reverted []reversal) []plan.Edit {
+	var kept []plan.Edit
+	for _, edit := range edits {
+		revertedFile := false
+		for _, r := range reverted {
+			if r.file == edit.File {
+				revertedFile = true
+				break
+			}
+		}
+		if !revertedFile {
+			kept = append(kept, edit)
+		}
+	}
+	return kept
+}
+
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
+
+// addHookFlags adds the post-update hook flags of update and apply
+func addHookFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&postUpdateHooks, "hook", nil,
+		`Command run in each directory touched by the update, e.g. "terraform fmt" (repeatable).
+A failure reverts the changes of that directory, including the .tf and lock files written by hooks. Defaults to hooks.post_update of the configuration file`)
+	flags.DurationVar(&hookTimeout, "hook-timeout", 10*time.Minute, "Timeout of each post-update hook")
+	flags.BoolVar(&skipHooks, "no-hooks", false, "Do not run post-update hooks")
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+)
+
+// hookDir writes a module to update and a .tf file the update does not plan, and
+// returns the plan of the update
+func hookDir(t *testing.T) (*plan.Plan, string) {
+	t.Helper()
+	dir := t.TempDir()
+	vpc := moduleFile(t, dir, "vpc", "acme/vpc/aws")
+	if err := os.WriteFile(filepath.Join(dir, "other.tf"), []byte("locals {\n  a=1\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write other.tf: %v", err)
+	}
+
+	p, err := plan.New(dir, time.Now())
+	if err != nil {
+		t.Fatalf("plan.New returned error: %v", err)
+	}
+	if err := planModuleUpdate(p, vpc, nil, "1.0.0", "1.1.0", nil); err != nil {
+		t.Fatalf("planModuleUpdate returned error: %v", err)
+	}
+	return p, dir
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestApplyPlanRestoresFilesOfFailingHook(t *testing.T) {
+	cacheDir = t.TempDir()
+	postUpdateHooks = []string{"echo 'locals {}' > other.tf; echo lock > .terraform.lock.hcl; exit 1"}
+	defer func() { postUpdateHooks = nil }()
+
+	p, dir := hookDir(t)
+	vpc := readTestFile(t, filepath.Join(dir, "vpc.tf"))
+
+	reverted, err := applyPlan(p, nil)
+	if err != nil {
+		t.Fatalf("applyPlan returned error: %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].file != "vpc.tf" {
+		t.Errorf("reverted = %v, want vpc.tf", reverted)
+	}
+
+	if got := readTestFile(t, filepath.Join(dir, "vpc.tf")); got != vpc {
+		t.Errorf("vpc.tf = %q, want it restored", got)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "other.tf")); got != "locals {\n  a=1\n}\n" {
+		t.Errorf("other.tf = %q, want the hook's change restored", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".terraform.lock.hcl")); !os.IsNotExist(err) {
+		t.Errorf("the lock file created by the failing hook was kept")
+	}
+}
+
+func TestApplyPlanJournalsFilesOfHooks(t *testing.T) {
+	cacheDir = t.TempDir()
+	postUpdateHooks = []string{"echo 'locals {}' > other.tf; echo lock > .terraform.lock.hcl"}
+	defer func() { postUpdateHooks = nil }()
+
+	p, dir := hookDir(t)
+	vpc := readTestFile(t, filepath.Join(dir, "vpc.tf"))
+
+	if _, err := applyPlan(p, nil); err != nil {
+		t.Fatalf("applyPlan returned error: %v", err)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "other.tf")); got != "locals {}\n" {
+		t.Fatalf("other.tf = %q, want the hook's change", got)
+	}
+
+	run, err := openJournal().Latest()
+	if err != nil {
+		t.Fatalf("Latest returned error: %v", err)
+	}
+	if _, err := run.Undo(); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+
+	if got := readTestFile(t, filepath.Join(dir, "vpc.tf")); got != vpc {
+		t.Errorf("vpc.tf = %q after undo, want it restored", got)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "other.tf")); got != "locals {\n  a=1\n}\n" {
+		t.Errorf("other.tf = %q after undo, want it restored", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".terraform.lock.hcl")); !os.IsNotExist(err) {
+		t.Errorf("undo kept the lock file created by the hook")
+	}
+}
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -237,28 +239,8 @@ func (ds *DiskStore) cleanupExpired() {
 }
 
 // hashKey creates a filesystem-safe filename from a cache key.
+// The whole key is hashed, as keys often share long prefixes, e.g. the versions of a module.
 func hashKey(key string) string {
-	// Simple hash: use first 32 chars of key, replacing unsafe chars
-	if len(key) > 32 {
-		key = key[:32]
-	}
-
-	// Replace unsafe characters with underscores
-	unsafe := []rune{'/', '\\', ':', '*', '?', '"', '<', '>', '|', '\x00'}
-	result := make([]rune, len(key))
-	for i, ch := range key {
-		safe := true
-		for _, u := range unsafe {
-			if ch == u {
-				result[i] = '_'
-				safe = false
-				break
-			}
-		}
-		if safe {
-			result[i] = ch
-		}
-	}
-
-	return string(result)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
 }
@@ -261,6 +261,44 @@ func TestDiskStore_Persistence(t *testing.T) {
 	}
 }
 
+func TestDiskStore_PersistenceSharedPrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	store1, err := NewDiskStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store1: %v", err)
+	}
+
+	// Keys longer than a filename prefix that only differ at their end
+	keys := []string{
+		"module_info:registry.terraform.io:terraform-aws-modules:vpc:aws:5.0.0",
+		"module_info:registry.terraform.io:terraform-aws-modules:vpc:aws:4.2.0",
+	}
+	for _, key := range keys {
+		if err := store1.Set(key, key, 1*time.Hour); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	store1.Close()
+
+	store2, err := NewDiskStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store2: %v", err)
+	}
+	defer store2.Close()
+
+	for _, key := range keys {
+		retrieved, err := store2.Get(key)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if retrieved != key {
+			t.Errorf("expected %v, got %v", key, retrieved)
+		}
+	}
+}
+
 func TestDiskStore_ComplexValues(t *testing.T) {
 	store, _ := setupTestStore(t)
 	defer store.Close()
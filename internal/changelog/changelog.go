@@ -0,0 +1,98 @@
+package changelog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// Entry is the release information of one module version
+type Entry struct {
+	Version     string `json:"version"`
+	PublishedAt string `json:"published_at,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+// NotesSource provides release notes for module source repositories
+type NotesSource interface {
+	// ReleaseNotes returns entries keyed by version for a repository URL
+	// Returns nil without error when the repository is not handled by the source
+	ReleaseNotes(ctx context.Context, repositoryURL string) (map[string]Entry, error)
+}
+
+// Collect gathers the entries of versions newer than from, up to and including to, newest first
+// Entries come from the registry's per-version metadata and are completed with release
+// notes from notes when it handles the module's source repository
+func Collect(ctx context.Context, module *registry.Module, from, to string, notes NotesSource) ([]Entry, error) {
+	if module == nil {
+		return nil, nil
+	}
+
+	var versions []string
+	byVersion := make(map[string]*registry.Version)
+	for _, v := range module.Versions {
+		newerThanFrom, err := version.IsNewer(from, v.Version)
+		if err != nil || !newerThanFrom {
+			continue
+		}
+		if newerThanTo, err := version.IsNewer(to, v.Version); err != nil || newerThanTo {
+			continue
+		}
+		versions = append(versions, v.Version)
+		byVersion[v.Version] = v
+	}
+
+	sorted, err := version.SortVersions(versions)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases map[string]Entry
+	if notes != nil {
+		if repositoryURL := repositoryOf(byVersion); repositoryURL != "" {
+			releases, err = notes.ReleaseNotes(ctx, repositoryURL)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	entries := make([]Entry, 0, len(sorted))
+	for _, v := range sorted {
+		entry := Entry{Version: v}
+		if info := byVersion[v].RegistryModuleInfo; info != nil {
+			entry.PublishedAt = info.PublishedAt
+			entry.URL = info.Source
+		}
+		if release, ok := releases[normalizeVersion(v)]; ok {
+			if release.URL != "" {
+				entry.URL = release.URL
+			}
+			if entry.PublishedAt == "" {
+				entry.PublishedAt = release.PublishedAt
+			}
+			entry.Notes = release.Notes
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// repositoryOf returns the source repository recorded in registry metadata
+func repositoryOf(versions map[string]*registry.Version) string {
+	for _, v := range versions {
+		if v.RegistryModuleInfo != nil && v.RegistryModuleInfo.Source != "" {
+			return v.RegistryModuleInfo.Source
+		}
+	}
+	return ""
+}
+
+// normalizeVersion strips the "v" prefix used by most release tags
+func normalizeVersion(v string) string {
+	return strings.TrimPrefix(v, "v")
+}
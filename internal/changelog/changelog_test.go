@@ -0,0 +1,138 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+)
+
+const fixturesDir = "../../tests/fixtures/changelog"
+
+// newFixtureServer serves recorded GitHub API responses
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	releases, err := os.ReadFile(filepath.Join(fixturesDir, "github_releases_vpc.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/terraform-aws-modules/terraform-aws-vpc/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(releases)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func loadModuleFixture(t *testing.T) *registry.Module {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(fixturesDir, "registry_vpc_versions.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var module registry.Module
+	if err := json.Unmarshal(data, &module); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	return &module
+}
+
+func TestCollectWithGitHubReleases(t *testing.T) {
+	server := newFixtureServer(t)
+	notes := NewGitHubReleases(server.URL, "test-token")
+
+	entries, err := Collect(context.Background(), loadModuleFixture(t), "4.0.1", "5.1.0", notes)
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	wantVersions := []string{"5.1.0", "5.0.0", "4.0.2"}
+	if len(entries) != len(wantVersions) {
+		t.Fatalf("Collect() = %+v, want versions %v", entries, wantVersions)
+	}
+	for i, v := range wantVersions {
+		if entries[i].Version != v {
+			t.Errorf("entries[%d].Version = %s, want %s", i, entries[i].Version, v)
+		}
+	}
+
+	if !strings.Contains(entries[1].Notes, "BREAKING CHANGES") {
+		t.Errorf("entries[1].Notes = %q, want release notes", entries[1].Notes)
+	}
+	if entries[1].URL != "https://github.com/terraform-aws-modules/terraform-aws-vpc/releases/tag/v5.0.0" {
+		t.Errorf("entries[1].URL = %s, want release URL", entries[1].URL)
+	}
+	if entries[1].PublishedAt != "2023-05-30T09:12:01.456Z" {
+		t.Errorf("entries[1].PublishedAt = %s, want registry publish date", entries[1].PublishedAt)
+	}
+
+	// Versions without a GitHub release keep the registry metadata
+	if entries[2].Notes != "" || entries[2].URL != "https://github.com/terraform-aws-modules/terraform-aws-vpc" {
+		t.Errorf("entries[2] = %+v, want registry metadata only", entries[2])
+	}
+}
+
+func TestCollectRegistryOnly(t *testing.T) {
+	entries, err := Collect(context.Background(), loadModuleFixture(t), "5.0.0", "5.1.0", nil)
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Version != "5.1.0" || entries[0].PublishedAt == "" {
+		t.Errorf("Collect() = %+v, want 5.1.0 from registry metadata", entries)
+	}
+}
+
+func TestGitHubReleasesErrors(t *testing.T) {
+	server := newFixtureServer(t)
+
+	if _, err := NewGitHubReleases(server.URL, "").ReleaseNotes(context.Background(), "https://github.com/terraform-aws-modules/terraform-aws-vpc"); err == nil {
+		t.Error("ReleaseNotes() should fail when the API rejects the request")
+	}
+
+	notes, err := NewGitHubReleases(server.URL, "test-token").ReleaseNotes(context.Background(), "https://gitlab.com/acme/vpc")
+	if err != nil || notes != nil {
+		t.Errorf("ReleaseNotes() for a non-GitHub repository = %v, %v; want nil", notes, err)
+	}
+}
+
+func TestParseGitHubRepository(t *testing.T) {
+	tests := []struct {
+		url   string
+		owner string
+		repo  string
+		ok    bool
+	}{
+		{"https://github.com/terraform-aws-modules/terraform-aws-vpc", "terraform-aws-modules", "terraform-aws-vpc", true},
+		{"git::https://github.com/acme/vpc.git?ref=v1.0.0", "acme", "vpc", true},
+		{"github.com/acme/vpc", "acme", "vpc", true},
+		{"https://gitlab.com/acme/vpc", "", "", false},
+		{"https://github.com/acme", "", "", false},
+	}
+
+	for _, tt := range tests {
+		owner, repo, ok := parseGitHubRepository(tt.url)
+		if owner != tt.owner || repo != tt.repo || ok != tt.ok {
+			t.Errorf("parseGitHubRepository(%q) = %q, %q, %v; want %q, %q, %v", tt.url, owner, repo, ok, tt.owner, tt.repo, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// DefaultGitHubAPI is the GitHub REST API used for release notes
+const DefaultGitHubAPI = "https://api.github.com"
+
+// maxReleasePages bounds the number of release pages fetched per repository
+const maxReleasePages = 5
+
+// GitHubReleases reads release notes from the GitHub releases API
+type GitHubReleases struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// githubRelease is a release returned by the GitHub API
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+}
+
+// NewGitHubReleases creates a release notes source for a GitHub API base URL
+// An empty token performs unauthenticated requests
+func NewGitHubReleases(baseURL, token string) *GitHubReleases {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPI
+	}
+
+	return &GitHubReleases{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{},
+		timeout:    time.Duration(version.RegistryTimeout) * time.Second,
+	}
+}
+
+// ReleaseNotes implements NotesSource for github.com repositories
+func (g *GitHubReleases) ReleaseNotes(ctx context.Context, repositoryURL string) (map[string]Entry, error) {
+	owner, repo, ok := parseGitHubRepository(repositoryURL)
+	if !ok {
+		return nil, nil
+	}
+
+	entries := make(map[string]Entry)
+	for page := 1; page <= maxReleasePages; page++ {
+		releases, err := g.fetchPage(ctx, owner, repo, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range releases {
+			if release.Draft {
+				continue
+			}
+			entries[normalizeVersion(release.TagName)] = Entry{
+				Version:     normalizeVersion(release.TagName),
+				PublishedAt: release.PublishedAt,
+				URL:         release.HTMLURL,
+				Notes:       strings.TrimSpace(release.Body),
+			}
+		}
+
+		if len(releases) < 100 {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+func (g *GitHubReleases) fetchPage(ctx context.Context, owner, repo string, page int) ([]githubRelease, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100&page=%d", g.baseURL, url.PathEscape(owner), url.PathEscape(repo), page)
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxWithTimeout, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("release notes API call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release notes API returned %d for %s/%s", resp.StatusCode, owner, repo)
+	}
+
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	return releases, nil
+}
+
+// parseGitHubRepository extracts owner and repository from a github.com URL
+// Accepts forms such as "https://github.com/o/r", "github.com/o/r.git" and "git::https://github.com/o/r"
+func parseGitHubRepository(repositoryURL string) (string, string, bool) {
+	s := strings.TrimPrefix(repositoryURL, "git::")
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	s = strings.TrimPrefix(s, "www.")
+
+	if !strings.HasPrefix(s, "github.com/") {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(s, "github.com/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	repo := strings.TrimSuffix(strings.SplitN(parts[1], "?", 2)[0], ".git")
+	return parts[0], repo, true
+}
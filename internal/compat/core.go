@@ -0,0 +1,99 @@
+package compat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// CoreChecker evaluates module versions against the Terraform version of the
+// configurations calling them
+type CoreChecker struct {
+	terraformVersion string            // Version set explicitly, overrides required_version
+	callers          map[string]string // Directory -> lowest Terraform version allowed by required_version
+}
+
+// NewCoreChecker creates a checker reading required_version from every directory in dirs
+// When terraformVersion is set, it is used for all directories instead
+func NewCoreChecker(terraformVersion string, dirs []string) (*CoreChecker, error) {
+	if terraformVersion != "" {
+		if _, err := semver.NewVersion(terraformVersion); err != nil {
+			return nil, fmt.Errorf("invalid terraform version %q: %w", terraformVersion, err)
+		}
+	}
+
+	c := &CoreChecker{
+		terraformVersion: terraformVersion,
+		callers:          make(map[string]string),
+	}
+
+	for _, dir := range dirs {
+		required := finder.LoadRequiredCore(dir)
+		if len(required) == 0 {
+			continue
+		}
+		if base, err := version.ConstraintBase(strings.Join(required, ",")); err == nil {
+			c.callers[dir] = base
+		}
+	}
+
+	return c, nil
+}
+
+// Filter returns a version filter rejecting module versions whose required_version
+// excludes the Terraform version of any calling directory
+func (c *CoreChecker) Filter(module *registry.Module, dirs []string) version.Filter {
+	if module == nil {
+		return nil
+	}
+
+	requiredCore := make(map[string][]string)
+	for _, v := range module.Versions {
+		if required := v.RequiredCore(); len(required) > 0 {
+			requiredCore[v.Version] = required
+		}
+	}
+	if len(requiredCore) == 0 {
+		return nil
+	}
+
+	var filters []version.Filter
+	for _, terraformVersion := range c.terraformVersions(dirs) {
+		if filter, err := version.RequiredVersionFilter(terraformVersion, requiredCore); err == nil {
+			filters = append(filters, filter)
+		}
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+
+	return func(candidate string) (bool, string) {
+		for _, filter := range filters {
+			if ok, reason := filter(candidate); !ok {
+				return false, reason
+			}
+		}
+		return true, ""
+	}
+}
+
+// terraformVersions returns the distinct Terraform versions the calling directories run with
+func (c *CoreChecker) terraformVersions(dirs []string) []string {
+	if c.terraformVersion != "" {
+		return []string{c.terraformVersion}
+	}
+
+	seen := make(map[string]bool)
+	var versions []string
+	for _, dir := range dirs {
+		if v, ok := c.callers[dir]; ok && !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
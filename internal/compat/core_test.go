@@ -0,0 +1,73 @@
+package compat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+)
+
+func testCoreModule() *registry.Module {
+	requires := func(constraint string) *registry.ModuleInfo {
+		return &registry.ModuleInfo{Root: &registry.ModuleInfoRoot{RequiredCore: []string{constraint}}}
+	}
+
+	return &registry.Module{Versions: []*registry.Version{
+		{Version: "5.0.0", RegistryModuleInfo: requires(">= 1.5.0")},
+		{Version: "4.2.0", RegistryModuleInfo: requires(">= 1.3.0")},
+		{Version: "4.0.0"},
+	}}
+}
+
+func TestCoreCheckerReadsRequiredVersion(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-compat-core-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `terraform {
+  required_version = "~> 1.3"
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	checker, err := NewCoreChecker("", []string{dir})
+	if err != nil {
+		t.Fatalf("NewCoreChecker() error = %v", err)
+	}
+
+	filter := checker.Filter(testCoreModule(), []string{dir})
+	if filter == nil {
+		t.Fatal("Filter() = nil, want a filter")
+	}
+	if ok, reason := filter("5.0.0"); ok || reason != "requires terraform >= 1.5.0" {
+		t.Errorf("filter(5.0.0) = (%v, %q), want rejection", ok, reason)
+	}
+	if ok, _ := filter("4.2.0"); !ok {
+		t.Error("filter(4.2.0) should accept a compatible version")
+	}
+
+	// Directories without required_version are not checked
+	if filter := checker.Filter(testCoreModule(), []string{t.TempDir()}); filter != nil {
+		t.Error("Filter() should be nil without a known terraform version")
+	}
+}
+
+func TestCoreCheckerTerraformVersionOverride(t *testing.T) {
+	checker, err := NewCoreChecker("1.6.2", nil)
+	if err != nil {
+		t.Fatalf("NewCoreChecker() error = %v", err)
+	}
+
+	if ok, reason := checker.Filter(testCoreModule(), []string{"any"})("5.0.0"); !ok {
+		t.Errorf("filter(5.0.0) rejected with %q, want accepted on 1.6.2", reason)
+	}
+
+	if _, err := NewCoreChecker("latest", nil); err == nil {
+		t.Error("NewCoreChecker() should reject an invalid terraform version")
+	}
+}
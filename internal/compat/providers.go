@@ -0,0 +1,150 @@
+package compat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// callerProviders holds the provider versions a calling configuration runs with
+type callerProviders struct {
+	constraints map[string]string // Provider source -> required_providers constraint
+	locked      map[string]string // Provider source -> version from .terraform.lock.hcl
+}
+
+// ProviderChecker evaluates module versions against the provider requirements
+// of the configurations calling them
+type ProviderChecker struct {
+	callers map[string]*callerProviders // Directory -> providers
+}
+
+// NewProviderChecker creates a checker from required_providers entries
+// Lock files are read for every directory passed in dirs
+func NewProviderChecker(requirements []finder.ProviderUsage, dirs []string) *ProviderChecker {
+	c := &ProviderChecker{callers: make(map[string]*callerProviders)}
+
+	for _, req := range requirements {
+		c.caller(req.FilePath).constraints[req.Source] = req.Constraint
+	}
+
+	for _, dir := range dirs {
+		caller := c.caller(dir)
+		for src, v := range finder.LoadLockedProviders(dir) {
+			caller.locked[src] = v
+		}
+	}
+
+	return c
+}
+
+func (c *ProviderChecker) caller(dir string) *callerProviders {
+	if _, ok := c.callers[dir]; !ok {
+		c.callers[dir] = &callerProviders{
+			constraints: make(map[string]string),
+			locked:      make(map[string]string),
+		}
+	}
+	return c.callers[dir]
+}
+
+// Filter returns a version filter rejecting module versions that would force a
+// provider major upgrade in any of the calling directories
+func (c *ProviderChecker) Filter(module *registry.Module, dirs []string) version.Filter {
+	if module == nil {
+		return nil
+	}
+
+	byVersion := make(map[string]*registry.Version)
+	for _, v := range module.Versions {
+		if parsed, err := semver.NewVersion(v.Version); err == nil {
+			byVersion[parsed.String()] = v
+		}
+		byVersion[v.Version] = v
+	}
+
+	return func(candidate string) (bool, string) {
+		v, ok := byVersion[candidate]
+		if !ok {
+			return true, ""
+		}
+
+		for _, req := range v.Root.Providers {
+			if req.Version == "" {
+				continue
+			}
+			providerSource := requirementSource(req)
+
+			for _, dir := range dirs {
+				current := c.currentVersion(dir, providerSource)
+				if current == nil {
+					continue
+				}
+				if forcesMajorUpgrade(req.Version, current) {
+					return false, fmt.Sprintf("requires %s %s", req.Name, req.Version)
+				}
+			}
+		}
+
+		return true, ""
+	}
+}
+
+// currentVersion returns the provider version a directory runs with: the locked
+// version when known, otherwise the lower bound of its required_providers constraint
+func (c *ProviderChecker) currentVersion(dir, providerSource string) *semver.Version {
+	caller, ok := c.callers[dir]
+	if !ok {
+		return nil
+	}
+
+	if locked, ok := caller.locked[providerSource]; ok {
+		if v, err := semver.NewVersion(locked); err == nil {
+			return v
+		}
+	}
+
+	if constraint, ok := caller.constraints[providerSource]; ok {
+		if base, err := version.ConstraintBase(constraint); err == nil {
+			if v, err := semver.NewVersion(base); err == nil {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+// forcesMajorUpgrade reports whether a provider requirement cannot be met without
+// moving the provider to a later major version than current
+func forcesMajorUpgrade(requirement string, current *semver.Version) bool {
+	if constraints, err := version.ParseConstraints(requirement); err == nil && constraints.Matches(current) {
+		return false
+	}
+
+	base, err := version.ConstraintBase(requirement)
+	if err != nil {
+		return false
+	}
+	minimum, err := semver.NewVersion(base)
+	if err != nil {
+		return false
+	}
+
+	return minimum.Major() > current.Major()
+}
+
+// requirementSource returns the normalized provider source of a registry requirement
+func requirementSource(req registry.Provider) string {
+	switch {
+	case req.Source != "":
+		return finder.NormalizeProviderSource(req.Source)
+	case req.Namespace != "":
+		return finder.NormalizeProviderSource(req.Namespace + "/" + req.Name)
+	default:
+		return "hashicorp/" + strings.ToLower(req.Name)
+	}
+}
@@ -0,0 +1,108 @@
+package compat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+func testModule() *registry.Module {
+	requires := func(constraint string) registry.RootModule {
+		return registry.RootModule{Providers: []registry.Provider{
+			{Name: "aws", Namespace: "hashicorp", Source: "hashicorp/aws", Version: constraint},
+		}}
+	}
+
+	return &registry.Module{Versions: []*registry.Version{
+		{Version: "5.0.0", Root: requires(">= 5.0")},
+		{Version: "4.2.0", Root: requires(">= 4.0")},
+		{Version: "4.0.0", Root: requires(">= 3.70")},
+	}}
+}
+
+func TestProviderCheckerFilter(t *testing.T) {
+	requirements := []finder.ProviderUsage{
+		{Name: "aws", Source: "hashicorp/aws", Constraint: "~> 4.0", FilePath: "envs/prod"},
+		{Name: "aws", Source: "hashicorp/aws", Constraint: "~> 5.1", FilePath: "envs/dev"},
+	}
+	checker := NewProviderChecker(requirements, nil)
+
+	tests := []struct {
+		name       string
+		dirs       []string
+		candidate  string
+		wantOK     bool
+		wantReason string
+	}{
+		{"provider major upgrade", []string{"envs/prod"}, "5.0.0", false, "requires aws >= 5.0"},
+		{"same major", []string{"envs/prod"}, "4.2.0", true, ""},
+		{"caller already upgraded", []string{"envs/dev"}, "5.0.0", true, ""},
+		{"any caller held back", []string{"envs/dev", "envs/prod"}, "5.0.0", false, "requires aws >= 5.0"},
+		{"unknown caller", []string{"envs/test"}, "5.0.0", true, ""},
+		{"unknown version", []string{"envs/prod"}, "6.0.0", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := checker.Filter(testModule(), tt.dirs)(tt.candidate)
+			if ok != tt.wantOK || reason != tt.wantReason {
+				t.Errorf("Filter(%s) = (%v, %q), want (%v, %q)", tt.candidate, ok, reason, tt.wantOK, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestProviderCheckerPrefersLockFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-compat-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	lock := `provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(lock), 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	// The constraint alone would allow 4.x, but the lock file already selected 5.x
+	requirements := []finder.ProviderUsage{
+		{Name: "aws", Source: "hashicorp/aws", Constraint: ">= 4.0", FilePath: dir},
+	}
+	checker := NewProviderChecker(requirements, []string{dir})
+
+	if ok, reason := checker.Filter(testModule(), []string{dir})("5.0.0"); !ok {
+		t.Errorf("Filter(5.0.0) rejected with %q, want accepted", reason)
+	}
+}
+
+func TestProviderCheckerWithSelectVersion(t *testing.T) {
+	requirements := []finder.ProviderUsage{
+		{Name: "aws", Source: "hashicorp/aws", Constraint: "~> 4.0", FilePath: "."},
+	}
+	checker := NewProviderChecker(requirements, nil)
+
+	selected, exclusions, err := version.SelectVersionWithExclusions(
+		"4.0.0",
+		[]string{"5.0.0", "4.2.0", "4.0.0"},
+		version.StrategyLatest,
+		nil,
+		checker.Filter(testModule(), []string{"."}),
+	)
+	if err != nil {
+		t.Fatalf("SelectVersionWithExclusions returned error: %v", err)
+	}
+
+	if selected != "4.2.0" {
+		t.Errorf("selected = %s, want 4.2.0", selected)
+	}
+	if len(exclusions) != 1 || exclusions[0].Version != "5.0.0" || exclusions[0].Reason != "requires aws >= 5.0" {
+		t.Errorf("exclusions = %v, want 5.0.0 requires aws >= 5.0", exclusions)
+	}
+}
@@ -0,0 +1,115 @@
+package finder
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// moduleMetaArguments are module block arguments that are not module inputs
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"providers":  true,
+	"count":      true,
+	"for_each":   true,
+	"depends_on": true,
+}
+
+// ModuleCall describes how a configuration uses one module block
+type ModuleCall struct {
+	Name              string   // Module block name
+	Arguments         []string // Input arguments set in the block, sorted
+	ReferencedOutputs []string // Outputs referenced as module.<name>.<output>, sorted
+}
+
+// LoadModuleCalls parses the .tf files of a directory and returns its module calls by block name
+func LoadModuleCalls(dir string) map[string]*ModuleCall {
+	calls := make(map[string]*ModuleCall)
+	outputs := make(map[string]map[string]bool)
+
+	parser := hclparse.NewParser()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	sort.Strings(files)
+
+	for _, path := range files {
+		body := parseBody(parser, path)
+		if body == nil {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "module" || len(block.Labels) != 1 {
+				continue
+			}
+
+			call := &ModuleCall{Name: block.Labels[0]}
+			for name := range block.Body.Attributes {
+				if !moduleMetaArguments[name] {
+					call.Arguments = append(call.Arguments, name)
+				}
+			}
+			sort.Strings(call.Arguments)
+			calls[call.Name] = call
+		}
+
+		// Collect module.<name>.<output> references anywhere in the file
+		hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+			if !ok {
+				return nil
+			}
+			name, output, ok := moduleOutputTraversal(expr.Traversal)
+			if !ok {
+				return nil
+			}
+			if outputs[name] == nil {
+				outputs[name] = make(map[string]bool)
+			}
+			outputs[name][output] = true
+			return nil
+		})
+	}
+
+	for name, referenced := range outputs {
+		call, ok := calls[name]
+		if !ok {
+			continue
+		}
+		for output := range referenced {
+			call.ReferencedOutputs = append(call.ReferencedOutputs, output)
+		}
+		sort.Strings(call.ReferencedOutputs)
+	}
+
+	return calls
+}
+
+// moduleOutputTraversal extracts the block and output names from a module.<name>.<output> traversal
+// Indexed calls such as module.<name>[0].<output> are supported
+func moduleOutputTraversal(traversal hcl.Traversal) (string, string, bool) {
+	if len(traversal) < 3 || traversal.RootName() != "module" {
+		return "", "", false
+	}
+
+	nameStep, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", "", false
+	}
+
+	for _, step := range traversal[2:] {
+		switch s := step.(type) {
+		case hcl.TraverseIndex, hcl.TraverseSplat:
+			continue
+		case hcl.TraverseAttr:
+			return nameStep.Name, s.Name, true
+		default:
+			return "", "", false
+		}
+	}
+
+	return "", "", false
+}
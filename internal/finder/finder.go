@@ -9,7 +9,8 @@ import (
 )
 
 // FindModulesWithVersions recursively finds all Terraform modules with explicit version constraints
-// Only returns modules that have a version attribute specified
+// Only returns modules that have a version attribute specified, either as a literal
+// or as a reference to a literal local or variable defined in the same directory
 // If filter is provided, only returns modules matching the filter criteria
 func FindModulesWithVersions(root string, moduleFilter *filter.ModuleFilter) ([]ModuleWithPath, error) {
 	var results []ModuleWithPath
@@ -27,10 +28,24 @@ func FindModulesWithVersions(root string, moduleFilter *filter.ModuleFilter) ([]
 		// Load the terraform module configuration for this directory
 		module, _ := tfconfig.LoadModule(path)
 
+		// Parsed for version positions and references
+		var values *directoryValues
+		if len(module.ModuleCalls) > 0 {
+			values = loadDirectoryValues(path)
+		}
+
 		// Extract module calls with version constraints
 		for _, call := range module.ModuleCalls {
+			version := call.Version
+			var ref *VersionRef
+
+			// Resolve versions that reference a local or variable
+			if version == "" {
+				version, ref = values.resolveVersionRef(call.Name)
+			}
+
 			// Only include modules with explicit version specified
-			if call.Version == "" {
+			if version == "" {
 				continue
 			}
 
@@ -45,10 +60,14 @@ func FindModulesWithVersions(root string, moduleFilter *filter.ModuleFilter) ([]
 			results = append(results, ModuleWithPath{
 				FilePath: path,
 				Usage: ModuleUsage{
-					Source:    call.Source,
-					Version:   call.Version,
-					FilePath:  path,
-					BlockName: call.Name,
+					Source:       call.Source,
+					Version:      version,
+					FilePath:     path,
+					BlockName:    call.Name,
+					BlockFile:    call.Pos.Filename,
+					BlockLine:    call.Pos.Line,
+					VersionRange: values.ranges[call.Name],
+					VersionRef:   ref,
 				},
 			})
 		}
@@ -82,6 +101,8 @@ func FindAllModules(root string) ([]ModuleWithPath, error) {
 					Version:   call.Version,
 					FilePath:  path,
 					BlockName: call.Name,
+					BlockFile: call.Pos.Filename,
+					BlockLine: call.Pos.Line,
 				},
 			})
 		}
@@ -91,3 +112,41 @@ func FindAllModules(root string) ([]ModuleWithPath, error) {
 
 	return results, err
 }
+
+// FindUnpinnedModules recursively finds module calls without any version attribute
+// Calls whose version is an expression (local, variable, function) are not considered unpinned
+// If filter is provided, only returns modules matching the filter criteria
+func FindUnpinnedModules(root string, moduleFilter *filter.ModuleFilter) ([]ModuleWithPath, error) {
+	all, err := FindAllModules(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ModuleWithPath
+	values := make(map[string]*directoryValues)
+
+	for _, mod := range all {
+		if mod.Usage.Version != "" {
+			continue
+		}
+
+		dirValues, ok := values[mod.FilePath]
+		if !ok {
+			dirValues = loadDirectoryValues(mod.FilePath)
+			values[mod.FilePath] = dirValues
+		}
+		if dirValues.versioned[mod.Usage.BlockName] {
+			continue
+		}
+
+		if moduleFilter != nil {
+			if _, matches := moduleFilter.GetVersionStrategy(mod.Usage.Source); !matches {
+				continue
+			}
+		}
+
+		results = append(results, mod)
+	}
+
+	return results, nil
+}
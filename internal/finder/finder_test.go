@@ -2,6 +2,7 @@ package finder
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -38,3 +39,283 @@ func TestFindAllModules(t *testing.T) {
 		t.Fatalf("expected 0 modules in empty dir, got %d", len(mods))
 	}
 }
+
+func TestFindModulesWithVersionsResolvesReferences(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-finder-refs-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"main.tf": `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = local.vpc_version
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = var.eks_module_version
+}
+
+module "computed" {
+  source  = "terraform-aws-modules/iam/aws"
+  version = var.unknown
+}
+`,
+		"locals.tf": `locals {
+  vpc_version = "5.1.0"
+}
+`,
+		"variables.tf": `variable "eks_module_version" {
+  type    = string
+  default = "19.0.0"
+}
+`,
+		"terraform.tfvars": `eks_module_version = "19.5.1"
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	mods, err := FindModulesWithVersions(dir, nil)
+	if err != nil {
+		t.Fatalf("FindModulesWithVersions returned error: %v", err)
+	}
+
+	if len(mods) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(mods))
+	}
+
+	bySource := make(map[string]ModuleUsage)
+	for _, m := range mods {
+		bySource[m.Usage.Source] = m.Usage
+	}
+
+	vpc := bySource["terraform-aws-modules/vpc/aws"]
+	if vpc.Version != "5.1.0" {
+		t.Errorf("vpc version = %q, want 5.1.0", vpc.Version)
+	}
+	if vpc.VersionRef == nil || vpc.VersionRef.String() != "local.vpc_version" {
+		t.Fatalf("vpc VersionRef = %v, want local.vpc_version", vpc.VersionRef)
+	}
+	if filepath.Base(vpc.VersionRef.FilePath) != "locals.tf" || vpc.VersionRef.Line != 2 {
+		t.Errorf("vpc defined at %s:%d, want locals.tf:2", vpc.VersionRef.FilePath, vpc.VersionRef.Line)
+	}
+
+	eks := bySource["terraform-aws-modules/eks/aws"]
+	if eks.Version != "19.5.1" {
+		t.Errorf("eks version = %q, want tfvars value 19.5.1", eks.Version)
+	}
+	if eks.VersionRef == nil || filepath.Base(eks.VersionRef.FilePath) != "terraform.tfvars" {
+		t.Errorf("eks VersionRef = %v, want definition in terraform.tfvars", eks.VersionRef)
+	}
+}
+
+func TestFindUnpinnedModules(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-finder-unpinned-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `module "unpinned" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+
+module "pinned" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+
+module "computed" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = var.eks_version
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	mods, err := FindUnpinnedModules(dir, nil)
+	if err != nil {
+		t.Fatalf("FindUnpinnedModules returned error: %v", err)
+	}
+
+	if len(mods) != 1 {
+		t.Fatalf("expected 1 unpinned module, got %d", len(mods))
+	}
+
+	usage := mods[0].Usage
+	if usage.BlockName != "unpinned" {
+		t.Errorf("BlockName = %q, want unpinned", usage.BlockName)
+	}
+	if filepath.Base(usage.BlockFile) != "main.tf" || usage.BlockLine != 1 {
+		t.Errorf("position = %s:%d, want main.tf:1", usage.BlockFile, usage.BlockLine)
+	}
+}
+
+func TestFindModulesWithVersionsRange(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-finder-range-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	mods, err := FindModulesWithVersions(dir, nil)
+	if err != nil {
+		t.Fatalf("FindModulesWithVersions returned error: %v", err)
+	}
+	if len(mods) != 1 {
+		t.Fatalf("expected 1 module, got %d", len(mods))
+	}
+
+	want := Range{StartLine: 3, StartColumn: 13, EndLine: 3, EndColumn: 20}
+	if rng := mods[0].Usage.VersionRange; rng == nil || *rng != want {
+		t.Errorf("VersionRange = %+v, want %+v", rng, want)
+	}
+}
+
+func TestFindProviderRequirements(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-finder-providers-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `terraform {
+  required_providers {
+    aws = {
+      source  = "registry.terraform.io/hashicorp/aws"
+      version = "~> 5.0"
+    }
+    random = "~> 3.1"
+    unversioned = {
+      source = "acme/unversioned"
+    }
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write versions.tf: %v", err)
+	}
+
+	providers, err := FindProviderRequirements(dir)
+	if err != nil {
+		t.Fatalf("FindProviderRequirements returned error: %v", err)
+	}
+
+	if len(providers) != 2 {
+		t.Fatalf("expected 2 provider requirements, got %d", len(providers))
+	}
+
+	aws := providers[0]
+	if aws.Name != "aws" || aws.Source != "hashicorp/aws" || aws.Constraint != "~> 5.0" || aws.Line != 3 {
+		t.Errorf("aws requirement = %+v", aws)
+	}
+
+	random := providers[1]
+	if random.Source != "hashicorp/random" || random.Constraint != "~> 3.1" {
+		t.Errorf("random requirement = %+v", random)
+	}
+}
+
+func TestLoadLockedProviders(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-finder-lock-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `provider "registry.terraform.io/hashicorp/aws" {
+  version     = "4.67.0"
+  constraints = "~> 4.0"
+  hashes = [
+    "h1:abc=",
+  ]
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	locked := LoadLockedProviders(dir)
+	if locked["hashicorp/aws"] != "4.67.0" {
+		t.Errorf("LoadLockedProviders() = %v, want hashicorp/aws at 4.67.0", locked)
+	}
+
+	if len(LoadLockedProviders(t.TempDir())) != 0 {
+		t.Error("LoadLockedProviders() should be empty without a lock file")
+	}
+}
+
+func TestLoadModuleCalls(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-finder-calls-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"main.tf": `module "vpc" {
+  source     = "terraform-aws-modules/vpc/aws"
+  version    = "3.19.0"
+  count      = 1
+  name       = "main"
+  cidr       = "10.0.0.0/16"
+  enable_nat = true
+}
+`,
+		"outputs.tf": `output "vpc_id" {
+  value = module.vpc[0].vpc_id
+}
+
+resource "aws_route" "default" {
+  route_table_id = module.vpc[0].private_route_table_ids[0]
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	calls := LoadModuleCalls(dir)
+	call, ok := calls["vpc"]
+	if !ok {
+		t.Fatalf("LoadModuleCalls() = %v, want module vpc", calls)
+	}
+
+	wantArgs := []string{"cidr", "enable_nat", "name"}
+	if len(call.Arguments) != len(wantArgs) {
+		t.Fatalf("Arguments = %v, want %v", call.Arguments, wantArgs)
+	}
+	for i, arg := range wantArgs {
+		if call.Arguments[i] != arg {
+			t.Errorf("Arguments[%d] = %s, want %s", i, call.Arguments[i], arg)
+		}
+	}
+
+	wantOutputs := []string{"private_route_table_ids", "vpc_id"}
+	if len(call.ReferencedOutputs) != len(wantOutputs) {
+		t.Fatalf("ReferencedOutputs = %v, want %v", call.ReferencedOutputs, wantOutputs)
+	}
+	for i, output := range wantOutputs {
+		if call.ReferencedOutputs[i] != output {
+			t.Errorf("ReferencedOutputs[%d] = %s, want %s", i, call.ReferencedOutputs[i], output)
+		}
+	}
+}
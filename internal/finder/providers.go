@@ -0,0 +1,170 @@
+package finder
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+// ProviderUsage represents a required_providers entry in a Terraform configuration
+type ProviderUsage struct {
+	Name       string // Local name, e.g., "aws"
+	Source     string // Provider source, e.g., "hashicorp/aws"
+	Constraint string // Version constraint, e.g., "~> 5.0"
+	FilePath   string // Directory containing the configuration
+	File       string // .tf file declaring the requirement
+	Line       int    // Line of the requirement entry
+}
+
+// FindProviderRequirements recursively finds all required_providers entries with a version constraint
+// Directories are discovered through tfconfig; entries are located with the HCL parser so that
+// each constraint can be reported and rewritten at its exact position
+func FindProviderRequirements(root string) ([]ProviderUsage, error) {
+	var results []ProviderUsage
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		module, _ := tfconfig.LoadModule(path)
+		if len(module.RequiredProviders) == 0 {
+			return nil
+		}
+
+		results = append(results, providerRequirementsInDir(path)...)
+		return nil
+	})
+
+	return results, err
+}
+
+// providerRequirementsInDir parses terraform { required_providers { ... } } blocks of one directory
+func providerRequirementsInDir(dir string) []ProviderUsage {
+	var results []ProviderUsage
+
+	parser := hclparse.NewParser()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	sort.Strings(files)
+
+	for _, path := range files {
+		body := parseBody(parser, path)
+		if body == nil {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "terraform" {
+				continue
+			}
+			for _, inner := range block.Body.Blocks {
+				if inner.Type != "required_providers" {
+					continue
+				}
+
+				names := make([]string, 0, len(inner.Body.Attributes))
+				for name := range inner.Body.Attributes {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				for _, name := range names {
+					attr := inner.Body.Attributes[name]
+					source, constraint := decodeProviderRequirement(attr.Expr)
+					if constraint == "" {
+						continue
+					}
+					if source == "" {
+						source = "hashicorp/" + name
+					}
+
+					results = append(results, ProviderUsage{
+						Name:       name,
+						Source:     NormalizeProviderSource(source),
+						Constraint: constraint,
+						FilePath:   dir,
+						File:       path,
+						Line:       attr.SrcRange.Start.Line,
+					})
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// decodeProviderRequirement reads source and version from a required_providers entry
+// Supports both the object form and the legacy `aws = "~> 5.0"` string form
+func decodeProviderRequirement(expr hclsyntax.Expression) (string, string) {
+	if value, ok := literalString(expr); ok {
+		return "", value
+	}
+
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return "", ""
+	}
+
+	var source, constraint string
+	for _, item := range object.Items {
+		switch hcl.ExprAsKeyword(item.KeyExpr) {
+		case "source":
+			source, _ = literalString(item.ValueExpr)
+		case "version":
+			constraint, _ = literalString(item.ValueExpr)
+		}
+	}
+
+	return source, constraint
+}
+
+// NormalizeProviderSource strips the default registry host from a provider source
+// Example: "registry.terraform.io/hashicorp/aws" becomes "hashicorp/aws"
+func NormalizeProviderSource(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	return strings.TrimPrefix(source, "registry.terraform.io/")
+}
+
+// LoadLockedProviders reads provider versions selected in a directory's .terraform.lock.hcl
+// Returns a map of normalized provider source to locked version, empty if there is no lock file
+func LoadLockedProviders(dir string) map[string]string {
+	locked := make(map[string]string)
+
+	body := parseBody(hclparse.NewParser(), filepath.Join(dir, ".terraform.lock.hcl"))
+	if body == nil {
+		return locked
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != "provider" || len(block.Labels) != 1 {
+			continue
+		}
+		if attr, ok := block.Body.Attributes["version"]; ok {
+			if version, ok := literalString(attr.Expr); ok {
+				locked[NormalizeProviderSource(block.Labels[0])] = version
+			}
+		}
+	}
+
+	return locked
+}
+
+// LoadRequiredCore returns the required_version constraints declared in a directory
+func LoadRequiredCore(dir string) []string {
+	module, _ := tfconfig.LoadModule(dir)
+	if module == nil {
+		return nil
+	}
+	return module.RequiredCore
+}
@@ -0,0 +1,211 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Reference kinds for module versions defined outside the module block
+const (
+	RefKindLocal    = "local"
+	RefKindVariable = "var"
+)
+
+// definition is a literal string value and the place it is defined
+type definition struct {
+	value    string
+	filePath string
+	line     int
+}
+
+// directoryValues holds the literal locals and effective variable values of one directory
+type directoryValues struct {
+	locals    map[string]definition
+	variables map[string]definition
+	refs      map[string]hcl.Traversal // module block name -> version traversal
+	versioned map[string]bool          // module block names with a version attribute
+	ranges    map[string]*Range        // module block name -> version value range
+}
+
+// resolveVersionRef resolves a module version expressed as local.X or var.X
+// Returns nil when the block does not reference a resolvable literal value
+func (d *directoryValues) resolveVersionRef(blockName string) (string, *VersionRef) {
+	traversal, ok := d.refs[blockName]
+	if !ok {
+		return "", nil
+	}
+
+	kind, name, ok := splitTraversal(traversal)
+	if !ok {
+		return "", nil
+	}
+
+	var values map[string]definition
+	switch kind {
+	case RefKindLocal:
+		values = d.locals
+	case RefKindVariable:
+		values = d.variables
+	default:
+		return "", nil
+	}
+
+	def, ok := values[name]
+	if !ok || def.value == "" {
+		return "", nil
+	}
+
+	return def.value, &VersionRef{
+		Kind:     kind,
+		Name:     name,
+		FilePath: def.filePath,
+		Line:     def.line,
+	}
+}
+
+// loadDirectoryValues parses the .tf and .tfvars files of a single directory
+// Only literal string values are recorded; anything requiring evaluation is ignored
+func loadDirectoryValues(dir string) *directoryValues {
+	values := &directoryValues{
+		locals:    make(map[string]definition),
+		variables: make(map[string]definition),
+		refs:      make(map[string]hcl.Traversal),
+		versioned: make(map[string]bool),
+		ranges:    make(map[string]*Range),
+	}
+
+	parser := hclparse.NewParser()
+
+	tfFiles, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	sort.Strings(tfFiles)
+	for _, path := range tfFiles {
+		body := parseBody(parser, path)
+		if body == nil {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			switch block.Type {
+			case "module":
+				if len(block.Labels) != 1 {
+					continue
+				}
+				if attr, ok := block.Body.Attributes["version"]; ok {
+					values.versioned[block.Labels[0]] = true
+					values.ranges[block.Labels[0]] = newRange(attr.Expr.Range())
+					if traversal, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr); ok {
+						values.refs[block.Labels[0]] = traversal.Traversal
+					}
+				}
+			case "locals":
+				for name, attr := range block.Body.Attributes {
+					if value, ok := literalString(attr.Expr); ok {
+						values.locals[name] = definition{value: value, filePath: path, line: attr.SrcRange.Start.Line}
+					}
+				}
+			case "variable":
+				if len(block.Labels) != 1 {
+					continue
+				}
+				if attr, ok := block.Body.Attributes["default"]; ok {
+					if value, ok := literalString(attr.Expr); ok {
+						values.variables[block.Labels[0]] = definition{value: value, filePath: path, line: attr.SrcRange.Start.Line}
+					}
+				}
+			}
+		}
+	}
+
+	// Variable files override defaults in the same order Terraform loads them
+	for _, path := range variableFiles(dir) {
+		body := parseBody(parser, path)
+		if body == nil {
+			continue
+		}
+
+		for name, attr := range body.Attributes {
+			if value, ok := literalString(attr.Expr); ok {
+				values.variables[name] = definition{value: value, filePath: path, line: attr.SrcRange.Start.Line}
+			}
+		}
+	}
+
+	return values
+}
+
+// newRange converts an HCL range
+func newRange(rng hcl.Range) *Range {
+	return &Range{
+		StartLine:   rng.Start.Line,
+		StartColumn: rng.Start.Column,
+		EndLine:     rng.End.Line,
+		EndColumn:   rng.End.Column,
+	}
+}
+
+// variableFiles returns terraform.tfvars followed by *.auto.tfvars in lexical order
+func variableFiles(dir string) []string {
+	var files []string
+
+	defaultFile := filepath.Join(dir, "terraform.tfvars")
+	if info, err := os.Stat(defaultFile); err == nil && !info.IsDir() {
+		files = append(files, defaultFile)
+	}
+
+	autoFiles, _ := filepath.Glob(filepath.Join(dir, "*.auto.tfvars"))
+	sort.Strings(autoFiles)
+
+	return append(files, autoFiles...)
+}
+
+// parseBody parses an HCL native syntax file, returning nil on failure
+func parseBody(parser *hclparse.Parser, path string) *hclsyntax.Body {
+	file, diags := parser.ParseHCLFile(path)
+	if diags.HasErrors() || file == nil {
+		return nil
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	return body
+}
+
+// literalString returns the value of an expression that is a plain string literal
+func literalString(expr hclsyntax.Expression) (string, bool) {
+	if len(expr.Variables()) > 0 {
+		return "", false
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return "", false
+	}
+
+	return strings.TrimSpace(value.AsString()), true
+}
+
+// splitTraversal extracts the kind and name from a local.X or var.X traversal
+func splitTraversal(traversal hcl.Traversal) (string, string, bool) {
+	if len(traversal) != 2 {
+		return "", "", false
+	}
+
+	root, ok := traversal[0].(hcl.TraverseRoot)
+	if !ok {
+		return "", "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", "", false
+	}
+
+	return root.Name, attr.Name, true
+}
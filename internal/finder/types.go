@@ -6,6 +6,36 @@ type ModuleUsage struct {
 	Version   string // e.g., "0.1.3"
 	FilePath  string // Absolute path to the .tf file
 	BlockName string // Module block name, e.g., "example" from module "example"
+	BlockFile string // .tf file declaring the module block
+	BlockLine int    // Line of the module block in BlockFile
+
+	// VersionRange locates the version attribute's value in BlockFile, nil if unknown
+	VersionRange *Range
+
+	// VersionRef is set when the version comes from a local or variable
+	// rather than a literal in the module block
+	VersionRef *VersionRef
+}
+
+// VersionRef describes where a module version referenced as local.X or var.X is defined
+type VersionRef struct {
+	Kind     string // RefKindLocal or RefKindVariable
+	Name     string // Local or variable name, e.g., "vpc_version"
+	FilePath string // File holding the definition (.tf or .tfvars)
+	Line     int    // Line of the defining attribute
+}
+
+// String returns the reference expression, e.g., "local.vpc_version"
+func (r *VersionRef) String() string {
+	return r.Kind + "." + r.Name
+}
+
+// Range is a span of source text; columns are 1-based and End is exclusive
+type Range struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
 }
 
 // ModuleWithPath is a convenience type combining a file path with module usage
@@ -0,0 +1,160 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// apiClient sends JSON requests to a forge REST API
+type apiClient struct {
+	baseURL    string
+	header     http.Header
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+func newAPIClient(baseURL string, header http.Header) *apiClient {
+	return &apiClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		header:     header,
+		httpClient: &http.Client{},
+		timeout:    time.Duration(version.RegistryTimeout) * time.Second,
+	}
+}
+
+// maxPages bounds the pages read from a list endpoint
+const maxPages = 50
+
+// do sends in as the JSON body of a request to path and decodes the response into out
+// in and out may be nil
+func (c *apiClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	_, err := c.send(ctx, method, c.baseURL+path, in, out)
+	return err
+}
+
+// list reads every page of a list endpoint, passing the JSON array of each page to add
+// Pages are followed through the Link header (rel="next"), or GitLab's X-Next-Page
+func (c *apiClient) list(ctx context.Context, path string, add func(page json.RawMessage) error) error {
+	target := c.baseURL + path
+	for pages := 0; target != ""; pages++ {
+		if pages == maxPages {
+			return fmt.Errorf("GET %s: more than %d pages", path, maxPages)
+		}
+
+		var page json.RawMessage
+		header, err := c.send(ctx, "GET", target, nil, &page)
+		if err != nil {
+			return err
+		}
+		if err := add(page); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", path, err)
+		}
+
+		target, err = c.nextPage(target, header)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextPage returns the URL of the page after current, empty on the last page
+// Only URLs of the same API are followed, as requests carry the token
+func (c *apiClient) nextPage(current string, header http.Header) (string, error) {
+	next := linkNext(header.Get("Link"))
+	if next == "" {
+		page := header.Get("X-Next-Page")
+		if page == "" {
+			return "", nil
+		}
+		u, err := url.Parse(current)
+		if err != nil {
+			return "", err
+		}
+		query := u.Query()
+		query.Set("page", page)
+		u.RawQuery = query.Encode()
+		next = u.String()
+	}
+
+	if !strings.HasPrefix(next, c.baseURL+"/") {
+		return "", fmt.Errorf("next page %s is outside of %s", next, c.baseURL)
+	}
+	return next, nil
+}
+
+// linkNext returns the rel="next" URL of a Link header
+func linkNext(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		fields := strings.Split(part, ";")
+		target := strings.Trim(strings.TrimSpace(fields[0]), "<>")
+		for _, param := range fields[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+// send sends in as the JSON body of a request to target and decodes the response into out
+// Returns the response headers
+func (c *apiClient) send(ctx context.Context, method, target string, in, out interface{}) (http.Header, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxWithTimeout, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range c.header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	// Forges may ask for their own media type, e.g. GitHub's application/vnd.github+json
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	path := strings.TrimPrefix(target, c.baseURL)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return resp.Header, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", path, err)
+	}
+	return resp.Header, nil
+}
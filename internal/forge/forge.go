@@ -0,0 +1,214 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Supported forges
+const (
+	KindGitHub = "github"
+	KindGitLab = "gitlab"
+	KindGitea  = "gitea"
+)
+
+// PullRequest is a pull request (merge request on GitLab) between two branches
+type PullRequest struct {
+	Number int    // Pull request number, the merge request IID on GitLab
+	URL    string // Web page of the pull request
+	Head   string // Branch with the changes
+	Base   string // Branch the changes are merged into
+	Title  string
+	Body   string
+}
+
+// Forge is the pull request API of a hosted repository
+type Forge interface {
+	// ListOpen returns the open pull requests of the repository
+	ListOpen(ctx context.Context) ([]PullRequest, error)
+	// Create opens a pull request from pr.Head into pr.Base
+	Create(ctx context.Context, pr PullRequest) (*PullRequest, error)
+	// Update replaces the title and body of pull request pr.Number
+	Update(ctx context.Context, pr PullRequest) (*PullRequest, error)
+	// AddLabels adds labels to a pull request
+	AddLabels(ctx context.Context, number int, labels []string) error
+	// RequestReviewers asks users to review a pull request
+	RequestReviewers(ctx context.Context, number int, reviewers []string) error
+}
+
+// New returns the forge API of a repository, e.g., "owner/name"
+// An empty baseURL uses the public API of GitHub or GitLab
+func New(kind, baseURL, repository, token string) (Forge, error) {
+	if strings.Count(repository, "/") < 1 {
+		return nil, fmt.Errorf("invalid repository %q: must be 'owner/name'", repository)
+	}
+
+	switch kind {
+	case KindGitHub:
+		return NewGitHub(baseURL, repository, token), nil
+	case KindGitLab:
+		return NewGitLab(baseURL, repository, token), nil
+	case KindGitea:
+		if baseURL == "" {
+			return nil, fmt.Errorf("gitea requires an API URL")
+		}
+		return NewGitea(baseURL, repository, token), nil
+	default:
+		return nil, fmt.Errorf("invalid forge %q: must be 'github', 'gitlab' or 'gitea'", kind)
+	}
+}
+
+// Request describes the pull request to open for a branch
+type Request struct {
+	Head      string
+	Base      string
+	Title     string
+	Body      string
+	Group     string   // Update group, used to find a pull request opened from another branch
+	Labels    []string // Labels added to the pull request
+	Reviewers []string // Reviewers requested on new pull requests
+}
+
+// Existing returns the open pull request for the same head branch or update group, if any
+func Existing(open []PullRequest, req Request) *PullRequest {
+	marker := GroupMarker(req.Group)
+	for i := range open {
+		pr := &open[i]
+		if pr.Head == req.Head {
+			return pr
+		}
+		if req.Group != "" && strings.Contains(pr.Body, marker) {
+			return pr
+		}
+	}
+	return nil
+}
+
+// FindExisting returns the open pull request for the same head branch or update group, nil if none
+func FindExisting(ctx context.Context, f Forge, req Request) (*PullRequest, error) {
+	open, err := f.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open pull requests: %w", err)
+	}
+	return Existing(open, req), nil
+}
+
+// Publish updates the existing pull request, or opens a new one when existing is nil
+// Reviewers are only requested on new pull requests
+// Returns the pull request and whether it was created
+func Publish(ctx context.Context, f Forge, req Request, existing *PullRequest) (*PullRequest, bool, error) {
+	body := req.Body
+	if req.Group != "" {
+		body = strings.TrimRight(body, "\n") + "\n\n" + GroupMarker(req.Group) + "\n"
+	}
+	pr := PullRequest{Head: req.Head, Base: req.Base, Title: req.Title, Body: body}
+
+	var result *PullRequest
+	var err error
+	created := existing == nil
+	if existing != nil {
+		pr.Number = existing.Number
+		pr.Head = existing.Head
+		result, err = f.Update(ctx, pr)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to update pull request #%d: %w", pr.Number, err)
+		}
+	} else {
+		result, err = f.Create(ctx, pr)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create pull request: %w", err)
+		}
+	}
+
+	if len(req.Labels) > 0 {
+		if err := f.AddLabels(ctx, result.Number, req.Labels); err != nil {
+			return result, created, fmt.Errorf("failed to label pull request #%d: %w", result.Number, err)
+		}
+	}
+	if created && len(req.Reviewers) > 0 {
+		if err := f.RequestReviewers(ctx, result.Number, req.Reviewers); err != nil {
+			return result, created, fmt.Errorf("failed to request reviewers on pull request #%d: %w", result.Number, err)
+		}
+	}
+
+	return result, created, nil
+}
+
+// GroupMarker is the hidden comment identifying the update group of a pull request body
+func GroupMarker(group string) string {
+	return fmt.Sprintf("<!-- terraform-module-versions group: %s -->", group)
+}
+
+// ChangeTypeLabels returns the labels of the given change types, sorted and without duplicates
+func ChangeTypeLabels(labels map[string]string, changeTypes []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, changeType := range changeTypes {
+		label := labels[changeType]
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		result = append(result, label)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// DetectKind guesses the forge of a well-known host, empty when unknown
+func DetectKind(host string) string {
+	switch {
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return KindGitHub
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return KindGitLab
+	case host == "codeberg.org" || strings.HasPrefix(host, "gitea."):
+		return KindGitea
+	}
+	return ""
+}
+
+// APIURL returns the API base URL of a forge served from host
+func APIURL(kind, host string) string {
+	switch kind {
+	case KindGitHub:
+		if host == "github.com" {
+			return DefaultGitHubAPI
+		}
+		return "https://" + host + "/api/v3"
+	case KindGitLab:
+		return "https://" + host + "/api/v4"
+	default:
+		return "https://" + host
+	}
+}
+
+var scpRemote = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// ParseRemoteURL returns the host and repository path of a git remote URL
+// e.g., "github.com" and "owner/name" for git@github.com:owner/name.git
+func ParseRemoteURL(remote string) (string, string, error) {
+	var host, path string
+
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid remote URL %q: %w", remote, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if m := scpRemote.FindStringSubmatch(remote); m != nil {
+		host, path = m[1], m[2]
+	} else {
+		return "", "", fmt.Errorf("unsupported remote URL %q", remote)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("remote URL %q has no owner/name path", remote)
+	}
+	return host, path, nil
+}
@@ -0,0 +1,503 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePulls is the pull request store of a fake forge server
+type fakePulls struct {
+	mu        sync.Mutex
+	pulls     []map[string]interface{}
+	labels    map[int][]interface{}
+	reviewers map[int][]interface{}
+	calls     []string
+}
+
+func newFakePulls() *fakePulls {
+	return &fakePulls{labels: make(map[int][]interface{}), reviewers: make(map[int][]interface{})}
+}
+
+// handle records the call and checks authentication before serving it
+func (f *fakePulls) handle(t *testing.T, mux *http.ServeMux, pattern, header, value string, handler func(http.ResponseWriter, *http.Request, map[string]interface{})) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(header) != value {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var in map[string]interface{}
+		if r.Body != nil && r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				t.Errorf("invalid request body: %v", err)
+			}
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+		handler(w, r, in)
+	})
+}
+
+func (f *fakePulls) find(number string) map[string]interface{} {
+	n, _ := strconv.Atoi(number)
+	for _, pull := range f.pulls {
+		if pull["number"] == n {
+			return pull
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// newFakeGitHub serves the GitHub pull request endpoints of acme/infra
+func newFakeGitHub(t *testing.T, store *fakePulls) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	auth := "Bearer test-token"
+
+	store.handle(t, mux, "GET /repos/acme/infra/pulls", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		writeJSON(w, store.pulls)
+	})
+	store.handle(t, mux, "POST /repos/acme/infra/pulls", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		number := len(store.pulls) + 1
+		pull := map[string]interface{}{
+			"number":   number,
+			"html_url": fmt.Sprintf("https://github.example/acme/infra/pull/%d", number),
+			"title":    in["title"],
+			"body":     in["body"],
+			"head":     map[string]interface{}{"ref": in["head"]},
+			"base":     map[string]interface{}{"ref": in["base"]},
+		}
+		store.pulls = append(store.pulls, pull)
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, pull)
+	})
+	store.handle(t, mux, "PATCH /repos/acme/infra/pulls/{number}", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		pull := store.find(r.PathValue("number"))
+		if pull == nil {
+			http.NotFound(w, r)
+			return
+		}
+		pull["title"], pull["body"] = in["title"], in["body"]
+		writeJSON(w, pull)
+	})
+	store.handle(t, mux, "POST /repos/acme/infra/issues/{number}/labels", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		store.labels[n] = append(store.labels[n], in["labels"].([]interface{})...)
+		writeJSON(w, []interface{}{})
+	})
+	store.handle(t, mux, "POST /repos/acme/infra/pulls/{number}/requested_reviewers", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		store.reviewers[n] = append(store.reviewers[n], in["reviewers"].([]interface{})...)
+		writeJSON(w, map[string]interface{}{})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// publish opens or updates the pull request of req, as a run would
+func publish(f Forge, req Request) (*PullRequest, bool, error) {
+	existing, err := FindExisting(context.Background(), f, req)
+	if err != nil {
+		return nil, false, err
+	}
+	return Publish(context.Background(), f, req, existing)
+}
+
+func TestPublishGitHub(t *testing.T) {
+	store := newFakePulls()
+	server := newFakeGitHub(t, store)
+
+	f, err := New(KindGitHub, server.URL, "acme/infra", "test-token")
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	req := Request{
+		Head:      "tfmv/patches-20240131",
+		Base:      "main",
+		Title:     "Update 2 Terraform dependencies",
+		Body:      "## Module updates\n",
+		Group:     "patches",
+		Labels:    []string{"dependencies", "semver:patch"},
+		Reviewers: []string{"alice"},
+	}
+
+	pr, created, err := publish(f, req)
+	if err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if !created || pr.Number != 1 || pr.URL != "https://github.example/acme/infra/pull/1" {
+		t.Errorf("Publish = %+v, created %v; want new pull request #1", pr, created)
+	}
+	if !strings.Contains(pr.Body, GroupMarker("patches")) {
+		t.Errorf("body %q does not carry the group marker", pr.Body)
+	}
+	if fmt.Sprint(store.labels[1]) != "[dependencies semver:patch]" || fmt.Sprint(store.reviewers[1]) != "[alice]" {
+		t.Errorf("labels = %v, reviewers = %v", store.labels[1], store.reviewers[1])
+	}
+
+	// A later run for the same group updates the open pull request, even from another branch
+	req.Head = "tfmv/patches-20240207"
+	req.Title = "Update 3 Terraform dependencies"
+	pr, created, err = publish(f, req)
+	if err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if created || pr.Number != 1 || pr.Title != req.Title || pr.Head != "tfmv/patches-20240131" {
+		t.Errorf("Publish = %+v, created %v; want updated pull request #1", pr, created)
+	}
+	if len(store.pulls) != 1 {
+		t.Errorf("got %d pull requests, want 1", len(store.pulls))
+	}
+	if len(store.reviewers[1]) != 1 {
+		t.Errorf("reviewers requested again on update: %v", store.reviewers[1])
+	}
+}
+
+func TestPublishUnauthorized(t *testing.T) {
+	server := newFakeGitHub(t, newFakePulls())
+
+	f := NewGitHub(server.URL, "acme/infra", "wrong-token")
+	if _, _, err := publish(f, Request{Head: "tfmv/x", Base: "main", Title: "x"}); err == nil {
+		t.Error("expected error with an invalid token")
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	store := newFakePulls()
+	mux := http.NewServeMux()
+	auth := "test-token"
+
+	checkProject := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.PathValue("project") != "acme/infra" {
+			http.NotFound(w, r)
+			return false
+		}
+		return true
+	}
+	store.handle(t, mux, "GET /api/v4/projects/{project}/merge_requests", "PRIVATE-TOKEN", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		if checkProject(w, r) {
+			writeJSON(w, store.pulls)
+		}
+	})
+	store.handle(t, mux, "POST /api/v4/projects/{project}/merge_requests", "PRIVATE-TOKEN", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		if !checkProject(w, r) {
+			return
+		}
+		mr := map[string]interface{}{
+			"iid":           7,
+			"number":        7,
+			"web_url":       "https://gitlab.example/acme/infra/-/merge_requests/7",
+			"title":         in["title"],
+			"description":   in["description"],
+			"source_branch": in["source_branch"],
+			"target_branch": in["target_branch"],
+		}
+		store.pulls = append(store.pulls, mr)
+		writeJSON(w, mr)
+	})
+	store.handle(t, mux, "PUT /api/v4/projects/{project}/merge_requests/{iid}", "PRIVATE-TOKEN", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		mr := store.find(r.PathValue("iid"))
+		if !checkProject(w, r) || mr == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if labels, ok := in["add_labels"]; ok {
+			store.labels[7] = append(store.labels[7], labels)
+		}
+		if ids, ok := in["reviewer_ids"]; ok {
+			store.reviewers[7] = append(store.reviewers[7], ids.([]interface{})...)
+		}
+		writeJSON(w, mr)
+	})
+	store.handle(t, mux, "GET /api/v4/users", "PRIVATE-TOKEN", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		if r.URL.Query().Get("username") == "alice" {
+			writeJSON(w, []gitlabUser{{ID: 42, Username: "alice"}})
+			return
+		}
+		writeJSON(w, []gitlabUser{})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	f, err := New(KindGitLab, server.URL+"/api/v4", "acme/infra", auth)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	pr, created, err := publish(f, Request{
+		Head: "tfmv/majors", Base: "main", Title: "Update eks", Body: "body",
+		Labels: []string{"dependencies", "semver:major"}, Reviewers: []string{"alice"},
+	})
+	if err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if !created || pr.Number != 7 || pr.Head != "tfmv/majors" || pr.Base != "main" {
+		t.Errorf("Publish = %+v, created %v", pr, created)
+	}
+	if fmt.Sprint(store.labels[7]) != "[dependencies,semver:major]" || fmt.Sprint(store.reviewers[7]) != "[42]" {
+		t.Errorf("labels = %v, reviewers = %v", store.labels[7], store.reviewers[7])
+	}
+
+	if err := f.RequestReviewers(context.Background(), 7, []string{"bob"}); err == nil {
+		t.Error("expected error for an unknown reviewer")
+	}
+}
+
+func TestGiteaLabels(t *testing.T) {
+	store := newFakePulls()
+	mux := http.NewServeMux()
+	auth := "token test-token"
+
+	store.handle(t, mux, "GET /api/v1/repos/acme/infra/labels", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		writeJSON(w, []giteaLabel{{ID: 3, Name: "dependencies"}, {ID: 5, Name: "semver:minor"}})
+	})
+	store.handle(t, mux, "POST /api/v1/repos/acme/infra/issues/{number}/labels", "Authorization", auth, func(w http.ResponseWriter, r *http.Request, in map[string]interface{}) {
+		store.labels[2] = append(store.labels[2], in["labels"].([]interface{})...)
+		writeJSON(w, []interface{}{})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	f := NewGitea(server.URL, "acme/infra", "test-token")
+
+	if err := f.AddLabels(context.Background(), 2, []string{"dependencies", "semver:minor"}); err != nil {
+		t.Fatalf("AddLabels returned error: %v", err)
+	}
+	if fmt.Sprint(store.labels[2]) != "[3 5]" {
+		t.Errorf("label IDs = %v, want [3 5]", store.labels[2])
+	}
+
+	if err := f.AddLabels(context.Background(), 2, []string{"semver:major"}); err == nil {
+		t.Error("expected error for a label missing from the repository")
+	}
+}
+
+func TestExisting(t *testing.T) {
+	open := []PullRequest{
+		{Number: 1, Head: "feature"},
+		{Number: 2, Head: "tfmv/aws-20240101", Body: "summary\n\n" + GroupMarker("aws") + "\n"},
+		{Number: 3, Head: "tfmv/manual"},
+	}
+
+	tests := []struct {
+		name string
+		req  Request
+		want int
+	}{
+		{"same head", Request{Head: "tfmv/manual"}, 3},
+		{"same group", Request{Head: "tfmv/aws-20240108", Group: "aws"}, 2},
+		{"other group", Request{Head: "tfmv/gcp-20240108", Group: "gcp"}, 0},
+		{"no group", Request{Head: "tfmv/new"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := 0
+			if pr := Existing(open, tt.req); pr != nil {
+				got = pr.Number
+			}
+			if got != tt.want {
+				t.Errorf("Existing = #%d, want #%d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		host   string
+		path   string
+	}{
+		{"git@github.com:acme/infra.git", "github.com", "acme/infra"},
+		{"https://gitlab.com/acme/platform/infra.git", "gitlab.com", "acme/platform/infra"},
+		{"ssh://git@gitea.example:2222/acme/infra", "gitea.example", "acme/infra"},
+	}
+
+	for _, tt := range tests {
+		host, path, err := ParseRemoteURL(tt.remote)
+		if err != nil {
+			t.Errorf("ParseRemoteURL(%q) returned error: %v", tt.remote, err)
+			continue
+		}
+		if host != tt.host || path != tt.path {
+			t.Errorf("ParseRemoteURL(%q) = %q, %q; want %q, %q", tt.remote, host, path, tt.host, tt.path)
+		}
+	}
+
+	if _, _, err := ParseRemoteURL("/srv/git/infra"); err == nil {
+		t.Error("expected error for a local path remote")
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		host string
+		kind string
+		api  string
+	}{
+		{"github.com", KindGitHub, DefaultGitHubAPI},
+		{"github.acme.corp", KindGitHub, "https://github.acme.corp/api/v3"},
+		{"gitlab.com", KindGitLab, DefaultGitLabAPI},
+		{"codeberg.org", KindGitea, "https://codeberg.org"},
+		{"git.acme.corp", "", ""},
+	}
+
+	for _, tt := range tests {
+		kind := DetectKind(tt.host)
+		if kind != tt.kind {
+			t.Errorf("DetectKind(%q) = %q, want %q", tt.host, kind, tt.kind)
+		}
+		if kind != "" && APIURL(kind, tt.host) != tt.api {
+			t.Errorf("APIURL(%q, %q) = %q, want %q", kind, tt.host, APIURL(kind, tt.host), tt.api)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New("bitbucket", "", "acme/infra", ""); err == nil {
+		t.Error("expected error for an unsupported forge")
+	}
+	if _, err := New(KindGitea, "", "acme/infra", ""); err == nil {
+		t.Error("expected error for gitea without an API URL")
+	}
+	if _, err := New(KindGitHub, "", "infra", ""); err == nil {
+		t.Error("expected error for a repository without owner")
+	}
+}
+
+// pagedPulls serves a list, e.g. pull requests, two items per page, announcing the next page with next
+func pagedPulls(pulls []map[string]interface{}, next func(w http.ResponseWriter, r *http.Request, page int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		start, end := (page-1)*2, page*2
+		if end >= len(pulls) {
+			end = len(pulls)
+		} else {
+			next(w, r, page+1)
+		}
+		writeJSON(w, pulls[start:end])
+	}
+}
+
+func TestListOpenPagesGitHub(t *testing.T) {
+	var pulls []map[string]interface{}
+	for n := 1; n <= 5; n++ {
+		pulls = append(pulls, map[string]interface{}{"number": n, "head": map[string]interface{}{"ref": fmt.Sprintf("tfmv/%d", n)}})
+	}
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	list := pagedPulls(pulls, func(w http.ResponseWriter, r *http.Request, page int) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/acme/infra/pulls?state=open&per_page=100&page=%d>; rel="next", <%s/repos/acme/infra/pulls?page=3>; rel="last"`, server.URL, page, server.URL))
+	})
+	mux.HandleFunc("GET /repos/acme/infra/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "application/vnd.github+json" {
+			t.Errorf("Accept = %q, want application/vnd.github+json", accept)
+		}
+		list(w, r)
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	open, err := NewGitHub(server.URL, "acme/infra", "").ListOpen(context.Background())
+	if err != nil {
+		t.Fatalf("ListOpen returned error: %v", err)
+	}
+	if len(open) != 5 || open[4].Number != 5 || open[4].Head != "tfmv/5" {
+		t.Errorf("ListOpen = %+v, want the 5 pull requests of 3 pages", open)
+	}
+
+	// The pull request of a later page is found
+	if existing := Existing(open, Request{Head: "tfmv/5"}); existing == nil || existing.Number != 5 {
+		t.Errorf("Existing = %+v, want #5", existing)
+	}
+}
+
+func TestListOpenPagesGitLab(t *testing.T) {
+	var requests []map[string]interface{}
+	for n := 1; n <= 3; n++ {
+		requests = append(requests, map[string]interface{}{"iid": n, "source_branch": fmt.Sprintf("tfmv/%d", n)})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests", pagedPulls(requests, func(w http.ResponseWriter, r *http.Request, page int) {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page))
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	open, err := NewGitLab(server.URL+"/api/v4", "acme/infra", "").ListOpen(context.Background())
+	if err != nil {
+		t.Fatalf("ListOpen returned error: %v", err)
+	}
+	if len(open) != 3 || open[2].Number != 3 || open[2].Head != "tfmv/3" {
+		t.Errorf("ListOpen = %+v, want the 3 merge requests of 2 pages", open)
+	}
+}
+
+func TestListOpenForeignNextPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/infra/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://elsewhere.example/pulls?page=2>; rel="next"`)
+		writeJSON(w, []interface{}{})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	if _, err := NewGitHub(server.URL, "acme/infra", "").ListOpen(context.Background()); err == nil {
+		t.Error("expected error for a next page outside of the API")
+	}
+}
+
+func TestGiteaLabelsPages(t *testing.T) {
+	var labels []map[string]interface{}
+	for id := 1; id <= 5; id++ {
+		labels = append(labels, map[string]interface{}{"id": id, "name": fmt.Sprintf("label-%d", id)})
+	}
+
+	var added []interface{}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("GET /api/v1/repos/acme/infra/labels", pagedPulls(labels, func(w http.ResponseWriter, r *http.Request, page int) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/repos/acme/infra/labels?limit=50&page=%d>; rel="next"`, server.URL, page))
+	}))
+	mux.HandleFunc("POST /api/v1/repos/acme/infra/issues/2/labels", func(w http.ResponseWriter, r *http.Request) {
+		var in map[string][]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("failed to decode labels: %v", err)
+		}
+		added = in["labels"]
+		writeJSON(w, []interface{}{})
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	// A label of the last page is found
+	if err := NewGitea(server.URL, "acme/infra", "").AddLabels(context.Background(), 2, []string{"label-1", "label-5"}); err != nil {
+		t.Fatalf("AddLabels returned error: %v", err)
+	}
+	if fmt.Sprint(added) != "[1 5]" {
+		t.Errorf("label IDs = %v, want [1 5]", added)
+	}
+}
@@ -0,0 +1,146 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Gitea opens pull requests through the Gitea (and Forgejo) REST API
+type Gitea struct {
+	api  *apiClient
+	repo string // "owner/name"
+}
+
+type giteaPull struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type giteaLabel struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// NewGitea creates a Gitea client for a repository
+// baseURL is the instance URL, with or without the /api/v1 suffix
+func NewGitea(baseURL, repository, token string) *Gitea {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/api/v1") {
+		baseURL += "/api/v1"
+	}
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "token "+token)
+	}
+	return &Gitea{api: newAPIClient(baseURL, header), repo: escapePath(repository)}
+}
+
+// ListOpen implements Forge
+func (g *Gitea) ListOpen(ctx context.Context) ([]PullRequest, error) {
+	var pulls []giteaPull
+	err := g.api.list(ctx, fmt.Sprintf("/repos/%s/pulls?state=open&limit=50", g.repo), func(page json.RawMessage) error {
+		var batch []giteaPull
+		if err := json.Unmarshal(page, &batch); err != nil {
+			return err
+		}
+		pulls = append(pulls, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(pulls))
+	for _, pull := range pulls {
+		result = append(result, pull.pullRequest())
+	}
+	return result, nil
+}
+
+// Create implements Forge
+func (g *Gitea) Create(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": pr.Title, "head": pr.Head, "base": pr.Base, "body": pr.Body}
+	var pull giteaPull
+	if err := g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls", g.repo), in, &pull); err != nil {
+		return nil, err
+	}
+	result := pull.pullRequest()
+	return &result, nil
+}
+
+// Update implements Forge
+func (g *Gitea) Update(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": pr.Title, "body": pr.Body}
+	var pull giteaPull
+	if err := g.api.do(ctx, "PATCH", fmt.Sprintf("/repos/%s/pulls/%d", g.repo, pr.Number), in, &pull); err != nil {
+		return nil, err
+	}
+	result := pull.pullRequest()
+	return &result, nil
+}
+
+// AddLabels implements Forge
+// Gitea adds labels by ID; labels missing from the repository are reported as an error
+func (g *Gitea) AddLabels(ctx context.Context, number int, labels []string) error {
+	var existing []giteaLabel
+	err := g.api.list(ctx, fmt.Sprintf("/repos/%s/labels?limit=50", g.repo), func(page json.RawMessage) error {
+		var batch []giteaLabel
+		if err := json.Unmarshal(page, &batch); err != nil {
+			return err
+		}
+		existing = append(existing, batch...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]int64, len(existing))
+	for _, label := range existing {
+		byName[label.Name] = label.ID
+	}
+
+	var ids []int64
+	var missing []string
+	for _, name := range labels {
+		id, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("labels not defined in the repository: %s", strings.Join(missing, ", "))
+	}
+
+	in := map[string][]int64{"labels": ids}
+	return g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/issues/%d/labels", g.repo, number), in, nil)
+}
+
+// RequestReviewers implements Forge
+func (g *Gitea) RequestReviewers(ctx context.Context, number int, reviewers []string) error {
+	in := map[string][]string{"reviewers": reviewers}
+	return g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", g.repo, number), in, nil)
+}
+
+func (p giteaPull) pullRequest() PullRequest {
+	return PullRequest{
+		Number: p.Number,
+		URL:    p.HTMLURL,
+		Head:   p.Head.Ref,
+		Base:   p.Base.Ref,
+		Title:  p.Title,
+		Body:   p.Body,
+	}
+}
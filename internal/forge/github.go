@@ -0,0 +1,122 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultGitHubAPI is the public GitHub REST API
+const DefaultGitHubAPI = "https://api.github.com"
+
+// GitHub opens pull requests through the GitHub REST API
+type GitHub struct {
+	api  *apiClient
+	repo string // "owner/name"
+}
+
+type githubPull struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// NewGitHub creates a GitHub client for a repository
+// An empty baseURL uses api.github.com; GitHub Enterprise uses https://host/api/v3
+func NewGitHub(baseURL, repository, token string) *GitHub {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPI
+	}
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return &GitHub{api: newAPIClient(baseURL, header), repo: escapePath(repository)}
+}
+
+// ListOpen implements Forge
+func (g *GitHub) ListOpen(ctx context.Context) ([]PullRequest, error) {
+	var pulls []githubPull
+	err := g.api.list(ctx, fmt.Sprintf("/repos/%s/pulls?state=open&per_page=100", g.repo), func(page json.RawMessage) error {
+		var batch []githubPull
+		if err := json.Unmarshal(page, &batch); err != nil {
+			return err
+		}
+		pulls = append(pulls, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(pulls))
+	for _, pull := range pulls {
+		result = append(result, pull.pullRequest())
+	}
+	return result, nil
+}
+
+// Create implements Forge
+func (g *GitHub) Create(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": pr.Title, "head": pr.Head, "base": pr.Base, "body": pr.Body}
+	var pull githubPull
+	if err := g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls", g.repo), in, &pull); err != nil {
+		return nil, err
+	}
+	result := pull.pullRequest()
+	return &result, nil
+}
+
+// Update implements Forge
+func (g *GitHub) Update(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": pr.Title, "body": pr.Body}
+	var pull githubPull
+	if err := g.api.do(ctx, "PATCH", fmt.Sprintf("/repos/%s/pulls/%d", g.repo, pr.Number), in, &pull); err != nil {
+		return nil, err
+	}
+	result := pull.pullRequest()
+	return &result, nil
+}
+
+// AddLabels implements Forge
+func (g *GitHub) AddLabels(ctx context.Context, number int, labels []string) error {
+	in := map[string][]string{"labels": labels}
+	return g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/issues/%d/labels", g.repo, number), in, nil)
+}
+
+// RequestReviewers implements Forge
+func (g *GitHub) RequestReviewers(ctx context.Context, number int, reviewers []string) error {
+	in := map[string][]string{"reviewers": reviewers}
+	return g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", g.repo, number), in, nil)
+}
+
+func (p githubPull) pullRequest() PullRequest {
+	return PullRequest{
+		Number: p.Number,
+		URL:    p.HTMLURL,
+		Head:   p.Head.Ref,
+		Base:   p.Base.Ref,
+		Title:  p.Title,
+		Body:   p.Body,
+	}
+}
+
+// escapePath escapes each segment of a repository path
+func escapePath(repository string) string {
+	segments := strings.Split(repository, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
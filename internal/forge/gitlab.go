@@ -0,0 +1,132 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultGitLabAPI is the public GitLab REST API
+const DefaultGitLabAPI = "https://gitlab.com/api/v4"
+
+// GitLab opens merge requests through the GitLab REST API
+type GitLab struct {
+	api     *apiClient
+	project string // URL-encoded project path
+}
+
+type gitlabMergeRequest struct {
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+}
+
+type gitlabUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// NewGitLab creates a GitLab client for a project, e.g., "group/subgroup/name"
+// An empty baseURL uses gitlab.com; self-managed instances use https://host/api/v4
+func NewGitLab(baseURL, project, token string) *GitLab {
+	if baseURL == "" {
+		baseURL = DefaultGitLabAPI
+	}
+	header := http.Header{}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+	return &GitLab{api: newAPIClient(baseURL, header), project: url.PathEscape(project)}
+}
+
+// ListOpen implements Forge
+func (g *GitLab) ListOpen(ctx context.Context) ([]PullRequest, error) {
+	var requests []gitlabMergeRequest
+	path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&per_page=100", g.project)
+	err := g.api.list(ctx, path, func(page json.RawMessage) error {
+		var batch []gitlabMergeRequest
+		if err := json.Unmarshal(page, &batch); err != nil {
+			return err
+		}
+		requests = append(requests, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, 0, len(requests))
+	for _, mr := range requests {
+		result = append(result, mr.pullRequest())
+	}
+	return result, nil
+}
+
+// Create implements Forge
+func (g *GitLab) Create(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := map[string]string{
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+		"title":         pr.Title,
+		"description":   pr.Body,
+	}
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, "POST", fmt.Sprintf("/projects/%s/merge_requests", g.project), in, &mr); err != nil {
+		return nil, err
+	}
+	result := mr.pullRequest()
+	return &result, nil
+}
+
+// Update implements Forge
+func (g *GitLab) Update(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": pr.Title, "description": pr.Body}
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, "PUT", fmt.Sprintf("/projects/%s/merge_requests/%d", g.project, pr.Number), in, &mr); err != nil {
+		return nil, err
+	}
+	result := mr.pullRequest()
+	return &result, nil
+}
+
+// AddLabels implements Forge
+func (g *GitLab) AddLabels(ctx context.Context, number int, labels []string) error {
+	in := map[string]string{"add_labels": strings.Join(labels, ",")}
+	return g.api.do(ctx, "PUT", fmt.Sprintf("/projects/%s/merge_requests/%d", g.project, number), in, nil)
+}
+
+// RequestReviewers implements Forge
+// GitLab assigns reviewers by user ID, looked up from each username
+func (g *GitLab) RequestReviewers(ctx context.Context, number int, reviewers []string) error {
+	var ids []int
+	for _, username := range reviewers {
+		var users []gitlabUser
+		if err := g.api.do(ctx, "GET", "/users?username="+url.QueryEscape(username), nil, &users); err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return fmt.Errorf("unknown GitLab user %q", username)
+		}
+		ids = append(ids, users[0].ID)
+	}
+
+	in := map[string][]int{"reviewer_ids": ids}
+	return g.api.do(ctx, "PUT", fmt.Sprintf("/projects/%s/merge_requests/%d", g.project, number), in, nil)
+}
+
+func (mr gitlabMergeRequest) pullRequest() PullRequest {
+	return PullRequest{
+		Number: mr.IID,
+		URL:    mr.WebURL,
+		Head:   mr.SourceBranch,
+		Base:   mr.TargetBranch,
+		Title:  mr.Title,
+		Body:   mr.Description,
+	}
+}
@@ -0,0 +1,132 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultCommitMessageTemplate lists each change with its files
+const DefaultCommitMessageTemplate = `{{ if eq (len .Changes) 1 }}{{ with index .Changes 0 }}Update {{ .Name }} to {{ .To }}{{ end }}
+{{- else }}Update {{ len .Changes }} Terraform dependencies{{ end }}
+{{ range .Changes }}
+- {{ .Name }} {{ .From }} → {{ .To }}
+{{- range .Files }}
+  - {{ . }}
+{{- end }}
+{{- end }}
+`
+
+// ToolTrailer ends the message of every commit made by update runs, so that branches
+// holding other commits are never replaced
+const ToolTrailer = "Updated-by: terraform-module-versions"
+
+// trailerLine matches a git trailer, e.g. "Signed-off-by: Jane <jane@example.com>"
+var trailerLine = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*: \S`)
+
+// WithTrailer returns a commit message whose trailers include ToolTrailer
+// The trailer joins the trailers a message already ends with, e.g. from a custom template
+func WithTrailer(message string) string {
+	if HasTrailer(message) {
+		return message
+	}
+	message = strings.TrimRight(message, "\n")
+	if trailers(message) != nil {
+		return message + "\n" + ToolTrailer
+	}
+	return message + "\n\n" + ToolTrailer
+}
+
+// HasTrailer reports whether a commit message was written by an update run
+// Trailers added after ToolTrailer, e.g. by a commit-msg hook, are allowed
+func HasTrailer(message string) bool {
+	for _, line := range trailers(message) {
+		if line == ToolTrailer {
+			return true
+		}
+	}
+	return false
+}
+
+// trailers returns the lines of the last paragraph of a message when they are all
+// trailers, nil otherwise. A message of a single paragraph has no trailers
+func trailers(message string) []string {
+	paragraphs := strings.Split(strings.TrimSpace(message), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+
+	lines := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+		if !trailerLine.MatchString(lines[i]) {
+			return nil
+		}
+	}
+	return lines
+}
+
+// Change is one dependency update included in a commit
+type Change struct {
+	Name  string   // Module or provider source
+	From  string   // Current version or constraint
+	To    string   // New version or constraint
+	Files []string // Files modified by the change
+}
+
+// AddFile records a modified file once
+func (c *Change) AddFile(file string) {
+	for _, f := range c.Files {
+		if f == file {
+			return
+		}
+	}
+	c.Files = append(c.Files, file)
+}
+
+// CommitData is the data available to commit message templates
+type CommitData struct {
+	Changes []Change
+}
+
+// BranchData is the data available to branch name templates
+type BranchData struct {
+	Date      string // Run date, e.g., "20240131"
+	Timestamp string // Run date and time, e.g., "20240131-154502"
+	Directory string // Base name of the updated directory
+	Group     string // Update group applied with update --group, empty otherwise
+}
+
+// NewBranchData returns branch template data for a run started at now on dir
+func NewBranchData(dir string, now time.Time) BranchData {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	return BranchData{
+		Date:      now.Format("20060102"),
+		Timestamp: now.Format("20060102-150405"),
+		Directory: filepath.Base(abs),
+	}
+}
+
+// ParseTemplate parses a branch name or commit message template
+func ParseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// Render executes a template and trims surrounding whitespace from the result
+func Render(tmpl *template.Template, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
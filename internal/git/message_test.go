@@ -0,0 +1,79 @@
+package git
+
+import "testing"
+
+func TestDefaultCommitMessage(t *testing.T) {
+	tmpl, err := ParseTemplate("commit message", DefaultCommitMessageTemplate)
+	if err != nil {
+		t.Fatalf("ParseTemplate returned error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		changes []Change
+		want    string
+	}{
+		{
+			name:    "single change",
+			changes: []Change{{Name: "acme/vpc/aws", From: "4.0.0", To: "5.1.0", Files: []string{"main.tf"}}},
+			want:    "Update acme/vpc/aws to 5.1.0\n\n- acme/vpc/aws 4.0.0 → 5.1.0\n  - main.tf",
+		},
+		{
+			name: "several changes",
+			changes: []Change{
+				{Name: "acme/vpc/aws", From: "4.0.0", To: "5.1.0", Files: []string{"main.tf", "locals.tf"}},
+				{Name: "hashicorp/aws", From: "~> 4.0", To: "~> 5.31", Files: []string{"versions.tf"}},
+			},
+			want: "Update 2 Terraform dependencies\n\n" +
+				"- acme/vpc/aws 4.0.0 → 5.1.0\n  - main.tf\n  - locals.tf\n" +
+				"- hashicorp/aws ~> 4.0 → ~> 5.31\n  - versions.tf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tmpl, CommitData{Changes: tt.changes})
+			if err != nil {
+				t.Fatalf("Render returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTrailer(t *testing.T) {
+	message := WithTrailer("Update acme/vpc/aws to 5.1.0\n\n- acme/vpc/aws 4.0.0 → 5.1.0\n")
+	want := "Update acme/vpc/aws to 5.1.0\n\n- acme/vpc/aws 4.0.0 → 5.1.0\n\n" + ToolTrailer
+	if message != want {
+		t.Errorf("WithTrailer() = %q, want %q", message, want)
+	}
+	if WithTrailer(message) != message {
+		t.Error("WithTrailer() added a second trailer")
+	}
+
+	if !HasTrailer(message + "\n") {
+		t.Error("HasTrailer() = false for a message ending with the trailer")
+	}
+	if HasTrailer("Fix review comment") || HasTrailer(ToolTrailer+"\n\nSquashed review fix") {
+		t.Error("HasTrailer() = true for a message not ending with the trailer")
+	}
+	if HasTrailer(ToolTrailer) || HasTrailer("Update vpc\n\n- "+ToolTrailer) {
+		t.Error("HasTrailer() = true for a trailer outside of a trailer paragraph")
+	}
+}
+
+func TestWithTrailerJoinsTrailers(t *testing.T) {
+	// A custom template ending with trailers of its own
+	message := WithTrailer("Update acme/vpc/aws to 5.1.0\n\nSigned-off-by: Bot <bot@example.com>\n")
+	want := "Update acme/vpc/aws to 5.1.0\n\nSigned-off-by: Bot <bot@example.com>\n" + ToolTrailer
+	if message != want {
+		t.Errorf("WithTrailer() = %q, want %q", message, want)
+	}
+
+	// A commit-msg hook appending a trailer after the tool's
+	if !HasTrailer(message + "\nChange-Id: I0123456789abcdef\n") {
+		t.Error("HasTrailer() = false for a trailer followed by another trailer")
+	}
+}
@@ -0,0 +1,225 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Repository is a local git working tree, driven through the git command
+type Repository struct {
+	root string
+}
+
+// Open returns the repository containing path
+func Open(path string) (*Repository, error) {
+	dir := path
+	if !isDir(path) {
+		dir = filepath.Dir(path)
+	}
+
+	root, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository: %w", path, err)
+	}
+	return &Repository{root: strings.TrimSpace(root)}, nil
+}
+
+// Root returns the top-level directory of the working tree
+func (r *Repository) Root() string {
+	return r.root
+}
+
+// IsClean reports whether the working tree has no staged, unstaged or untracked changes
+func (r *Repository) IsClean() (bool, error) {
+	status, err := run(r.root, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(status) == "", nil
+}
+
+// CurrentBranch returns the checked out branch, or an empty string when HEAD is detached
+func (r *Repository) CurrentBranch() (string, error) {
+	branch, err := run(r.root, "branch", "--show-current")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(branch), nil
+}
+
+// CreateBranch creates and checks out a branch from HEAD, keeping working tree changes
+func (r *Repository) CreateBranch(name string) error {
+	if _, err := run(r.root, "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("invalid branch name %q: %w", name, err)
+	}
+	if _, err := run(r.root, "checkout", "-b", name); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// Head returns the commit checked out
+func (r *Repository) Head() (string, error) {
+	head, err := run(r.root, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	return strings.TrimSpace(head), nil
+}
+
+// Reset moves the current branch to a commit and resets the index, keeping the working tree
+func (r *Repository) Reset(rev string) error {
+	if _, err := run(r.root, "reset", "--quiet", rev); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+	return nil
+}
+
+// Checkout checks out a branch, or detaches HEAD at a commit
+func (r *Repository) Checkout(rev string) error {
+	if _, err := run(r.root, "checkout", "--quiet", rev); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", rev, err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a local branch, even when it is not merged
+func (r *Repository) DeleteBranch(name string) error {
+	if _, err := run(r.root, "branch", "--quiet", "-D", name); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// RelativePath returns a path relative to the repository root
+func (r *Repository) RelativePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// git reports the root with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	return filepath.Rel(r.root, abs)
+}
+
+// Commit stages files and commits them with a message
+// Relative file paths are relative to the repository root
+// Returns false without error when the files have no changes to commit
+func (r *Repository) Commit(message string, files []string) (bool, error) {
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(r.root, file)
+		}
+		paths = append(paths, file)
+	}
+
+	if _, err := run(r.root, append([]string{"add", "--"}, paths...)...); err != nil {
+		return false, fmt.Errorf("failed to stage files: %w", err)
+	}
+
+	// diff --cached --quiet exits with 1 when changes are staged
+	if _, err := run(r.root, "diff", "--cached", "--quiet"); err == nil {
+		return false, nil
+	}
+
+	cmd := exec.Command("git", "-C", r.root, "commit", "--quiet", "--file", "-")
+	cmd.Stdin = strings.NewReader(message)
+	if err := runCommand(cmd); err != nil {
+		return false, fmt.Errorf("failed to commit: %w", err)
+	}
+	return true, nil
+}
+
+// RemoteURL returns the URL of a remote
+func (r *Repository) RemoteURL(remote string) (string, error) {
+	url, err := run(r.root, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to read remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(url), nil
+}
+
+// Push pushes a local branch to a branch of a remote
+// With a non-empty expected commit, the remote branch is replaced even when it has
+// diverged, but only while it still points at that commit
+func (r *Repository) Push(remote, branch, remoteBranch, expected string) error {
+	args := []string{"push", "--quiet"}
+	if expected != "" {
+		args = append(args, "--force-with-lease=refs/heads/"+remoteBranch+":"+expected)
+	}
+	args = append(args, remote, "refs/heads/"+branch+":refs/heads/"+remoteBranch)
+
+	if _, err := run(r.root, args...); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %w", branch, remote, err)
+	}
+	return nil
+}
+
+// FetchBranch fetches a branch of a remote and returns the commit it points at
+func (r *Repository) FetchBranch(remote, branch string) (string, error) {
+	if _, err := run(r.root, "fetch", "--quiet", remote, "refs/heads/"+branch); err != nil {
+		return "", fmt.Errorf("failed to fetch %s from %s: %w", branch, remote, err)
+	}
+	commit, err := run(r.root, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read fetched %s: %w", branch, err)
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// CommitMessages returns the messages of the commits reachable from rev but not from exclude
+func (r *Repository) CommitMessages(rev, exclude string) ([]string, error) {
+	log, err := run(r.root, "log", "--format=%B%x00", rev, "^"+exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of %s: %w", rev, err)
+	}
+
+	var messages []string
+	for _, message := range strings.Split(log, "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// run executes a git command in dir and returns its standard output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := runCommand(cmd); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// runCommand runs a git command, reporting its standard error on failure
+func runCommand(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s: %s", cmd.Args[3], msg)
+		}
+		return fmt.Errorf("git %s: %w", cmd.Args[3], err)
+	}
+	return nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,275 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepository initializes a repository with one committed file
+func newTestRepository(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "test-git-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"add", "main.tf"},
+		{"commit", "--quiet", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", args[0], err, out)
+		}
+	}
+
+	return dir
+}
+
+func TestRepositoryCommitOnBranch(t *testing.T) {
+	dir := newTestRepository(t)
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	clean, err := repo.IsClean()
+	if err != nil || !clean {
+		t.Fatalf("IsClean = %v, %v; want true", clean, err)
+	}
+
+	file := filepath.Join(dir, "main.tf")
+	if err := os.WriteFile(file, []byte("# updated\n"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	if clean, _ := repo.IsClean(); clean {
+		t.Error("IsClean = true after modifying a file")
+	}
+
+	if err := repo.CreateBranch("tfmv/update"); err != nil {
+		t.Fatalf("CreateBranch returned error: %v", err)
+	}
+	if branch, _ := repo.CurrentBranch(); branch != "tfmv/update" {
+		t.Errorf("CurrentBranch = %q, want tfmv/update", branch)
+	}
+
+	committed, err := repo.Commit("Update modules\n\n- vpc 1.0.0 → 2.0.0\n", []string{file})
+	if err != nil || !committed {
+		t.Fatalf("Commit = %v, %v; want true", committed, err)
+	}
+
+	out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%B").Output()
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "Update modules\n\n- vpc 1.0.0 → 2.0.0") {
+		t.Errorf("commit message = %q", out)
+	}
+
+	// Nothing left to commit
+	committed, err = repo.Commit("Again", []string{file})
+	if err != nil || committed {
+		t.Errorf("Commit = %v, %v; want false", committed, err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	dir := newTestRepository(t)
+
+	remote, err := os.MkdirTemp("", "test-git-remote-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(remote)
+
+	for _, args := range [][]string{
+		{"-C", remote, "init", "--quiet", "--bare"},
+		{"-C", dir, "remote", "add", "origin", remote},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", args[2], err, out)
+		}
+	}
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	url, err := repo.RemoteURL("origin")
+	if err != nil || url != remote {
+		t.Errorf("RemoteURL = %q, %v; want %q", url, err, remote)
+	}
+
+	if err := repo.Push("origin", "main", "tfmv/update", ""); err != nil {
+		t.Fatalf("Push returned error: %v", err)
+	}
+	if out, err := exec.Command("git", "-C", remote, "rev-parse", "--verify", "refs/heads/tfmv/update").CombinedOutput(); err != nil {
+		t.Errorf("remote branch missing: %v: %s", err, out)
+	}
+
+	if _, err := repo.RemoteURL("upstream"); err == nil {
+		t.Error("expected error for a missing remote")
+	}
+}
+
+func TestCreateBranchInvalidName(t *testing.T) {
+	repo, err := Open(newTestRepository(t))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	if err := repo.CreateBranch("bad..name"); err == nil {
+		t.Error("expected error for invalid branch name")
+	}
+}
+
+func TestOpenOutsideRepository(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test-git-none-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := Open(dir); err == nil {
+		t.Error("expected error outside of a repository")
+	}
+}
+
+func TestResetAndDeleteBranch(t *testing.T) {
+	dir := newTestRepository(t)
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	start, err := repo.Head()
+	if err != nil {
+		t.Fatalf("Head returned error: %v", err)
+	}
+
+	if err := repo.CreateBranch("tfmv/update"); err != nil {
+		t.Fatalf("CreateBranch returned error: %v", err)
+	}
+	file := filepath.Join(dir, "main.tf")
+	if err := os.WriteFile(file, []byte("# updated\n"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+	if _, err := repo.Commit("Update", []string{"main.tf"}); err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+
+	// Undo the commit, restore the file and leave the branch
+	if err := repo.Reset(start); err != nil {
+		t.Fatalf("Reset returned error: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("# empty\n"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+	if err := repo.Checkout("main"); err != nil {
+		t.Fatalf("Checkout returned error: %v", err)
+	}
+	if err := repo.DeleteBranch("tfmv/update"); err != nil {
+		t.Fatalf("DeleteBranch returned error: %v", err)
+	}
+
+	if head, _ := repo.Head(); head != start {
+		t.Errorf("Head = %s, want %s", head, start)
+	}
+	if branch, _ := repo.CurrentBranch(); branch != "main" {
+		t.Errorf("CurrentBranch = %q, want main", branch)
+	}
+	if clean, _ := repo.IsClean(); !clean {
+		t.Error("IsClean = false after rollback")
+	}
+	if out, err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", "refs/heads/tfmv/update").CombinedOutput(); err == nil {
+		t.Errorf("branch tfmv/update still exists: %s", out)
+	}
+}
+
+// newTestRemote creates a bare repository as remote origin of dir and pushes main to it
+func newTestRemote(t *testing.T, dir string) string {
+	t.Helper()
+
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"-C", remote, "init", "--quiet", "--bare", "--initial-branch", "main"},
+		{"-C", dir, "remote", "add", "origin", remote},
+		{"-C", dir, "push", "--quiet", "origin", "main"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", args[2], err, out)
+		}
+	}
+	return remote
+}
+
+// commitFile writes content to main.tf and commits it with message
+func commitFile(t *testing.T, repo *Repository, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo.Root(), "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+	if _, err := repo.Commit(message, []string{"main.tf"}); err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+}
+
+func TestPushWithLease(t *testing.T) {
+	dir := newTestRepository(t)
+	newTestRemote(t, dir)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	if err := repo.CreateBranch("tfmv/update"); err != nil {
+		t.Fatalf("CreateBranch returned error: %v", err)
+	}
+	commitFile(t, repo, "# first run\n", WithTrailer("Update vpc"))
+	if err := repo.Push("origin", "tfmv/update", "tfmv/update", ""); err != nil {
+		t.Fatalf("Push returned error: %v", err)
+	}
+
+	pushed, err := repo.FetchBranch("origin", "tfmv/update")
+	if err != nil {
+		t.Fatalf("FetchBranch returned error: %v", err)
+	}
+	base, err := repo.FetchBranch("origin", "main")
+	if err != nil {
+		t.Fatalf("FetchBranch returned error: %v", err)
+	}
+	messages, err := repo.CommitMessages(pushed, base)
+	if err != nil || len(messages) != 1 || !HasTrailer(messages[0]) {
+		t.Errorf("CommitMessages = %q, %v; want the run's commit", messages, err)
+	}
+
+	// A later run rewrites the branch while it still points at the fetched commit
+	if err := repo.Reset(base); err != nil {
+		t.Fatalf("Reset returned error: %v", err)
+	}
+	commitFile(t, repo, "# second run\n", WithTrailer("Update vpc again"))
+	if err := repo.Push("origin", "tfmv/update", "tfmv/update", pushed); err != nil {
+		t.Fatalf("Push with lease returned error: %v", err)
+	}
+
+	// The lease fails once the remote branch moved on
+	commitFile(t, repo, "# third run\n", WithTrailer("Update vpc once more"))
+	if err := repo.Push("origin", "tfmv/update", "tfmv/update", pushed); err == nil {
+		t.Error("Push with a stale lease succeeded")
+	}
+}
@@ -0,0 +1,250 @@
+package group
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/filter"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// DefaultName is the group of updates no rule matches
+const DefaultName = "default"
+
+// Rule assigns the pending updates it matches to a named group
+// Empty criteria match every update
+type Rule struct {
+	Name        string   `toml:"name"`
+	Sources     []string `toml:"sources"`      // Source patterns, exact or regex as with --module
+	ChangeTypes []string `toml:"change_types"` // "major", "minor" or "patch"
+	Directories []string `toml:"directories"`  // Glob patterns matched against a directory and its parents
+	PerModule   bool     `toml:"per_module"`   // Split the group into one group per module source
+	Branch      string   `toml:"branch"`       // Branch name template used by update --group
+}
+
+// Update is a pending module update in one directory
+type Update struct {
+	Source     string             `json:"source"`
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	ChangeType version.ChangeType `json:"change_type"`
+	Directory  string             `json:"directory"` // Directory relative to the analyzed path, "." for the path itself
+	Files      []string           `json:"files"`     // Files declaring the updated module blocks
+}
+
+// Group is a batch of updates applied together
+type Group struct {
+	Name    string   `json:"name"`
+	Branch  string   `json:"branch,omitempty"` // Branch name template from the rule, empty for the default group
+	Updates []Update `json:"updates"`
+}
+
+// Grouper assigns updates to groups with the first matching rule
+type Grouper struct {
+	rules    []Rule
+	matchers [][]*filter.Matcher
+}
+
+// NewGrouper validates rules and prepares their source matchers
+func NewGrouper(rules []Rule) (*Grouper, error) {
+	g := &Grouper{rules: rules}
+	names := make(map[string]bool)
+
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("group rule %d has no name", i+1)
+		}
+		if rule.Name == DefaultName || names[rule.Name] {
+			return nil, fmt.Errorf("group name %q is reserved or already used", rule.Name)
+		}
+		names[rule.Name] = true
+
+		for _, changeType := range rule.ChangeTypes {
+			switch version.ChangeType(changeType) {
+			case version.ChangeMajor, version.ChangeMinor, version.ChangePatch:
+			default:
+				return nil, fmt.Errorf("group %s: invalid change type %q: must be 'major', 'minor' or 'patch'", rule.Name, changeType)
+			}
+		}
+
+		for _, pattern := range rule.Directories {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("group %s: invalid directory pattern %q: %w", rule.Name, pattern, err)
+			}
+		}
+
+		var matchers []*filter.Matcher
+		for _, pattern := range rule.Sources {
+			matcher, err := filter.NewMatcher(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("group %s: %w", rule.Name, err)
+			}
+			matchers = append(matchers, matcher)
+		}
+		g.matchers = append(g.matchers, matchers)
+	}
+
+	return g, nil
+}
+
+// HasRules reports whether any grouping rule is configured
+func (g *Grouper) HasRules() bool {
+	return len(g.rules) > 0
+}
+
+// GroupOf returns the name of the group an update belongs to and the rule that placed it there
+// The rule is nil for the default group
+func (g *Grouper) GroupOf(update Update) (string, *Rule) {
+	name, index := g.groupOf(update)
+	if index == len(g.rules) {
+		return name, nil
+	}
+	return name, &g.rules[index]
+}
+
+// Rule returns the rule a group name comes from, nil for the default group or an unknown name
+func (g *Grouper) Rule(name string) *Rule {
+	var found *Rule
+	for i := range g.rules {
+		rule := &g.rules[i]
+		if rule.Name == name {
+			return rule
+		}
+		// Per-module groups are named after the rule and the module
+		if rule.PerModule && strings.HasPrefix(name, rule.Name+"-") && (found == nil || len(rule.Name) > len(found.Name)) {
+			found = rule
+		}
+	}
+	return found
+}
+
+// groupOf returns the group name and the index of the matching rule, len(rules) for the default group
+func (g *Grouper) groupOf(update Update) (string, int) {
+	for i, rule := range g.rules {
+		if !g.matches(i, update) {
+			continue
+		}
+		if rule.PerModule {
+			return rule.Name + "-" + moduleSlug(update.Source), i
+		}
+		return rule.Name, i
+	}
+	return DefaultName, len(g.rules)
+}
+
+// Partition splits updates into groups, in rule order with the default group last
+func (g *Grouper) Partition(updates []Update) []*Group {
+	byName := make(map[string]*Group)
+	order := make(map[string]int)
+	var groups []*Group
+
+	for _, update := range updates {
+		name, index := g.groupOf(update)
+		grp, ok := byName[name]
+		if !ok {
+			grp = &Group{Name: name}
+			if index < len(g.rules) {
+				grp.Branch = g.rules[index].Branch
+			}
+			order[name] = index
+			byName[name] = grp
+			groups = append(groups, grp)
+		}
+		grp.Updates = append(grp.Updates, update)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if order[groups[i].Name] != order[groups[j].Name] {
+			return order[groups[i].Name] < order[groups[j].Name]
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	for _, grp := range groups {
+		sort.SliceStable(grp.Updates, func(i, j int) bool {
+			a, b := grp.Updates[i], grp.Updates[j]
+			if a.Source != b.Source {
+				return a.Source < b.Source
+			}
+			if a.From != b.From {
+				return a.From < b.From
+			}
+			return a.Directory < b.Directory
+		})
+	}
+
+	return groups
+}
+
+func (g *Grouper) matches(index int, update Update) bool {
+	rule := g.rules[index]
+
+	if len(g.matchers[index]) > 0 {
+		matched := false
+		for _, matcher := range g.matchers[index] {
+			if matcher.Matches(update.Source) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(rule.ChangeTypes) > 0 {
+		matched := false
+		for _, changeType := range rule.ChangeTypes {
+			if version.ChangeType(changeType) == update.ChangeType {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(rule.Directories) > 0 {
+		matched := false
+		for _, pattern := range rule.Directories {
+			if matchDirectory(pattern, update.Directory) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchDirectory reports whether a directory or one of its parents matches a glob pattern
+func matchDirectory(pattern, dir string) bool {
+	pattern = filepath.Clean(pattern)
+	for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+		if ok, _ := filepath.Match(pattern, dir); ok {
+			return true
+		}
+		if dir == "." || dir == string(filepath.Separator) || filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// moduleSlug turns a module source into a group name suffix, e.g., "terraform-aws-modules-vpc-aws"
+func moduleSlug(source string) string {
+	parts := strings.Split(source, "/")
+	// Drop the registry host of private registry sources
+	if len(parts) == 4 {
+		parts = parts[1:]
+	}
+	slug := nonSlugChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-")
+	return strings.Trim(slug, "-")
+}
@@ -0,0 +1,133 @@
+package group
+
+import (
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+func TestPartition(t *testing.T) {
+	grouper, err := NewGrouper([]Rule{
+		{Name: "majors", ChangeTypes: []string{"major"}, PerModule: true, Branch: "tfmv/{{.Group}}"},
+		{Name: "aws-patches", Sources: []string{"terraform-aws-modules/.*"}, ChangeTypes: []string{"patch"}},
+		{Name: "prod", Directories: []string{"envs/prod"}},
+	})
+	if err != nil {
+		t.Fatalf("NewGrouper returned error: %v", err)
+	}
+
+	updates := []Update{
+		{Source: "terraform-aws-modules/vpc/aws", From: "4.0.0", To: "4.0.2", ChangeType: version.ChangePatch, Directory: "envs/dev"},
+		{Source: "terraform-aws-modules/eks/aws", From: "19.0.0", To: "20.1.0", ChangeType: version.ChangeMajor, Directory: "envs/dev"},
+		{Source: "terraform-aws-modules/s3-bucket/aws", From: "3.0.0", To: "4.0.0", ChangeType: version.ChangeMajor, Directory: "."},
+		{Source: "acme/network/aws", From: "1.0.0", To: "1.2.0", ChangeType: version.ChangeMinor, Directory: "envs/prod/network"},
+		{Source: "acme/network/aws", From: "1.0.0", To: "1.2.0", ChangeType: version.ChangeMinor, Directory: "envs/dev"},
+		{Source: "terraform-aws-modules/vpc/aws", From: "4.0.0", To: "4.0.2", ChangeType: version.ChangePatch, Directory: "."},
+	}
+
+	groups := grouper.Partition(updates)
+
+	want := []struct {
+		name    string
+		branch  string
+		updates int
+	}{
+		{"majors-terraform-aws-modules-eks-aws", "tfmv/{{.Group}}", 1},
+		{"majors-terraform-aws-modules-s3-bucket-aws", "tfmv/{{.Group}}", 1},
+		{"aws-patches", "", 2},
+		{"prod", "", 1},
+		{DefaultName, "", 1},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for i, w := range want {
+		if groups[i].Name != w.name || groups[i].Branch != w.branch || len(groups[i].Updates) != w.updates {
+			t.Errorf("group %d = %s (branch %q, %d updates), want %s (branch %q, %d updates)",
+				i, groups[i].Name, groups[i].Branch, len(groups[i].Updates), w.name, w.branch, w.updates)
+		}
+	}
+
+	// Updates within a group are ordered by source, version and directory
+	if dir := groups[2].Updates[0].Directory; dir != "." {
+		t.Errorf("first aws-patches update directory = %q, want \".\"", dir)
+	}
+}
+
+func TestGroupOfWithoutRules(t *testing.T) {
+	grouper, err := NewGrouper(nil)
+	if err != nil {
+		t.Fatalf("NewGrouper returned error: %v", err)
+	}
+	if grouper.HasRules() {
+		t.Error("HasRules = true without rules")
+	}
+
+	name, rule := grouper.GroupOf(Update{Source: "acme/vpc/aws", ChangeType: version.ChangeMinor, Directory: "."})
+	if name != DefaultName || rule != nil {
+		t.Errorf("GroupOf = %q, %v; want %q, nil", name, rule, DefaultName)
+	}
+}
+
+func TestNewGrouperInvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+	}{
+		{"missing name", []Rule{{Sources: []string{"acme/.*"}}}},
+		{"duplicate name", []Rule{{Name: "a"}, {Name: "a"}}},
+		{"reserved name", []Rule{{Name: DefaultName}}},
+		{"invalid change type", []Rule{{Name: "a", ChangeTypes: []string{"huge"}}}},
+		{"invalid source pattern", []Rule{{Name: "a", Sources: []string{"acme/(vpc"}}}},
+		{"invalid directory pattern", []Rule{{Name: "a", Directories: []string{"envs/["}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGrouper(tt.rules); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestModuleSlug(t *testing.T) {
+	tests := map[string]string{
+		"terraform-aws-modules/vpc/aws":            "terraform-aws-modules-vpc-aws",
+		"app.terraform.io/Acme/network_core/azure": "acme-network-core-azure",
+	}
+	for source, want := range tests {
+		if got := moduleSlug(source); got != want {
+			t.Errorf("moduleSlug(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestRule(t *testing.T) {
+	grouper, err := NewGrouper([]Rule{
+		{Name: "majors", PerModule: true},
+		{Name: "majors-aws", PerModule: true},
+		{Name: "patches"},
+	})
+	if err != nil {
+		t.Fatalf("NewGrouper returned error: %v", err)
+	}
+
+	tests := map[string]string{
+		"patches":                 "patches",
+		"majors-acme-vpc-aws":     "majors",
+		"majors-aws-acme-vpc-aws": "majors-aws",
+		"patches-acme-vpc-aws":    "",
+		DefaultName:               "",
+	}
+	for name, want := range tests {
+		rule := grouper.Rule(name)
+		got := ""
+		if rule != nil {
+			got = rule.Name
+		}
+		if got != want {
+			t.Errorf("Rule(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
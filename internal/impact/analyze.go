@@ -0,0 +1,181 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+)
+
+// Kind classifies a breaking change affecting a module call
+type Kind string
+
+const (
+	KindRemovedInput     Kind = "removed input"
+	KindRenamedInput     Kind = "renamed input"
+	KindNewRequiredInput Kind = "new required input"
+	KindRemovedOutput    Kind = "removed output"
+)
+
+// Finding is a breaking change between two module versions that affects a call
+type Finding struct {
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`             // Input or output name in the current version
+	Detail string `json:"detail,omitempty"` // Additional context, e.g., the new name of a renamed input
+}
+
+// String returns a one-line description of the finding
+func (f Finding) String() string {
+	if f.Detail == "" {
+		return fmt.Sprintf("%s %q", f.Kind, f.Name)
+	}
+	return fmt.Sprintf("%s %q (%s)", f.Kind, f.Name, f.Detail)
+}
+
+// Changes is the interface difference between two module versions
+type Changes struct {
+	RemovedInputs    []string          // Inputs of the current version missing from the target
+	RenamedInputs    map[string]string // Removed input -> likely new name
+	NewRequired      []string          // Inputs required by the target but not by the current version
+	RemovedOutputs   []string          // Outputs of the current version missing from the target
+	RemovedResources []string          // Resource addresses missing from the target, e.g., "aws_vpc.this"
+}
+
+// Diff compares the registry details of two module versions
+// Returns nil when details are missing for either version
+func Diff(current, target *registry.ModuleInfoRoot) *Changes {
+	if current == nil || target == nil {
+		return nil
+	}
+
+	changes := &Changes{RenamedInputs: make(map[string]string)}
+
+	currentInputs := make(map[string]registry.Input)
+	for _, input := range current.Inputs {
+		currentInputs[input.Name] = input
+	}
+	targetInputs := make(map[string]registry.Input)
+	for _, input := range target.Inputs {
+		targetInputs[input.Name] = input
+	}
+
+	for _, input := range current.Inputs {
+		if _, ok := targetInputs[input.Name]; !ok {
+			changes.RemovedInputs = append(changes.RemovedInputs, input.Name)
+		}
+	}
+
+	var added []registry.Input
+	for _, input := range target.Inputs {
+		previous, existed := currentInputs[input.Name]
+		if !existed {
+			added = append(added, input)
+		}
+		if input.Required && (!existed || !previous.Required) {
+			changes.NewRequired = append(changes.NewRequired, input.Name)
+		}
+	}
+
+	// An added input documented like a removed one is most likely a rename
+	for _, name := range changes.RemovedInputs {
+		removed := currentInputs[name]
+		if removed.Description == "" {
+			continue
+		}
+		for _, input := range added {
+			if input.Description == removed.Description && input.Type == removed.Type {
+				changes.RenamedInputs[name] = input.Name
+				break
+			}
+		}
+	}
+
+	targetOutputs := make(map[string]bool)
+	for _, output := range target.Outputs {
+		targetOutputs[output.Name] = true
+	}
+	for _, output := range current.Outputs {
+		if !targetOutputs[output.Name] {
+			changes.RemovedOutputs = append(changes.RemovedOutputs, output.Name)
+		}
+	}
+
+	targetResources := make(map[string]bool)
+	for _, resource := range target.Resources {
+		targetResources[resource.Type+"."+resource.Name] = true
+	}
+	for _, resource := range current.Resources {
+		address := resource.Type + "." + resource.Name
+		if !targetResources[address] {
+			changes.RemovedResources = append(changes.RemovedResources, address)
+		}
+	}
+
+	sort.Strings(changes.RemovedInputs)
+	sort.Strings(changes.NewRequired)
+	sort.Strings(changes.RemovedOutputs)
+	sort.Strings(changes.RemovedResources)
+
+	return changes
+}
+
+// Analyze cross-references the changes between two module versions with a module call
+// Only changes the call is affected by are returned: removed or renamed inputs it sets,
+// newly required inputs it does not set, and removed outputs it references
+func Analyze(current, target *registry.Version, call *finder.ModuleCall) []Finding {
+	changes := Diff(current.Details(), target.Details())
+	if changes == nil || call == nil {
+		return nil
+	}
+
+	arguments := make(map[string]bool)
+	for _, name := range call.Arguments {
+		arguments[name] = true
+	}
+	referenced := make(map[string]bool)
+	for _, name := range call.ReferencedOutputs {
+		referenced[name] = true
+	}
+
+	var findings []Finding
+	for _, name := range changes.RemovedInputs {
+		if !arguments[name] {
+			continue
+		}
+		if renamed, ok := changes.RenamedInputs[name]; ok {
+			findings = append(findings, Finding{Kind: KindRenamedInput, Name: name, Detail: "now " + renamed})
+		} else {
+			findings = append(findings, Finding{Kind: KindRemovedInput, Name: name})
+		}
+	}
+
+	for _, name := range changes.NewRequired {
+		if arguments[name] {
+			continue
+		}
+		// Renamed inputs are already reported under the old name the call sets
+		if renamedFromArgument(changes, name, arguments) {
+			continue
+		}
+		findings = append(findings, Finding{Kind: KindNewRequiredInput, Name: name})
+	}
+
+	for _, name := range changes.RemovedOutputs {
+		if referenced[name] {
+			findings = append(findings, Finding{Kind: KindRemovedOutput, Name: name})
+		}
+	}
+
+	return findings
+}
+
+// renamedFromArgument reports whether an input is the new name of an input the call sets
+func renamedFromArgument(changes *Changes, name string, arguments map[string]bool) bool {
+	for oldName, newName := range changes.RenamedInputs {
+		if newName == name && arguments[oldName] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package impact
+
+import (
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+)
+
+func version(v string, root *registry.ModuleInfoRoot) *registry.Version {
+	return &registry.Version{Version: v, RegistryModuleInfo: &registry.ModuleInfo{Root: root}}
+}
+
+func TestDiff(t *testing.T) {
+	current := &registry.ModuleInfoRoot{
+		Inputs: []registry.Input{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "enable_classiclink", Type: "bool"},
+			{Name: "azs", Type: "list(string)", Description: "Availability zones"},
+			{Name: "tags", Type: "map(string)"},
+		},
+		Outputs:   []registry.Output{{Name: "vpc_id"}, {Name: "vpc_classiclink_id"}},
+		Resources: []registry.Resource{{Type: "aws_vpc", Name: "this"}, {Type: "aws_vpc_classiclink", Name: "this"}},
+	}
+	target := &registry.ModuleInfoRoot{
+		Inputs: []registry.Input{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "availability_zones", Type: "list(string)", Description: "Availability zones", Required: true},
+			{Name: "tags", Type: "map(string)", Required: true},
+		},
+		Outputs:   []registry.Output{{Name: "vpc_id"}},
+		Resources: []registry.Resource{{Type: "aws_vpc", Name: "this"}},
+	}
+
+	changes := Diff(current, target)
+	if changes == nil {
+		t.Fatal("Diff() = nil")
+	}
+
+	if len(changes.RemovedInputs) != 2 || changes.RemovedInputs[0] != "azs" || changes.RemovedInputs[1] != "enable_classiclink" {
+		t.Errorf("RemovedInputs = %v", changes.RemovedInputs)
+	}
+	if changes.RenamedInputs["azs"] != "availability_zones" {
+		t.Errorf("RenamedInputs = %v, want azs -> availability_zones", changes.RenamedInputs)
+	}
+	if len(changes.NewRequired) != 2 || changes.NewRequired[0] != "availability_zones" || changes.NewRequired[1] != "tags" {
+		t.Errorf("NewRequired = %v", changes.NewRequired)
+	}
+	if len(changes.RemovedOutputs) != 1 || changes.RemovedOutputs[0] != "vpc_classiclink_id" {
+		t.Errorf("RemovedOutputs = %v", changes.RemovedOutputs)
+	}
+	if len(changes.RemovedResources) != 1 || changes.RemovedResources[0] != "aws_vpc_classiclink.this" {
+		t.Errorf("RemovedResources = %v", changes.RemovedResources)
+	}
+
+	if Diff(nil, target) != nil {
+		t.Error("Diff() should be nil without current details")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	current := version("3.0.0", &registry.ModuleInfoRoot{
+		Inputs: []registry.Input{
+			{Name: "name", Required: true},
+			{Name: "enable_classiclink"},
+			{Name: "azs", Type: "list(string)", Description: "Availability zones"},
+			{Name: "unused_removed"},
+		},
+		Outputs: []registry.Output{{Name: "vpc_id"}, {Name: "vpc_classiclink_id"}, {Name: "unused_output"}},
+	})
+	target := version("5.0.0", &registry.ModuleInfoRoot{
+		Inputs: []registry.Input{
+			{Name: "name", Required: true},
+			{Name: "availability_zones", Type: "list(string)", Description: "Availability zones", Required: true},
+			{Name: "cidr", Required: true},
+		},
+		Outputs: []registry.Output{{Name: "vpc_id"}},
+	})
+
+	call := &finder.ModuleCall{
+		Name:              "vpc",
+		Arguments:         []string{"azs", "enable_classiclink", "name"},
+		ReferencedOutputs: []string{"vpc_classiclink_id", "vpc_id"},
+	}
+
+	findings := Analyze(current, target, call)
+
+	want := []string{
+		`renamed input "azs" (now availability_zones)`,
+		`removed input "enable_classiclink"`,
+		`new required input "cidr"`,
+		`removed output "vpc_classiclink_id"`,
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("Analyze() = %v, want %v", findings, want)
+	}
+	for i, finding := range findings {
+		if finding.String() != want[i] {
+			t.Errorf("finding[%d] = %s, want %s", i, finding, want[i])
+		}
+	}
+
+	if findings := Analyze(current, &registry.Version{Version: "5.0.0"}, call); findings != nil {
+		t.Errorf("Analyze() without target details = %v, want nil", findings)
+	}
+}
@@ -0,0 +1,167 @@
+package interactive
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testItems() []Item {
+	published := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	return []Item{
+		{
+			Source:     "terraform-aws-modules/vpc/aws",
+			Files:      []string{"prod/main.tf"},
+			From:       "4.0.0",
+			To:         "5.1.0",
+			Candidates: []string{"5.1.0", "5.0.0", "4.1.0"},
+			Published:  map[string]time.Time{"5.1.0": published},
+			Selected:   true,
+		},
+		{
+			Source:     "terraform-aws-modules/eks/aws",
+			Files:      []string{"prod/eks.tf"},
+			From:       "19.0.0",
+			To:         "19.0.1",
+			Candidates: []string{"19.0.1"},
+			Selected:   true,
+		},
+	}
+}
+
+func noPreview(int) (string, error) {
+	return "", nil
+}
+
+func TestNewerVersions(t *testing.T) {
+	got := NewerVersions("4.0.0", []string{"3.9.0", "4.1.0", "5.1.0", "4.0.0", "latest", "5.0.0"})
+	want := []string{"5.1.0", "5.0.0", "4.1.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewerVersions() = %v, want %v", got, want)
+	}
+}
+
+func TestItemDetails(t *testing.T) {
+	item := testItems()[0]
+	if got := item.ChangeType(); got != "major" {
+		t.Errorf("ChangeType() = %q, want major", got)
+	}
+	if got := item.ReleaseAge(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)); got != "3mo" {
+		t.Errorf("ReleaseAge() = %q, want 3mo", got)
+	}
+	item.To = "5.0.0"
+	if got := item.ReleaseAge(time.Now()); got != "" {
+		t.Errorf("ReleaseAge() = %q, want empty without a publish date", got)
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	items := testItems()
+	var previewed []int
+	preview := func(index int) (string, error) {
+		previewed = append(previewed, index)
+		return "--- a/main.tf\n+++ b/main.tf\n", nil
+	}
+
+	input := "2\n1 4.1.0\n1 9.9.9\nd 1\nfoo\na\n"
+	var out bytes.Buffer
+	applied, err := prompt(strings.NewReader(input), &out, items, preview)
+	if err != nil || !applied {
+		t.Fatalf("prompt() = %v, %v, want applied", applied, err)
+	}
+
+	if items[0].To != "4.1.0" || !items[0].Selected {
+		t.Errorf("item 1 = %s selected %v, want 4.1.0 selected", items[0].To, items[0].Selected)
+	}
+	if items[1].Selected {
+		t.Error("item 2 should be deselected")
+	}
+	if !reflect.DeepEqual(previewed, []int{0}) {
+		t.Errorf("previewed items %v, want [0]", previewed)
+	}
+	for _, want := range []string{"9.9.9 is not a newer version", "unknown command \"foo\"", "+++ b/main.tf"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("prompt output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPromptEndOfInput(t *testing.T) {
+	items := testItems()
+	applied, err := prompt(strings.NewReader("none\n"), &bytes.Buffer{}, items, noPreview)
+	if err != nil || applied {
+		t.Errorf("prompt() = %v, %v, want quit at the end of input", applied, err)
+	}
+	if items[0].Selected || items[1].Selected {
+		t.Error("none should deselect every item")
+	}
+}
+
+func TestModelKeys(t *testing.T) {
+	items := testItems()
+	m := newModel(items, func(index int) (string, error) {
+		return "line 1\nline 2\nline 3\n", nil
+	}, time.Now())
+
+	for _, key := range parseKeys([]byte("\x1b[D\x1b[Dj \x1b[Ak")) {
+		m.handle(key)
+	}
+	if items[0].To != "4.1.0" {
+		t.Errorf("left twice: To = %s, want 4.1.0", items[0].To)
+	}
+	if items[1].Selected {
+		t.Error("space on the second item should deselect it")
+	}
+
+	// Pick the latest version in the version picker
+	for _, key := range []string{"v", "up", "up", "enter"} {
+		m.handle(key)
+	}
+	if items[0].To != "5.1.0" || m.view != viewList {
+		t.Errorf("version picker: To = %s, view %d", items[0].To, m.view)
+	}
+
+	m.handle("d")
+	if m.view != viewDiff || len(m.diff) != 3 {
+		t.Fatalf("d: view %d with %d diff lines", m.view, len(m.diff))
+	}
+	if !strings.Contains(m.render(), "line 3") {
+		t.Error("diff view should render the preview")
+	}
+	m.handle("esc")
+	if m.view != viewList || m.done {
+		t.Errorf("esc in the diff should return to the list, view %d done %v", m.view, m.done)
+	}
+
+	m.handle("a")
+	if !m.done || !m.applied {
+		t.Error("a should apply the selection")
+	}
+}
+
+func TestModelQuit(t *testing.T) {
+	m := newModel(testItems(), noPreview, time.Now())
+	m.handle("ctrl-c")
+	if !m.done || m.applied {
+		t.Error("ctrl-c should quit without applying")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("\x1b[A\x1b\r q\x03"))
+	want := []string{"up", "esc", "enter", "space", "q", "ctrl-c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("\x1b[32m+ added\x1b[0m", 3); got != "\x1b[32m+ a"+reset {
+		t.Errorf("truncate() = %q", got)
+	}
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate() = %q, want the line unchanged", got)
+	}
+}
@@ -0,0 +1,84 @@
+package interactive
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// Item is a pending module update offered for selection
+type Item struct {
+	Source     string
+	Files      []string             // Files declaring the blocks or version definitions to update
+	From       string               // Current version
+	To         string               // Version the item updates to, one of Candidates
+	Candidates []string             // Fetched versions newer than From, latest first
+	Published  map[string]time.Time // Publish date of candidate versions, when known
+	Selected   bool
+}
+
+// Preview returns the diff the item at index would apply with its current target version
+type Preview func(index int) (string, error)
+
+// ChangeType returns the kind of change from the current to the target version
+func (i Item) ChangeType() string {
+	change, err := version.ClassifyChange(i.From, i.To)
+	if err != nil {
+		return "unknown"
+	}
+	return string(change)
+}
+
+// ReleaseAge returns how long ago the target version was published, empty if unknown
+func (i Item) ReleaseAge(now time.Time) string {
+	published, ok := i.Published[i.To]
+	if !ok {
+		return ""
+	}
+	return formatAge(now.Sub(published))
+}
+
+// NewerVersions returns the versions newer than current, latest first
+// Versions that are not valid semver are skipped
+func NewerVersions(current string, versions []string) []string {
+	var newer []string
+	for _, v := range versions {
+		if isNewer, err := version.IsNewer(current, v); err == nil && isNewer {
+			newer = append(newer, v)
+		}
+	}
+	sort.SliceStable(newer, func(a, b int) bool {
+		cmp, _ := version.CompareVersions(newer[a], newer[b])
+		return cmp > 0
+	})
+	return newer
+}
+
+// Select lets the user review items, updating their Selected and To fields
+// A terminal UI is used when in and out are terminals, a line-based prompt otherwise
+// Returns false when the user quit without applying
+func Select(in, out *os.File, items []Item, preview Preview) (bool, error) {
+	if color.IsTTY(in) && color.IsTTY(out) {
+		return runTUI(in, out, items, preview)
+	}
+	return prompt(in, out, items, preview)
+}
+
+// formatAge renders a duration in the largest whole unit, e.g. "3d" or "5mo"
+func formatAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days < 1:
+		return "<1d"
+	case days < 31:
+		return fmt.Sprintf("%dd", days)
+	case days < 365:
+		return fmt.Sprintf("%dmo", days/30)
+	default:
+		return fmt.Sprintf("%dy", days/365)
+	}
+}
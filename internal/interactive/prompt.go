@@ -0,0 +1,121 @@
+package interactive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const promptHelp = `Commands: <n> toggle, <n> <version> pick a version, d <n> show diff, all, none, a apply, q quit`
+
+// prompt reviews items through numbered commands read line by line
+// Reaching the end of the input quits without applying
+func prompt(in io.Reader, out io.Writer, items []Item, preview Preview) (bool, error) {
+	scanner := bufio.NewScanner(in)
+	now := time.Now()
+
+	printItems(out, items, now)
+	fmt.Fprintln(out, promptHelp)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return false, scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch command := fields[0]; {
+		case command == "a" || command == "apply":
+			return true, nil
+		case command == "q" || command == "quit":
+			return false, nil
+		case command == "all" || command == "none":
+			for i := range items {
+				items[i].Selected = command == "all"
+			}
+			printItems(out, items, now)
+		case command == "d" || command == "diff":
+			index, err := itemIndex(fields, 1, items)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			diff, err := preview(index)
+			if err != nil {
+				fmt.Fprintf(out, "failed to preview %s: %v\n", items[index].Source, err)
+				continue
+			}
+			if diff == "" {
+				diff = "No changes\n"
+			}
+			fmt.Fprint(out, diff)
+		default:
+			index, err := itemIndex(fields, 0, items)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				fmt.Fprintln(out, promptHelp)
+				continue
+			}
+			if len(fields) == 1 {
+				items[index].Selected = !items[index].Selected
+			} else if err := pickVersion(&items[index], fields[1]); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			printItems(out, items, now)
+		}
+	}
+}
+
+// itemIndex parses the 1-based item number at position pos of the fields
+func itemIndex(fields []string, pos int, items []Item) (int, error) {
+	if pos >= len(fields) {
+		return 0, fmt.Errorf("missing item number")
+	}
+	n, err := strconv.Atoi(fields[pos])
+	if err != nil {
+		return 0, fmt.Errorf("unknown command %q", strings.Join(fields, " "))
+	}
+	if n < 1 || n > len(items) {
+		return 0, fmt.Errorf("no item %d: pick 1 to %d", n, len(items))
+	}
+	return n - 1, nil
+}
+
+// pickVersion sets the target version of an item, which must be one of its candidates
+func pickVersion(item *Item, version string) error {
+	for _, candidate := range item.Candidates {
+		if candidate == version {
+			item.To = version
+			item.Selected = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a newer version of %s: pick one of %s",
+		version, item.Source, strings.Join(item.Candidates, ", "))
+}
+
+// printItems lists items with their number, selection and target version
+func printItems(out io.Writer, items []Item, now time.Time) {
+	fmt.Fprintln(out, "\nPending updates:")
+	for i, item := range items {
+		mark := " "
+		if item.Selected {
+			mark = "x"
+		}
+		details := item.ChangeType()
+		if age := item.ReleaseAge(now); age != "" {
+			details += ", released " + age + " ago"
+		}
+		fmt.Fprintf(out, "  [%s] %d. %s %s → %s (%s)\n", mark, i+1, item.Source, item.From, item.To, details)
+		fmt.Fprintf(out, "         files: %s\n", strings.Join(item.Files, ", "))
+		fmt.Fprintf(out, "         versions: %s\n", strings.Join(item.Candidates, ", "))
+	}
+}
@@ -0,0 +1,76 @@
+//go:build linux
+
+package interactive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// runTUI reviews items in a full-screen terminal UI
+// Raw terminal input relies on Linux termios ioctls; other platforms use the line-based prompt
+func runTUI(in, out *os.File, items []Item, preview Preview) (bool, error) {
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return false, err
+	}
+	defer restore()
+
+	// Draw on the alternate screen with a hidden cursor, leaving the scrollback untouched
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	m := newModel(items, preview, time.Now())
+	buf := make([]byte, 64)
+	for !m.done {
+		m.width, m.height = terminalSize(out)
+		if _, err := io.WriteString(out, m.render()); err != nil {
+			return false, err
+		}
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return false, fmt.Errorf("failed to read terminal input: %w", err)
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			m.handle(key)
+			if m.done {
+				break
+			}
+		}
+	}
+	return m.applied, nil
+}
+
+// makeRaw reads key presses one at a time without echo or signals
+// Returns a function restoring the previous terminal settings
+func makeRaw(fd int) (func(), error) {
+	previous, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal settings: %w", err)
+	}
+
+	raw := *previous
+	raw.Iflag &^= unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+		return nil, fmt.Errorf("failed to configure terminal: %w", err)
+	}
+
+	return func() { _ = unix.IoctlSetTermios(fd, unix.TCSETS, previous) }, nil
+}
+
+// terminalSize returns the columns and rows of a terminal, 80x24 if unknown
+func terminalSize(f *os.File) (int, int) {
+	size, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil || size.Col == 0 || size.Row == 0 {
+		return 80, 24
+	}
+	return int(size.Col), int(size.Row)
+}
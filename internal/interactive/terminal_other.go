@@ -0,0 +1,10 @@
+//go:build !linux
+
+package interactive
+
+import "os"
+
+// runTUI falls back to the line-based prompt where raw terminal input is not supported
+func runTUI(in, out *os.File, items []Item, preview Preview) (bool, error) {
+	return prompt(in, out, items, preview)
+}
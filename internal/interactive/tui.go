@@ -0,0 +1,325 @@
+package interactive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// view is the screen shown by the terminal UI
+type view int
+
+const (
+	viewList     view = iota // Pending updates
+	viewVersions             // Version picker of the item under the cursor
+	viewDiff                 // Diff preview of the item under the cursor
+)
+
+const (
+	reverse = "\x1b[7m"
+	reset   = "\x1b[0m"
+)
+
+// model is the state of the terminal UI, updated by key presses
+type model struct {
+	items   []Item
+	preview Preview
+	now     time.Time
+
+	view    view
+	cursor  int      // Item under the cursor
+	picked  int      // Version under the cursor in the version picker
+	diff    []string // Lines of the previewed diff
+	scroll  int      // First diff line shown
+	status  string   // Message shown until the next key press
+	width   int
+	height  int
+	done    bool
+	applied bool
+}
+
+func newModel(items []Item, preview Preview, now time.Time) *model {
+	return &model{items: items, preview: preview, now: now, width: 80, height: 24}
+}
+
+// handle updates the model for a key, as named by parseKeys
+func (m *model) handle(key string) {
+	m.status = ""
+	if key == "ctrl-c" {
+		m.done = true
+		return
+	}
+
+	switch m.view {
+	case viewList:
+		m.handleList(key)
+	case viewVersions:
+		m.handleVersions(key)
+	case viewDiff:
+		m.handleDiff(key)
+	}
+}
+
+func (m *model) handleList(key string) {
+	item := &m.items[m.cursor]
+
+	switch key {
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, len(m.items)-1)
+	case "space", "x":
+		item.Selected = !item.Selected
+	case "left", "h":
+		// Older candidates come later in the list
+		if i := indexOf(item.Candidates, item.To); i < len(item.Candidates)-1 {
+			item.To = item.Candidates[i+1]
+		}
+	case "right", "l":
+		if i := indexOf(item.Candidates, item.To); i > 0 {
+			item.To = item.Candidates[i-1]
+		}
+	case "v":
+		m.view = viewVersions
+		m.picked = max(indexOf(item.Candidates, item.To), 0)
+	case "d":
+		diff, err := m.preview(m.cursor)
+		if err != nil {
+			m.status = fmt.Sprintf("failed to preview %s: %v", item.Source, err)
+			return
+		}
+		if diff == "" {
+			m.status = "No changes"
+			return
+		}
+		m.diff = strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+		m.scroll = 0
+		m.view = viewDiff
+	case "a", "enter":
+		m.done = true
+		m.applied = true
+	case "q", "esc":
+		m.done = true
+	}
+}
+
+func (m *model) handleVersions(key string) {
+	item := &m.items[m.cursor]
+
+	switch key {
+	case "up", "k":
+		m.picked = max(m.picked-1, 0)
+	case "down", "j":
+		m.picked = min(m.picked+1, len(item.Candidates)-1)
+	case "enter", "space":
+		item.To = item.Candidates[m.picked]
+		item.Selected = true
+		m.view = viewList
+	case "q", "esc", "v":
+		m.view = viewList
+	}
+}
+
+func (m *model) handleDiff(key string) {
+	page := m.bodyHeight()
+	last := max(len(m.diff)-page, 0)
+
+	switch key {
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "down", "j", "enter":
+		m.scroll = min(m.scroll+1, last)
+	case "pgup", "b":
+		m.scroll = max(m.scroll-page, 0)
+	case "pgdown", "space":
+		m.scroll = min(m.scroll+page, last)
+	case "q", "esc", "d":
+		m.view = viewList
+	}
+}
+
+// bodyHeight is the number of lines between the title and the footer
+func (m *model) bodyHeight() int {
+	return max(m.height-3, 1)
+}
+
+// render draws the whole screen
+func (m *model) render() string {
+	var title, footer string
+	var body []string
+
+	switch m.view {
+	case viewList:
+		title = fmt.Sprintf("Select updates to apply (%d of %d selected)", m.selected(), len(m.items))
+		body = m.renderList()
+		footer = "↑/↓ move  space toggle  ←/→ version  v versions  d diff  a apply  q quit"
+	case viewVersions:
+		item := m.items[m.cursor]
+		title = fmt.Sprintf("Versions of %s newer than %s", item.Source, item.From)
+		body = m.renderVersions()
+		footer = "↑/↓ move  enter select  esc back"
+	case viewDiff:
+		title = fmt.Sprintf("Diff of %s %s → %s", m.items[m.cursor].Source, m.items[m.cursor].From, m.items[m.cursor].To)
+		end := min(m.scroll+m.bodyHeight(), len(m.diff))
+		body = m.diff[m.scroll:end]
+		footer = fmt.Sprintf("↑/↓ scroll  space/b page  esc back  (lines %d-%d of %d)", m.scroll+1, end, len(m.diff))
+	}
+	if m.status != "" {
+		footer = m.status
+	}
+
+	var screen strings.Builder
+	screen.WriteString("\x1b[H\x1b[2J")
+	screen.WriteString(truncate(title, m.width) + "\n\n")
+	for _, line := range body {
+		screen.WriteString(truncate(line, m.width) + "\n")
+	}
+	for i := len(body); i < m.bodyHeight(); i++ {
+		screen.WriteString("\n")
+	}
+	screen.WriteString(truncate(footer, m.width))
+	return screen.String()
+}
+
+// renderList lists the items around the cursor, then the files of the item under it
+func (m *model) renderList() []string {
+	sourceWidth, fromWidth, toWidth := 0, 0, 0
+	for _, item := range m.items {
+		sourceWidth = max(sourceWidth, utf8.RuneCountInString(item.Source))
+		fromWidth = max(fromWidth, len(item.From))
+		toWidth = max(toWidth, len(item.To))
+	}
+
+	// Two lines are kept for the files of the item under the cursor
+	rows := max(m.bodyHeight()-2, 1)
+	first := min(max(m.cursor-rows/2, 0), max(len(m.items)-rows, 0))
+	last := min(first+rows, len(m.items))
+
+	var lines []string
+	for i := first; i < last; i++ {
+		item := m.items[i]
+		mark := " "
+		if item.Selected {
+			mark = "x"
+		}
+		line := fmt.Sprintf(" [%s] %-*s  %*s → %-*s  %-5s  %s",
+			mark, sourceWidth, item.Source, fromWidth, item.From, toWidth, item.To,
+			item.ChangeType(), item.ReleaseAge(m.now))
+		if i == m.cursor {
+			line = reverse + truncate(line, m.width) + reset
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", "Files: "+strings.Join(m.items[m.cursor].Files, ", "))
+	return lines
+}
+
+// renderVersions lists the candidate versions of the item under the cursor
+func (m *model) renderVersions() []string {
+	item := m.items[m.cursor]
+	rows := m.bodyHeight()
+	first := min(max(m.picked-rows/2, 0), max(len(item.Candidates)-rows, 0))
+	last := min(first+rows, len(item.Candidates))
+
+	var lines []string
+	for i := first; i < last; i++ {
+		candidate := Item{From: item.From, To: item.Candidates[i], Published: item.Published}
+		current := " "
+		if candidate.To == item.To {
+			current = "*"
+		}
+		line := fmt.Sprintf(" %s %-12s  %-5s  %s", current, candidate.To, candidate.ChangeType(), candidate.ReleaseAge(m.now))
+		if i == m.picked {
+			line = reverse + truncate(line, m.width) + reset
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (m *model) selected() int {
+	count := 0
+	for _, item := range m.items {
+		if item.Selected {
+			count++
+		}
+	}
+	return count
+}
+
+// parseKeys names the keys of the bytes read from a raw terminal
+func parseKeys(input []byte) []string {
+	sequences := map[string]string{
+		"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left",
+		"\x1bOA": "up", "\x1bOB": "down", "\x1bOC": "right", "\x1bOD": "left",
+		"\x1b[5~": "pgup", "\x1b[6~": "pgdown",
+	}
+
+	var keys []string
+	for s := string(input); s != ""; {
+		if s[0] == '\x1b' {
+			matched := false
+			for seq, name := range sequences {
+				if strings.HasPrefix(s, seq) {
+					keys = append(keys, name)
+					s = s[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				keys = append(keys, "esc")
+				s = s[1:]
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case ' ':
+			keys = append(keys, "space")
+		case 3:
+			keys = append(keys, "ctrl-c")
+		default:
+			keys = append(keys, string(r))
+		}
+	}
+	return keys
+}
+
+// truncate cuts a line to width visible characters, ignoring ANSI escape sequences
+func truncate(line string, width int) string {
+	visible := 0
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			// Skip the sequence up to its final letter
+			j := i + 1
+			for j < len(line) && !(line[j] >= 'A' && line[j] <= 'Z' || line[j] >= 'a' && line[j] <= 'z') {
+				j++
+			}
+			i = j + 1
+			continue
+		}
+		if visible == width {
+			return line[:i] + reset
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		i += size
+		visible++
+	}
+	return line
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
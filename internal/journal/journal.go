@@ -0,0 +1,369 @@
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/plan"
+	"github.com/vdesjardins/terraform-module-versions/internal/updater"
+)
+
+// States of a run
+const (
+	StatePending    = "pending"     // Writing files; a pending run that is not running was interrupted
+	StateCompleted  = "completed"   // Every file was written
+	StateRolledBack = "rolled-back" // Files were restored after a failure or interruption
+	StateUndone     = "undone"      // Files were restored by undo after the run completed
+)
+
+// ErrNoRun is returned when no run matches
+var ErrNoRun = errors.New("no matching run in the journal")
+
+// File is a file written by a run
+type File struct {
+	Path         string   `json:"path"`              // Absolute path
+	Original     []byte   `json:"original"`          // Content before the run
+	OriginalHash string   `json:"original_hash"`     // SHA-256 of Original, empty when Created
+	Hashes       []string `json:"hashes"`            // SHA-256 of each content the run wrote, last is current; empty for a deletion
+	Created      bool     `json:"created,omitempty"` // The file did not exist before the run
+}
+
+// Run journals the files written by one update run, so they can be restored
+type Run struct {
+	ID        string      `json:"id"`
+	StartedAt time.Time   `json:"started_at"`
+	Directory string      `json:"directory"` // Absolute path of the updated directory
+	State     string      `json:"state"`
+	Edits     []plan.Edit `json:"edits"` // Edits of the run, relative to Directory
+	Files     []*File     `json:"files"`
+
+	path string // Journal file of the run
+}
+
+// Journal stores runs as JSON files in a directory
+type Journal struct {
+	dir string
+}
+
+// Open returns the journal stored in dir, created on the first run
+func Open(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+// Begin starts and saves a pending run applying edits to a directory
+func (j *Journal) Begin(directory string, edits []plan.Edit, now time.Time) (*Run, error) {
+	if err := os.MkdirAll(j.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	abs, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", directory, err)
+	}
+
+	id := now.UTC().Format("20060102T150405Z")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(j.runPath(id)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.UTC().Format("20060102T150405Z"), n)
+	}
+
+	run := &Run{ID: id, StartedAt: now.UTC(), Directory: abs, State: StatePending, Edits: edits, path: j.runPath(id)}
+	if err := run.save(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Load returns a run by ID
+func (j *Journal) Load(id string) (*Run, error) {
+	data, err := os.ReadFile(j.runPath(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("run %s: %w", id, ErrNoRun)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse journal of run %s: %w", id, err)
+	}
+	run.path = j.runPath(id)
+	return &run, nil
+}
+
+// Runs returns the journaled runs, oldest first
+func (j *Journal) Runs() ([]*Run, error) {
+	entries, err := os.ReadDir(j.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	var runs []*Run
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		run, err := j.Load(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	sort.Slice(runs, func(a, b int) bool {
+		if !runs[a].StartedAt.Equal(runs[b].StartedAt) {
+			return runs[a].StartedAt.Before(runs[b].StartedAt)
+		}
+		return runs[a].ID < runs[b].ID
+	})
+	return runs, nil
+}
+
+// Interrupted returns the latest pending run, ErrNoRun if none
+func (j *Journal) Interrupted() (*Run, error) {
+	runs, err := j.Runs()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].State == StatePending {
+			return runs[i], nil
+		}
+	}
+	return nil, ErrNoRun
+}
+
+// Latest returns the latest run that can be undone, pending or completed; ErrNoRun if none
+func (j *Journal) Latest() (*Run, error) {
+	runs, err := j.Runs()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].State == StatePending || runs[i].State == StateCompleted {
+			return runs[i], nil
+		}
+	}
+	return nil, ErrNoRun
+}
+
+func (j *Journal) runPath(id string) string {
+	return filepath.Join(j.dir, id+".json")
+}
+
+// Write writes content to path atomically
+// The original content and the hash of content are journaled before the file is written
+func (r *Run) Write(path string, content []byte) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	file := r.file(abs)
+	if file == nil {
+		original, err := os.ReadFile(abs)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		file = &File{Path: abs, Original: original, OriginalHash: hash(original)}
+		r.Files = append(r.Files, file)
+	}
+	file.Hashes = append(file.Hashes, hash(content))
+
+	if err := r.save(); err != nil {
+		return err
+	}
+	return updater.WriteFile(abs, content)
+}
+
+// Track journals a file changed during the run by something else than Write, e.g.,
+// a post-update hook, from its content before the change
+// existed is false when the file was created; a file deleted since is journaled as such
+func (r *Run) Track(path string, original []byte, existed bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	current, err := currentHash(abs)
+	if err != nil {
+		return err
+	}
+
+	file := r.file(abs)
+	if file == nil {
+		file = &File{Path: abs, Original: original, Created: !existed}
+		if existed {
+			file.OriginalHash = hash(original)
+		}
+		r.Files = append(r.Files, file)
+	}
+	file.Hashes = append(file.Hashes, current)
+	return r.save()
+}
+
+// Restore writes back the original content of a file and stops journaling it
+func (r *Run) Restore(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	for i, file := range r.Files {
+		if file.Path != abs {
+			continue
+		}
+		if err := updater.WriteFile(abs, file.Original); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		r.Files = append(r.Files[:i], r.Files[i+1:]...)
+		return r.save()
+	}
+	return nil
+}
+
+// Complete marks the run as completed with the edits it kept
+func (r *Run) Complete(edits []plan.Edit) error {
+	r.State = StateCompleted
+	r.Edits = edits
+	return r.save()
+}
+
+// Rollback restores the original content of every file the run wrote
+// Files changed by something else since the run wrote them are left alone and reported
+// Returns the restored files
+func (r *Run) Rollback() ([]string, error) {
+	var restored, conflicts []string
+
+	for i := len(r.Files) - 1; i >= 0; i-- {
+		file := r.Files[i]
+		current, err := currentHash(file.Path)
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", file.Path, err))
+			continue
+		}
+
+		if current == file.OriginalHash {
+			continue
+		}
+		if !containsString(file.Hashes, current) {
+			conflicts = append(conflicts, fmt.Sprintf("%s: modified since the run", file.Path))
+			continue
+		}
+
+		if err := file.restore(); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", file.Path, err))
+			continue
+		}
+		restored = append(restored, file.Path)
+	}
+
+	if len(conflicts) > 0 {
+		return restored, fmt.Errorf("failed to restore %d files: %s", len(conflicts), strings.Join(conflicts, "; "))
+	}
+
+	r.State = StateRolledBack
+	return restored, r.save()
+}
+
+// Undo restores the files of a completed run, reverting its edits
+// Nothing is written unless every file still holds the content the run wrote
+// Returns the restored files
+func (r *Run) Undo() ([]string, error) {
+	if r.State != StateCompleted {
+		return nil, fmt.Errorf("run %s is %s, only completed runs can be undone", r.ID, r.State)
+	}
+
+	var modified []string
+	for _, file := range r.Files {
+		current, err := currentHash(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Hashes) == 0 || current != file.Hashes[len(file.Hashes)-1] {
+			modified = append(modified, file.Path)
+		}
+	}
+	if len(modified) > 0 {
+		return nil, fmt.Errorf("files modified since run %s: %s", r.ID, strings.Join(modified, ", "))
+	}
+
+	restored, err := r.Rollback()
+	if err != nil {
+		return restored, err
+	}
+	r.State = StateUndone
+	return restored, r.save()
+}
+
+func (r *Run) file(path string) *File {
+	for _, file := range r.Files {
+		if file.Path == path {
+			return file
+		}
+	}
+	return nil
+}
+
+// save writes the journal of the run atomically
+func (r *Run) save() error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	if err := updater.WriteFile(r.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// restore writes back the original content of a file, or removes a file the run created
+func (f *File) restore() error {
+	if !f.Created {
+		return updater.WriteFile(f.Path, f.Original)
+	}
+	if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// currentHash returns the SHA-256 of a file's content, empty when the file does not exist
+func currentHash(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	return hash(content), nil
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
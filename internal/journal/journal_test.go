@@ -0,0 +1,287 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupRun(t *testing.T) (*Journal, *Run, string) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "test-journal-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	workDir := filepath.Join(tmpDir, "work")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatalf("failed to create work dir: %v", err)
+	}
+	for _, name := range []string{"a.tf", "b.tf"} {
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte("original "+name), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	j := Open(filepath.Join(tmpDir, "journal"))
+	run, err := j.Begin(workDir, nil, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	return j, run, workDir
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestRollbackInterrupted(t *testing.T) {
+	j, run, workDir := setupRun(t)
+	a, b := filepath.Join(workDir, "a.tf"), filepath.Join(workDir, "b.tf")
+
+	if err := run.Write(a, []byte("step 1")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := run.Write(a, []byte("step 2")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := run.Write(b, []byte("updated b")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// The run never completed: it is found from the journal on disk
+	interrupted, err := j.Interrupted()
+	if err != nil {
+		t.Fatalf("Interrupted() error = %v", err)
+	}
+	if interrupted.ID != "20261018T120000Z" || len(interrupted.Files) != 2 {
+		t.Fatalf("Interrupted() = %s with %d files", interrupted.ID, len(interrupted.Files))
+	}
+
+	restored, err := interrupted.Rollback()
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if len(restored) != 2 {
+		t.Errorf("Rollback() restored %v, want both files", restored)
+	}
+	if got := readFile(t, a); got != "original a.tf" {
+		t.Errorf("a.tf = %q after rollback", got)
+	}
+	if got := readFile(t, b); got != "original b.tf" {
+		t.Errorf("b.tf = %q after rollback", got)
+	}
+
+	if _, err := j.Interrupted(); !errors.Is(err, ErrNoRun) {
+		t.Errorf("Interrupted() after rollback error = %v, want ErrNoRun", err)
+	}
+}
+
+func TestRollbackModifiedFile(t *testing.T) {
+	_, run, workDir := setupRun(t)
+	a, b := filepath.Join(workDir, "a.tf"), filepath.Join(workDir, "b.tf")
+
+	if err := run.Write(a, []byte("updated a")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := run.Write(b, []byte("updated b")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := os.WriteFile(b, []byte("edited by hand"), 0644); err != nil {
+		t.Fatalf("failed to edit file: %v", err)
+	}
+
+	restored, err := run.Rollback()
+	if err == nil || !strings.Contains(err.Error(), "b.tf: modified since the run") {
+		t.Errorf("Rollback() error = %v, want b.tf modified", err)
+	}
+	if len(restored) != 1 || restored[0] != a {
+		t.Errorf("Rollback() restored %v, want only a.tf", restored)
+	}
+	if got := readFile(t, b); got != "edited by hand" {
+		t.Errorf("b.tf = %q, want the manual edit kept", got)
+	}
+	if run.State != StatePending {
+		t.Errorf("State = %s, want %s until every file is restored", run.State, StatePending)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	j, run, workDir := setupRun(t)
+
+	if err := run.Write(filepath.Join(workDir, "a.tf"), []byte("updated a")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := run.Complete(nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if _, err := j.Interrupted(); !errors.Is(err, ErrNoRun) {
+		t.Errorf("Interrupted() error = %v, want ErrNoRun", err)
+	}
+
+	// A second run in the same second gets its own ID
+	second, err := j.Begin(workDir, nil, run.StartedAt)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if second.ID == run.ID {
+		t.Errorf("Begin() reused run ID %s", run.ID)
+	}
+
+	runs, err := j.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].State != StateCompleted || runs[1].State != StatePending {
+		t.Errorf("Runs() = %d runs, want the completed run then the pending one", len(runs))
+	}
+}
+
+func TestUndo(t *testing.T) {
+	j, run, workDir := setupRun(t)
+	a, b := filepath.Join(workDir, "a.tf"), filepath.Join(workDir, "b.tf")
+
+	if err := run.Write(a, []byte("updated a")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := run.Write(b, []byte("updated b")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := run.Complete(nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	// A file changed since the run: nothing is reverted
+	if err := os.WriteFile(b, []byte("edited by hand"), 0644); err != nil {
+		t.Fatalf("failed to edit file: %v", err)
+	}
+	latest, err := j.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if _, err := latest.Undo(); err == nil || !strings.Contains(err.Error(), "b.tf") {
+		t.Errorf("Undo() error = %v, want b.tf modified", err)
+	}
+	if got := readFile(t, a); got != "updated a" {
+		t.Errorf("a.tf = %q, want the run's content kept", got)
+	}
+
+	if err := os.WriteFile(b, []byte("updated b"), 0644); err != nil {
+		t.Fatalf("failed to edit file: %v", err)
+	}
+	restored, err := latest.Undo()
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if len(restored) != 2 || readFile(t, a) != "original a.tf" || readFile(t, b) != "original b.tf" {
+		t.Errorf("Undo() restored %v", restored)
+	}
+
+	undone, err := j.Load(run.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if undone.State != StateUndone {
+		t.Errorf("State = %s, want %s", undone.State, StateUndone)
+	}
+	if _, err := j.Latest(); !errors.Is(err, ErrNoRun) {
+		t.Errorf("Latest() error = %v, want ErrNoRun", err)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	_, run, workDir := setupRun(t)
+	a, b := filepath.Join(workDir, "a.tf"), filepath.Join(workDir, "b.tf")
+
+	for _, path := range []string{a, b} {
+		if err := run.Write(path, []byte("updated")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := run.Restore(a); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := readFile(t, a); got != "original a.tf" {
+		t.Errorf("a.tf = %q after Restore()", got)
+	}
+	if len(run.Files) != 1 || run.Files[0].Path != b {
+		t.Errorf("Files = %d after Restore(), want only b.tf", len(run.Files))
+	}
+}
+
+func TestTrack(t *testing.T) {
+	j, run, workDir := setupRun(t)
+	a, b, lock := filepath.Join(workDir, "a.tf"), filepath.Join(workDir, "b.tf"), filepath.Join(workDir, ".terraform.lock.hcl")
+
+	if err := run.Write(a, []byte("updated")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// A hook formats b.tf and creates the lock file
+	if err := os.WriteFile(b, []byte("formatted"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := run.Track(b, []byte("original b.tf"), true); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if err := os.WriteFile(lock, []byte("lock"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := run.Track(lock, nil, false); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if err := run.Complete(nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	loaded, err := j.Load(run.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	restored, err := loaded.Undo()
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if len(restored) != 3 {
+		t.Errorf("Undo() restored %v, want 3 files", restored)
+	}
+	if got := readFile(t, a); got != "original a.tf" {
+		t.Errorf("a.tf = %q after Undo()", got)
+	}
+	if got := readFile(t, b); got != "original b.tf" {
+		t.Errorf("b.tf = %q after Undo()", got)
+	}
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("Undo() kept the created lock file")
+	}
+}
+
+func TestTrackDeletedFile(t *testing.T) {
+	_, run, workDir := setupRun(t)
+	b := filepath.Join(workDir, "b.tf")
+
+	if err := os.Remove(b); err != nil {
+		t.Fatalf("failed to remove test file: %v", err)
+	}
+	if err := run.Track(b, []byte("original b.tf"), true); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	if _, err := run.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if got := readFile(t, b); got != "original b.tf" {
+		t.Errorf("b.tf = %q after Rollback()", got)
+	}
+}
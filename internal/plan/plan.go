@@ -0,0 +1,360 @@
+package plan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the plan file format
+const FormatVersion = 1
+
+// Kinds of edits
+const (
+	KindModule    = "module"    // Version attribute of a module block
+	KindReference = "reference" // local or variable a module version is read from
+	KindPin       = "pin"       // Version attribute inserted into an unpinned module block
+	KindProvider  = "provider"  // Provider version constraint
+)
+
+// Edit replaces Old with New at a byte offset of a file
+type Edit struct {
+	Kind   string `json:"kind"`
+	File   string `json:"file"`   // Path relative to the plan directory
+	Line   int    `json:"line"`   // Line of the edited block or attribute
+	Block  string `json:"block"`  // e.g., "module.vpc", "local.vpc_version", "provider.aws"
+	Source string `json:"source"` // Module or provider source
+	From   string `json:"from"`   // Version or constraint before the edit
+	To     string `json:"to"`     // Version or constraint after the edit
+	Offset int    `json:"offset"` // Byte offset of Old in the planned content of File
+	Old    string `json:"old"`    // Text replaced
+	New    string `json:"new"`    // Replacement text
+}
+
+// End returns the byte offset following Old
+func (e Edit) End() int {
+	return e.Offset + len(e.Old)
+}
+
+// Plan records every edit of an update run and the hash of each file it edits
+type Plan struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	Directory string            `json:"directory"` // Absolute path of the analyzed directory
+	Hashes    map[string]string `json:"hashes"`    // File -> SHA-256 of its content when planned
+	Edits     []Edit            `json:"edits"`
+
+	root      string            // Directory as given, prefixed to file paths shown to users
+	originals map[string][]byte // Planned content of edited files
+}
+
+// New returns an empty plan for a directory
+func New(dir string, now time.Time) (*Plan, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	return &Plan{
+		Version:   FormatVersion,
+		CreatedAt: now.UTC(),
+		Directory: abs,
+		Hashes:    make(map[string]string),
+		root:      dir,
+		originals: make(map[string][]byte),
+	}, nil
+}
+
+// Load reads a plan file
+// dir overrides the planned directory when not empty
+// Plans editing files outside of the directory or without a recorded hash are refused
+func Load(path, dir string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if p.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported plan version %d in %s", p.Version, path)
+	}
+	if p.Hashes == nil {
+		p.Hashes = make(map[string]string)
+	}
+
+	// Edits stay within the planned directory and apply to the content that was planned
+	for _, file := range p.Files() {
+		if !filepath.IsLocal(filepath.FromSlash(file)) {
+			return nil, fmt.Errorf("invalid plan %s: %s is outside of the planned directory", path, file)
+		}
+		if _, ok := p.Hashes[file]; !ok {
+			return nil, fmt.Errorf("invalid plan %s: no hash recorded for %s", path, file)
+		}
+	}
+
+	p.root = p.Directory
+	if dir != "" {
+		p.root = dir
+		if p.Directory, err = filepath.Abs(dir); err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+		}
+	}
+	p.originals = make(map[string][]byte)
+
+	return &p, nil
+}
+
+// Save writes the plan as indented JSON
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// Path returns the path of a planned file as seen from the working directory
+func (p *Plan) Path(file string) string {
+	return filepath.Join(p.root, file)
+}
+
+// Add plans the change made by update to the content of the file at path
+// update must change a single span of the content; unchanged content plans nothing
+// Returns whether an edit was added
+func (p *Plan) Add(edit Edit, path string, update func(content []byte) ([]byte, bool, error)) (bool, error) {
+	file, err := p.relative(path)
+	if err != nil {
+		return false, err
+	}
+
+	content, err := p.original(file)
+	if err != nil {
+		return false, err
+	}
+
+	updated, changed, err := update(content)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	edit.File = file
+	edit.Offset, edit.Old, edit.New = changedSpan(content, updated)
+
+	for _, existing := range p.Edits {
+		overlaps := existing.Offset < edit.End() && edit.Offset < existing.End() || existing.Offset == edit.Offset
+		if existing.File != file || !overlaps {
+			continue
+		}
+		if existing.Offset == edit.Offset && existing.Old == edit.Old && existing.New == edit.New {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %s edit overlaps %s edit at byte %d", path, edit.Block, existing.Block, existing.Offset)
+	}
+
+	p.Edits = append(p.Edits, edit)
+	return true, nil
+}
+
+// Files returns the edited files, sorted
+func (p *Plan) Files() []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, edit := range p.Edits {
+		if !seen[edit.File] {
+			seen[edit.File] = true
+			files = append(files, edit.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// FileEdits returns the edits of a file, by offset
+func (p *Plan) FileEdits(file string) []Edit {
+	var edits []Edit
+	for _, edit := range p.Edits {
+		if edit.File == file {
+			edits = append(edits, edit)
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Offset < edits[j].Offset })
+	return edits
+}
+
+// Verify checks that no edited file changed since the plan was made
+func (p *Plan) Verify() error {
+	var changed []string
+	for _, file := range p.Files() {
+		if _, err := p.original(file); err != nil {
+			changed = append(changed, file)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("files changed since the plan was made: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// Render returns the planned and updated content of a file
+func (p *Plan) Render(file string) ([]byte, []byte, error) {
+	content, err := p.original(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, err := ApplyEdits(content, p.FileEdits(file))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", p.Path(file), err)
+	}
+	return content, updated, nil
+}
+
+// Applier writes the edits of a plan in steps; each written file holds every edit
+// applied to it so far
+type Applier struct {
+	plan    *Plan
+	write   func(path string, content []byte) error
+	applied map[string][]Edit
+}
+
+// NewApplier returns an applier writing files with write
+func (p *Plan) NewApplier(write func(path string, content []byte) error) *Applier {
+	return &Applier{plan: p, write: write, applied: make(map[string][]Edit)}
+}
+
+// Apply writes edits on top of those already applied
+// Files changed since the plan was made are refused
+func (a *Applier) Apply(edits []Edit) error {
+	var files []string
+	byFile := make(map[string][]Edit)
+	for _, edit := range edits {
+		if _, ok := byFile[edit.File]; !ok {
+			files = append(files, edit.File)
+		}
+		byFile[edit.File] = append(byFile[edit.File], edit)
+	}
+
+	for _, file := range files {
+		content, err := a.plan.original(file)
+		if err != nil {
+			return err
+		}
+
+		applied := append(append([]Edit{}, a.applied[file]...), byFile[file]...)
+		updated, err := ApplyEdits(content, applied)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.plan.Path(file), err)
+		}
+		if err := a.write(a.plan.Path(file), updated); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", a.plan.Path(file), err)
+		}
+		a.applied[file] = applied
+	}
+
+	return nil
+}
+
+// original returns the planned content of a file, reading it on first use
+// The content must match the recorded hash when the file has one
+func (p *Plan) original(file string) ([]byte, error) {
+	if content, ok := p.originals[file]; ok {
+		return content, nil
+	}
+
+	content, err := os.ReadFile(p.Path(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", p.Path(file), err)
+	}
+
+	hash := Hash(content)
+	if recorded, ok := p.Hashes[file]; ok && recorded != hash {
+		return nil, fmt.Errorf("%s changed since the plan was made", p.Path(file))
+	}
+	p.Hashes[file] = hash
+	p.originals[file] = content
+	return content, nil
+}
+
+// relative returns a path relative to the plan directory
+func (p *Plan) relative(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(p.Directory, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside of %s", path, p.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// Hash returns the hex SHA-256 of content
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ApplyEdits applies non-overlapping edits to content, checking the text each one replaces
+func ApplyEdits(content []byte, edits []Edit) ([]byte, error) {
+	sorted := append([]Edit{}, edits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Offset > sorted[j].Offset })
+
+	updated := append([]byte{}, content...)
+	limit := len(content)
+	for _, edit := range sorted {
+		if edit.Offset < 0 || edit.End() > limit {
+			return nil, fmt.Errorf("%s edit at byte %d is out of range or overlaps another edit", edit.Block, edit.Offset)
+		}
+		if string(content[edit.Offset:edit.End()]) != edit.Old {
+			return nil, fmt.Errorf("%s edit at byte %d does not match %q", edit.Block, edit.Offset, edit.Old)
+		}
+
+		var next []byte
+		next = append(next, updated[:edit.Offset]...)
+		next = append(next, edit.New...)
+		next = append(next, updated[edit.End():]...)
+		updated = next
+		limit = edit.Offset
+	}
+
+	return updated, nil
+}
+
+// changedSpan returns the offset, old text and new text of the whole lines that differ
+// between content and updated; a pure insertion at the start of a line only spans the inserted lines
+func changedSpan(content, updated []byte) (int, string, string) {
+	prefix := 0
+	for prefix < len(content) && prefix < len(updated) && content[prefix] == updated[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(content)-prefix && suffix < len(updated)-prefix &&
+		content[len(content)-1-suffix] == updated[len(updated)-1-suffix] {
+		suffix++
+	}
+
+	start := bytes.LastIndexByte(content[:prefix], '\n') + 1
+	end := len(content) - suffix
+	if end > start || start != prefix {
+		if i := bytes.IndexByte(content[end:], '\n'); i >= 0 {
+			end += i + 1
+		} else {
+			end = len(content)
+		}
+	}
+
+	newEnd := len(updated) - (len(content) - end)
+	return start, string(content[start:end]), string(updated[start:newEnd])
+}
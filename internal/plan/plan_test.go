@@ -0,0 +1,189 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const mainTF = `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "4.0.0"
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "19.0.0"
+}
+`
+
+// replace returns an update replacing old with new once
+func replace(old, new string) func([]byte) ([]byte, bool, error) {
+	return func(content []byte) ([]byte, bool, error) {
+		if !strings.Contains(string(content), old) {
+			return content, false, nil
+		}
+		return []byte(strings.Replace(string(content), old, new, 1)), true, nil
+	}
+}
+
+func newTestPlan(t *testing.T) (*Plan, string) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "test-plan-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte(mainTF), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	p, err := New(tmpDir, time.Now())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return p, tmpDir
+}
+
+func TestAddAndRender(t *testing.T) {
+	p, tmpDir := newTestPlan(t)
+	file := filepath.Join(tmpDir, "main.tf")
+
+	added, err := p.Add(Edit{Kind: KindModule, Block: "module.vpc"}, file, replace(`"4.0.0"`, `"5.1.0"`))
+	if err != nil || !added {
+		t.Fatalf("Add(vpc) = %v, %v", added, err)
+	}
+	added, err = p.Add(Edit{Kind: KindModule, Block: "module.eks"}, file, replace(`"19.0.0"`, `"20.0.0"`))
+	if err != nil || !added {
+		t.Fatalf("Add(eks) = %v, %v", added, err)
+	}
+
+	// The same edit planned twice is recorded once
+	added, err = p.Add(Edit{Kind: KindModule, Block: "module.vpc"}, file, replace(`"4.0.0"`, `"5.1.0"`))
+	if err != nil || added {
+		t.Errorf("Add(vpc again) = %v, %v, want false, nil", added, err)
+	}
+
+	edit := p.Edits[0]
+	if edit.File != "main.tf" || edit.Old != "  version = \"4.0.0\"\n" || edit.New != "  version = \"5.1.0\"\n" {
+		t.Errorf("edit = %+v, want the whole version line of main.tf", edit)
+	}
+
+	_, updated, err := p.Render("main.tf")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := strings.NewReplacer(`"4.0.0"`, `"5.1.0"`, `"19.0.0"`, `"20.0.0"`).Replace(mainTF)
+	if string(updated) != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", updated, want)
+	}
+}
+
+func TestAddOverlap(t *testing.T) {
+	p, tmpDir := newTestPlan(t)
+	file := filepath.Join(tmpDir, "main.tf")
+
+	if _, err := p.Add(Edit{Block: "module.vpc"}, file, replace(`"4.0.0"`, `"5.1.0"`)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := p.Add(Edit{Block: "module.vpc"}, file, replace(`"4.0.0"`, `"4.1.0"`)); err == nil {
+		t.Error("Add() of an overlapping edit should fail")
+	}
+}
+
+func TestSaveLoadApply(t *testing.T) {
+	p, tmpDir := newTestPlan(t)
+	file := filepath.Join(tmpDir, "main.tf")
+
+	if _, err := p.Add(Edit{Kind: KindModule, Block: "module.vpc"}, file, replace(`"4.0.0"`, `"5.1.0"`)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := p.Add(Edit{Kind: KindModule, Block: "module.eks"}, file, replace(`"19.0.0"`, `"20.0.0"`)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	planFile := filepath.Join(tmpDir, "plan.json")
+	if err := p.Save(planFile); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(planFile, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := loaded.Verify(); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	// Edits applied in steps accumulate in the written file
+	write := func(path string, content []byte) error { return os.WriteFile(path, content, 0644) }
+	applier := loaded.NewApplier(write)
+	for _, edit := range loaded.Edits {
+		if err := applier.Apply([]Edit{edit}); err != nil {
+			t.Fatalf("Apply(%s) error = %v", edit.Block, err)
+		}
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read updated file: %v", err)
+	}
+	want := strings.NewReplacer(`"4.0.0"`, `"5.1.0"`, `"19.0.0"`, `"20.0.0"`).Replace(mainTF)
+	if string(content) != want {
+		t.Errorf("applied content =\n%s\nwant\n%s", content, want)
+	}
+
+	// Once the file changed, the plan no longer applies
+	again, err := Load(planFile, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := again.Verify(); err == nil || !strings.Contains(err.Error(), "main.tf") {
+		t.Errorf("Verify() error = %v, want main.tf changed", err)
+	}
+	if err := again.NewApplier(write).Apply(again.Edits); err == nil {
+		t.Error("Apply() on a changed file should fail")
+	}
+}
+
+func TestApplyEditsMismatch(t *testing.T) {
+	edits := []Edit{{Block: "module.vpc", Offset: 0, Old: "module \"eks\"", New: "module \"x\""}}
+	if _, err := ApplyEdits([]byte(mainTF), edits); err == nil {
+		t.Error("ApplyEdits() should refuse text that does not match")
+	}
+}
+
+func TestLoadRejectsUnsafeEdits(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		hash bool
+		want string
+	}{
+		{name: "parent directory", file: "../outside.tf", hash: true, want: "outside of the planned directory"},
+		{name: "absolute path", file: "/etc/outside.tf", hash: true, want: "outside of the planned directory"},
+		{name: "missing hash", file: "main.tf", want: "no hash recorded for main.tf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, tmpDir := newTestPlan(t)
+			p.Edits = []Edit{{Kind: KindModule, File: tt.file, Block: "module.vpc", Old: "4.0.0", New: "5.1.0"}}
+			if tt.hash {
+				p.Hashes[tt.file] = Hash([]byte(mainTF))
+			}
+
+			planFile := filepath.Join(tmpDir, "plan.json")
+			if err := p.Save(planFile); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			if _, err := Load(planFile, ""); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/vdesjardins/terraform-module-versions/internal/cache"
@@ -16,6 +17,23 @@ type Client struct {
 	httpClient *http.Client
 	timeout    time.Duration
 	store      cache.Store
+	requests   atomic.Int64
+	cacheHits  atomic.Int64
+}
+
+// Stats counts the registry lookups of a client
+type Stats struct {
+	Requests  int // HTTP requests sent to registries
+	CacheHits int // Lookups answered from the cache
+	Errors    int // Modules or providers whose lookup failed
+}
+
+// Stats returns the request and cache hit counts of the client
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests:  int(c.requests.Load()),
+		CacheHits: int(c.cacheHits.Load()),
+	}
 }
 
 // NewClient creates a new registry client with configured timeout
@@ -47,6 +65,7 @@ func (c *Client) FetchModuleVersions(ctx context.Context, registryHost, namespac
 			if jsonBytes, err := json.Marshal(cachedData); err == nil {
 				var module Module
 				if err := json.Unmarshal(jsonBytes, &module); err == nil {
+					c.cacheHits.Add(1)
 					return &module, nil
 				}
 			}
@@ -64,6 +83,7 @@ func (c *Client) FetchModuleVersions(ctx context.Context, registryHost, namespac
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	c.requests.Add(1)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("registry API call failed: %w", err)
@@ -89,7 +109,7 @@ func (c *Client) FetchModuleVersions(ctx context.Context, registryHost, namespac
 	if c.store != nil {
 		if data, err := json.Marshal(module); err == nil {
 			// Cache for 24 hours
-			c.store.Set(cacheKey, data, 24*time.Hour)
+			c.store.Set(cacheKey, json.RawMessage(data), 24*time.Hour)
 		}
 	}
 
@@ -111,6 +131,7 @@ func (c *Client) FetchModuleInfo(ctx context.Context, registryHost, namespace, n
 					if err := json.Unmarshal(jsonBytes, &info); err == nil {
 						v.RegistryModuleInfo = &info
 						cacheHit = true
+						c.cacheHits.Add(1)
 					}
 				}
 			}
@@ -130,6 +151,7 @@ func (c *Client) FetchModuleInfo(ctx context.Context, registryHost, namespace, n
 			return fmt.Errorf("failed to create request: %w", err)
 		}
 
+		c.requests.Add(1)
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
 			return fmt.Errorf("registry API call failed: %w", err)
@@ -150,10 +172,64 @@ func (c *Client) FetchModuleInfo(ctx context.Context, registryHost, namespace, n
 		if c.store != nil {
 			if data, err := json.Marshal(info); err == nil {
 				// Cache for 24 hours
-				c.store.Set(cacheKey, data, 24*time.Hour)
+				c.store.Set(cacheKey, json.RawMessage(data), 24*time.Hour)
 			}
 		}
 	}
 
 	return nil
 }
+
+// FetchProviderVersions fetches all versions for a provider using the provider registry protocol
+func (c *Client) FetchProviderVersions(ctx context.Context, registryHost, namespace, typeName string) (*ProviderVersions, error) {
+	cacheKey := fmt.Sprintf("provider_versions:%s:%s:%s", registryHost, namespace, typeName)
+
+	// Check cache first if store is available
+	if c.store != nil {
+		if cachedData, err := c.store.Get(cacheKey); err == nil && cachedData != nil {
+			if jsonBytes, err := json.Marshal(cachedData); err == nil {
+				var versions ProviderVersions
+				if err := json.Unmarshal(jsonBytes, &versions); err == nil {
+					c.cacheHits.Add(1)
+					return &versions, nil
+				}
+			}
+		}
+	}
+
+	apiURL := fmt.Sprintf("https://%s/v1/providers/%s/%s/versions", registryHost, namespace, typeName)
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxWithTimeout, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.requests.Add(1)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry API call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry API returned %d for provider %s/%s", resp.StatusCode, namespace, typeName)
+	}
+
+	var versions ProviderVersions
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Store in cache if available
+	if c.store != nil {
+		if data, err := json.Marshal(versions); err == nil {
+			// Cache for 24 hours
+			c.store.Set(cacheKey, json.RawMessage(data), 24*time.Hour)
+		}
+	}
+
+	return &versions, nil
+}
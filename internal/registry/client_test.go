@@ -1,7 +1,11 @@
 package registry
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -138,3 +142,159 @@ func TestCacheCheckWithoutStore(t *testing.T) {
 		t.Error("Client should have httpClient")
 	}
 }
+
+// newTestClient returns a client talking to a local TLS test server and the host to query
+func newTestClient(t *testing.T, handler http.Handler) (*Client, string) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.httpClient = server.Client()
+
+	return client, strings.TrimPrefix(server.URL, "https://")
+}
+
+func TestFetchProviderVersions(t *testing.T) {
+	client, host := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/providers/hashicorp/aws/versions" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id":"hashicorp/aws","versions":[{"version":"5.0.0","protocols":["5.0"]},{"version":"5.31.0","protocols":["5.0"]}]}`))
+	}))
+
+	versions, err := client.FetchProviderVersions(context.Background(), host, "hashicorp", "aws")
+	if err != nil {
+		t.Fatalf("FetchProviderVersions() error = %v", err)
+	}
+	if len(versions.Versions) != 2 || versions.Versions[1].Version != "5.31.0" {
+		t.Errorf("FetchProviderVersions() = %+v", versions.Versions)
+	}
+
+	if _, err := client.FetchProviderVersions(context.Background(), host, "hashicorp", "missing"); err == nil {
+		t.Error("FetchProviderVersions() should fail on 404")
+	}
+}
+
+func TestFetchModuleInfoRequiredCore(t *testing.T) {
+	client, host := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/acme/vpc/aws/5.0.0":
+			w.Write([]byte(`{"source":"https://github.com/acme/vpc","published_at":"2024-01-01T00:00:00Z","root":{"required_core":[">= 1.5.0"]}}`))
+		case "/v1/modules/acme/vpc/aws/4.2.0":
+			w.Write([]byte(`{"source":"https://github.com/acme/vpc","published_at":"2023-06-01T00:00:00Z","root":{"inputs":[]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	module := &Module{Versions: []*Version{{Version: "5.0.0"}, {Version: "4.2.0"}}}
+	if err := client.FetchModuleInfo(context.Background(), host, "acme", "vpc", "aws", module); err != nil {
+		t.Fatalf("FetchModuleInfo() error = %v", err)
+	}
+
+	if got := module.Versions[0].RequiredCore(); len(got) != 1 || got[0] != ">= 1.5.0" {
+		t.Errorf("RequiredCore() = %v, want [>= 1.5.0]", got)
+	}
+	if got := module.Versions[1].RequiredCore(); got != nil {
+		t.Errorf("RequiredCore() = %v, want nil without metadata", got)
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	client, host := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"hashicorp/aws","versions":[{"version":"5.0.0"}]}`))
+	}))
+
+	store := NewMockStore()
+	store.data["provider_versions:"+host+":hashicorp:random"] = map[string]interface{}{
+		"id":       "hashicorp/random",
+		"versions": []interface{}{map[string]interface{}{"version": "3.6.0"}},
+	}
+	client.store = store
+
+	if _, err := client.FetchProviderVersions(context.Background(), host, "hashicorp", "aws"); err != nil {
+		t.Fatalf("FetchProviderVersions() error = %v", err)
+	}
+	if _, err := client.FetchProviderVersions(context.Background(), host, "hashicorp", "random"); err != nil {
+		t.Fatalf("FetchProviderVersions() error = %v", err)
+	}
+
+	want := Stats{Requests: 1, CacheHits: 1}
+	if got := client.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+// Responses are cached as JSON documents, not as base64 strings of their bytes, so
+// that later runs decode them from the disk cache instead of querying the registry again
+func TestDiskCachedResponses(t *testing.T) {
+	var requests int
+	client, host := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/v1/modules/acme/vpc/aws/versions":
+			w.Write([]byte(`{"modules":[{"versions":[{"version":"5.0.0"},{"version":"4.2.0"}]}]}`))
+		case "/v1/modules/acme/vpc/aws/5.0.0", "/v1/modules/acme/vpc/aws/4.2.0":
+			w.Write([]byte(`{"published_at":"2024-01-01T00:00:00Z","root":{"required_core":[">= 1.5.0"]}}`))
+		case "/v1/providers/hashicorp/aws/versions":
+			w.Write([]byte(`{"id":"hashicorp/aws","versions":[{"version":"5.31.0"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	cacheDir := t.TempDir()
+	fetch := func() (*Module, *ProviderVersions) {
+		t.Helper()
+		store, err := cache.NewDiskStore(cacheDir)
+		if err != nil {
+			t.Fatalf("NewDiskStore() error = %v", err)
+		}
+		defer store.Close()
+		client.store = store
+
+		module, err := client.FetchModuleVersions(context.Background(), host, "acme", "vpc", "aws")
+		if err != nil {
+			t.Fatalf("FetchModuleVersions() error = %v", err)
+		}
+		if err := client.FetchModuleInfo(context.Background(), host, "acme", "vpc", "aws", module); err != nil {
+			t.Fatalf("FetchModuleInfo() error = %v", err)
+		}
+		providers, err := client.FetchProviderVersions(context.Background(), host, "hashicorp", "aws")
+		if err != nil {
+			t.Fatalf("FetchProviderVersions() error = %v", err)
+		}
+
+		// Lookups within the run are answered from memory
+		if _, err := client.FetchModuleVersions(context.Background(), host, "acme", "vpc", "aws"); err != nil {
+			t.Fatalf("FetchModuleVersions() error = %v", err)
+		}
+		return module, providers
+	}
+
+	fetch()
+	if requests != 4 {
+		t.Fatalf("first run sent %d requests, want 4", requests)
+	}
+
+	// A second run reads every response from the disk cache
+	module, providers := fetch()
+	if requests != 4 {
+		t.Errorf("second run sent %d requests, want none", requests-4)
+	}
+	if len(module.Versions) != 2 || module.Versions[0].Version != "5.0.0" {
+		t.Fatalf("cached module versions = %+v", module.Versions)
+	}
+	if got := module.Versions[0].RequiredCore(); len(got) != 1 || got[0] != ">= 1.5.0" {
+		t.Errorf("cached RequiredCore() = %v, want [>= 1.5.0]", got)
+	}
+	if len(providers.Versions) != 1 || providers.Versions[0].Version != "5.31.0" {
+		t.Errorf("cached provider versions = %+v", providers.Versions)
+	}
+	if got := client.Stats(); got.CacheHits != 6 {
+		t.Errorf("Stats().CacheHits = %d, want 6", got.CacheHits)
+	}
+}
@@ -14,13 +14,15 @@ var _ source.VersionFetcher = (*VersionFetcher)(nil)
 
 // VersionFetcher fetches versions from registries, with parallel support
 type VersionFetcher struct {
-	client    *Client
-	workers   int
-	results   map[string][]string
-	resultsMu sync.RWMutex
-	workerSem chan struct{}
-	errors    map[string]error
-	errorsMu  sync.RWMutex
+	client          *Client
+	workers         int
+	results         map[string][]string
+	modules         map[string]*Module
+	providerResults map[string][]string
+	resultsMu       sync.RWMutex
+	workerSem       chan struct{}
+	errors          map[string]error
+	errorsMu        sync.RWMutex
 }
 
 // NewVersionFetcher creates a new fetcher with worker pool
@@ -30,11 +32,13 @@ func NewVersionFetcher(workerCount int) *VersionFetcher {
 	}
 
 	return &VersionFetcher{
-		client:    NewClient(),
-		workers:   workerCount,
-		results:   make(map[string][]string),
-		errors:    make(map[string]error),
-		workerSem: make(chan struct{}, workerCount),
+		client:          NewClient(),
+		workers:         workerCount,
+		results:         make(map[string][]string),
+		modules:         make(map[string]*Module),
+		providerResults: make(map[string][]string),
+		errors:          make(map[string]error),
+		workerSem:       make(chan struct{}, workerCount),
 	}
 }
 
@@ -49,11 +53,13 @@ func NewVersionFetcherWithClient(client *Client, workerCount int) *VersionFetche
 	}
 
 	return &VersionFetcher{
-		client:    client,
-		workers:   workerCount,
-		results:   make(map[string][]string),
-		errors:    make(map[string]error),
-		workerSem: make(chan struct{}, workerCount),
+		client:          client,
+		workers:         workerCount,
+		results:         make(map[string][]string),
+		modules:         make(map[string]*Module),
+		providerResults: make(map[string][]string),
+		errors:          make(map[string]error),
+		workerSem:       make(chan struct{}, workerCount),
 	}
 }
 
@@ -108,12 +114,14 @@ func (f *VersionFetcher) FetchVersions(ctx context.Context, host, namespace, nam
 
 	f.resultsMu.Lock()
 	f.results[moduleKey] = sortedVersions
+	f.modules[moduleKey] = module
 	f.resultsMu.Unlock()
 
 	return sortedVersions, nil
 }
 
 // FetchMultipleVersions fetches versions for multiple modules in parallel
+// Results are keyed by the module source as written in the configuration
 func (f *VersionFetcher) FetchMultipleVersions(ctx context.Context, modules []*source.Source) map[string][]string {
 	var wg sync.WaitGroup
 
@@ -135,13 +143,87 @@ func (f *VersionFetcher) FetchMultipleVersions(ctx context.Context, modules []*s
 	defer f.resultsMu.RUnlock()
 
 	resultsCopy := make(map[string][]string)
-	for k, v := range f.results {
+	for _, mod := range modules {
+		moduleKey := fmt.Sprintf("%s/%s/%s", mod.Namespace, mod.Name, mod.Provider)
+		if versions, ok := f.results[moduleKey]; ok {
+			resultsCopy[mod.Original] = append([]string{}, versions...)
+		}
+	}
+
+	return resultsCopy
+}
+
+// FetchProviderVersions fetches the versions of a provider, sorted latest first
+func (f *VersionFetcher) FetchProviderVersions(ctx context.Context, src *source.Source) ([]string, error) {
+	key := providerKey(src)
+
+	// Acquire worker slot
+	select {
+	case f.workerSem <- struct{}{}:
+		defer func() { <-f.workerSem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	versions, err := f.client.FetchProviderVersions(ctx, src.Host, src.Namespace, src.Name)
+	if err != nil {
+		f.errorsMu.Lock()
+		f.errors[key] = err
+		f.errorsMu.Unlock()
+		return nil, err
+	}
+
+	versionStrings := make([]string, 0, len(versions.Versions))
+	for _, v := range versions.Versions {
+		versionStrings = append(versionStrings, v.Version)
+	}
+
+	sortedVersions, err := version.SortVersions(versionStrings)
+	if err != nil {
+		sortedVersions = versionStrings
+	}
+
+	f.resultsMu.Lock()
+	f.providerResults[key] = sortedVersions
+	f.resultsMu.Unlock()
+
+	return sortedVersions, nil
+}
+
+// FetchMultipleProviderVersions fetches versions for multiple providers in parallel
+// Results are keyed by provider source, omitting the default registry host
+func (f *VersionFetcher) FetchMultipleProviderVersions(ctx context.Context, providers []*source.Source) map[string][]string {
+	var wg sync.WaitGroup
+
+	for _, p := range providers {
+		wg.Add(1)
+		go func(src *source.Source) {
+			defer wg.Done()
+			_, _ = f.FetchProviderVersions(ctx, src)
+		}(p)
+	}
+
+	wg.Wait()
+
+	f.resultsMu.RLock()
+	defer f.resultsMu.RUnlock()
+
+	resultsCopy := make(map[string][]string)
+	for k, v := range f.providerResults {
 		resultsCopy[k] = append([]string{}, v...)
 	}
 
 	return resultsCopy
 }
 
+// providerKey returns the result key for a provider source
+func providerKey(src *source.Source) string {
+	if src.Type == source.SourceTypeTerraformRegistry {
+		return fmt.Sprintf("%s/%s", src.Namespace, src.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", src.Host, src.Namespace, src.Name)
+}
+
 // Errors returns all errors encountered during fetching
 func (f *VersionFetcher) Errors() map[string]error {
 	f.errorsMu.RLock()
@@ -155,6 +237,22 @@ func (f *VersionFetcher) Errors() map[string]error {
 	return errorsCopy
 }
 
+// Stats returns the registry request, cache hit and error counts of the fetcher
+func (f *VersionFetcher) Stats() Stats {
+	stats := f.client.Stats()
+	stats.Errors = len(f.Errors())
+	return stats
+}
+
+// GetModule returns the registry metadata fetched for a specific module, or nil
+func (f *VersionFetcher) GetModule(namespace, name, provider string) *Module {
+	moduleKey := fmt.Sprintf("%s/%s/%s", namespace, name, provider)
+	f.resultsMu.RLock()
+	defer f.resultsMu.RUnlock()
+
+	return f.modules[moduleKey]
+}
+
 // GetResult returns the result for a specific module
 func (f *VersionFetcher) GetResult(namespace, name, provider string) []string {
 	moduleKey := fmt.Sprintf("%s/%s/%s", namespace, name, provider)
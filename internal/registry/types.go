@@ -20,14 +20,87 @@ type RootModule struct {
 
 // Provider represents a provider requirement
 type Provider struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Version   string `json:"version"` // Version constraint, e.g., ">= 5.0"
 }
 
 // ModuleInfo represents registry metadata for a module version
 type ModuleInfo struct {
-	Source      string `json:"source"`
-	PublishedAt string `json:"published_at"`
+	Source      string          `json:"source"`
+	Tag         string          `json:"tag,omitempty"`
+	PublishedAt string          `json:"published_at"`
+	Root        *ModuleInfoRoot `json:"root,omitempty"`
+}
+
+// ModuleInfoRoot represents the root module details of a module version
+type ModuleInfoRoot struct {
+	RequiredCore []string   `json:"required_core,omitempty"` // Terraform required_version constraints
+	Inputs       []Input    `json:"inputs,omitempty"`
+	Outputs      []Output   `json:"outputs,omitempty"`
+	Resources    []Resource `json:"resources,omitempty"`
+}
+
+// Input represents a module input variable
+type Input struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// Output represents a module output value
+type Output struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Resource represents a resource declared by a module
+type Resource struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// FindVersion returns the registry entry for a version, or nil if unknown
+func (m *Module) FindVersion(version string) *Version {
+	if m == nil {
+		return nil
+	}
+	for _, v := range m.Versions {
+		if v.Version == version {
+			return v
+		}
+	}
+	return nil
+}
+
+// Details returns the root module details of a version, or nil if unknown
+func (v *Version) Details() *ModuleInfoRoot {
+	if v == nil || v.RegistryModuleInfo == nil {
+		return nil
+	}
+	return v.RegistryModuleInfo.Root
+}
+
+// RequiredCore returns the Terraform core constraints of a version, or nil if unknown
+func (v *Version) RequiredCore() []string {
+	if v.RegistryModuleInfo == nil || v.RegistryModuleInfo.Root == nil {
+		return nil
+	}
+	return v.RegistryModuleInfo.Root.RequiredCore
+}
+
+// ProviderVersions represents the versions of a provider in the registry
+type ProviderVersions struct {
+	ID       string             `json:"id"`
+	Versions []*ProviderVersion `json:"versions"`
+}
+
+// ProviderVersion represents a specific version of a provider in the registry
+type ProviderVersion struct {
+	Version   string   `json:"version"`
+	Protocols []string `json:"protocols"`
 }
 
 // registryResponse is the response structure from the registry API
@@ -2,20 +2,31 @@ package report
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/vdesjardins/terraform-module-versions/internal/finder"
 	"github.com/vdesjardins/terraform-module-versions/internal/source"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
 )
 
 // Builder constructs UpdateSummary from findings and registry results
 type Builder struct {
-	modules map[string]*ModuleReport
+	modules           map[string]*ModuleReport
+	unpinned          []*UnpinnedModule
+	types             map[string]source.SourceTypeEnum // Source -> type, for unpinned calls
+	providers         map[string]*ProviderReport
+	compatible        map[string]string // Source -> newest version its modules can be updated to
+	targeted          bool              // Whether TargetModules chose the module targets
+	providersTargeted bool              // Whether TargetProviders chose the provider targets
 }
 
 // NewBuilder creates a new summary builder
 func NewBuilder() *Builder {
 	return &Builder{
-		modules: make(map[string]*ModuleReport),
+		modules:    make(map[string]*ModuleReport),
+		types:      make(map[string]source.SourceTypeEnum),
+		providers:  make(map[string]*ProviderReport),
+		compatible: make(map[string]string),
 	}
 }
 
@@ -38,11 +49,60 @@ func (b *Builder) AddModuleUsages(usages []finder.ModuleWithPath) {
 		if len(mod.Locations) < 100 {
 			mod.Locations = append(mod.Locations, usage.FilePath)
 		}
+
+		usageReport := UsageReport{
+			BlockName:    usage.Usage.BlockName,
+			File:         usage.Usage.BlockFile,
+			Line:         usage.Usage.BlockLine,
+			Version:      usage.Usage.Version,
+			VersionRange: usage.Usage.VersionRange,
+		}
+
+		// Track where versions read from locals/variables are defined
+		if ref := usage.Usage.VersionRef; ref != nil {
+			definition := fmt.Sprintf("%s (%s:%d)", ref, ref.FilePath, ref.Line)
+			if !containsString(mod.Definitions, definition) {
+				mod.Definitions = append(mod.Definitions, definition)
+			}
+			usageReport.Definition = definition
+		}
+
+		mod.Usages = append(mod.Usages, usageReport)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AddUnpinnedModules records module calls that have no version attribute
+// Only calls whose source resolves to a registry are kept in the summary
+func (b *Builder) AddUnpinnedModules(usages []finder.ModuleWithPath) {
+	for _, usage := range usages {
+		b.unpinned = append(b.unpinned, &UnpinnedModule{
+			Source:    usage.Usage.Source,
+			Type:      source.SourceTypeUnknown,
+			BlockName: usage.Usage.BlockName,
+			File:      usage.Usage.BlockFile,
+			Line:      usage.Usage.BlockLine,
+		})
 	}
 }
 
 // AddSourceInfo adds source type information to modules
 func (b *Builder) AddSourceInfo(sources map[string]*source.Source) {
+	for _, unpinned := range b.unpinned {
+		if src, exists := sources[unpinned.Source]; exists && src.Supported {
+			unpinned.Type = src.Type
+			b.types[unpinned.Source] = src.Type
+		}
+	}
+
 	for sourceStr, src := range sources {
 		if mod, exists := b.modules[sourceStr]; exists {
 			mod.Type = src.Type
@@ -54,27 +114,180 @@ func (b *Builder) AddSourceInfo(sources map[string]*source.Source) {
 // AddLatestVersions adds latest version info from registry
 // Accepts map from fetcher which returns latest first
 func (b *Builder) AddLatestVersions(versionsMap map[string][]string) {
+	for _, unpinned := range b.unpinned {
+		if versions := versionsMap[unpinned.Source]; len(versions) > 0 {
+			unpinned.LatestVersion = versions[0]
+		}
+	}
+
 	for sourceStr, versions := range versionsMap {
 		if mod, exists := b.modules[sourceStr]; exists {
 			if len(versions) > 0 {
 				// Versions are already sorted latest-first
 				mod.LatestVersion = versions[0]
+				b.compatible[sourceStr] = versions[0]
+			}
+		}
+	}
+}
 
-				// Calculate how many would be updated
-				for ver, count := range mod.CurrentVersions {
-					if ver != mod.LatestVersion {
-						mod.UpdateCount += count
-					}
-				}
+// HoldModule sets the version a module is held at and why newer versions were skipped
+// An empty target means no compatible version is available
+func (b *Builder) HoldModule(sourceStr, target, reason string) {
+	if _, exists := b.modules[sourceStr]; !exists {
+		return
+	}
+
+	b.modules[sourceStr].HoldReason = reason
+	b.compatible[sourceStr] = target
+}
+
+// TargetModules sets the version each current version of a module is updated to, as chosen by target
+// from the current version and the latest compatible version of the module
+// Current versions for which target returns false are left alone
+func (b *Builder) TargetModules(target func(mod *ModuleReport, current, compatible string) (string, bool)) {
+	b.targeted = true
+
+	for sourceStr, mod := range b.modules {
+		mod.Targets = nil
+		mod.UpdateCount = 0
+		mod.UpcomingVersion = ""
+		if mod.LatestVersion == "" {
+			continue
+		}
+
+		compatible := b.compatible[sourceStr]
+		for ver, count := range mod.CurrentVersions {
+			selected, ok := target(mod, ver, compatible)
+			if !ok || !pendingTarget(mod, ver, selected) {
+				continue
+			}
+
+			if mod.Targets == nil {
+				mod.Targets = make(map[string]string)
+			}
+			mod.Targets[ver] = selected
+			mod.UpdateCount += count
+
+			if mod.UpcomingVersion == "" {
+				mod.UpcomingVersion = selected
+			} else if newer, err := version.IsNewer(mod.UpcomingVersion, selected); err == nil && newer {
+				mod.UpcomingVersion = selected
+			}
+		}
+
+		// Held modules with nothing to update still report the version they are held at
+		if mod.UpcomingVersion == "" && mod.HoldReason != "" {
+			mod.UpcomingVersion = compatible
+		}
+	}
+}
+
+// WarnModule records a compatibility warning for a module's upcoming version
+func (b *Builder) WarnModule(sourceStr, warning string) {
+	if mod, exists := b.modules[sourceStr]; exists && !containsString(mod.Warnings, warning) {
+		mod.Warnings = append(mod.Warnings, warning)
+	}
+}
+
+// pendingUpdate reports whether usages at version ver are updated
+func pendingUpdate(mod *ModuleReport, ver string) bool {
+	return mod.Target(ver) != ""
+}
+
+// pendingTarget reports whether usages at version ver move to target
+// Held modules never move usages that are already at or past the target
+func pendingTarget(mod *ModuleReport, ver, target string) bool {
+	if target == "" || ver == target {
+		return false
+	}
+	if mod.HoldReason == "" {
+		return true
+	}
+
+	newer, err := version.IsNewer(ver, target)
+	return err == nil && newer
+}
 
-				mod.UpcomingVersion = mod.LatestVersion
+// AddProviderUsages processes required_providers entries and groups by provider source
+func (b *Builder) AddProviderUsages(usages []finder.ProviderUsage) {
+	for _, usage := range usages {
+		if _, exists := b.providers[usage.Source]; !exists {
+			b.providers[usage.Source] = &ProviderReport{
+				Source:             usage.Source,
+				CurrentConstraints: make(map[string]int),
+				Locations:          []string{},
 			}
 		}
+
+		provider := b.providers[usage.Source]
+		provider.CurrentConstraints[usage.Constraint]++
+		provider.TotalUsages++
+
+		if len(provider.Locations) < 100 {
+			provider.Locations = append(provider.Locations, usage.File)
+		}
+	}
+}
+
+// AddProviderVersions adds latest version info for providers
+// Accepts map keyed by provider source with versions sorted latest first
+func (b *Builder) AddProviderVersions(versionsMap map[string][]string) {
+	for sourceStr, versions := range versionsMap {
+		provider, exists := b.providers[sourceStr]
+		if !exists || len(versions) == 0 {
+			continue
+		}
+
+		provider.LatestVersion = versions[0]
+	}
+}
+
+// TargetProviders sets the constraint each current constraint of a provider is rewritten to,
+// as chosen by target
+// Constraints for which target returns false or the same constraint are left alone
+func (b *Builder) TargetProviders(target func(provider *ProviderReport, constraint string) (string, bool)) {
+	b.providersTargeted = true
+
+	for _, provider := range b.providers {
+		provider.Targets = nil
+		provider.UpdateCount = 0
+		if provider.LatestVersion == "" {
+			continue
+		}
+
+		for constraint, count := range provider.CurrentConstraints {
+			rewritten, ok := target(provider, constraint)
+			if !ok || rewritten == "" || rewritten == constraint {
+				continue
+			}
+
+			if provider.Targets == nil {
+				provider.Targets = make(map[string]string)
+			}
+			provider.Targets[constraint] = rewritten
+			provider.UpdateCount += count
+		}
 	}
 }
 
 // Build constructs the final UpdateSummary
 func (b *Builder) Build() *UpdateSummary {
+	if !b.targeted {
+		// Update every module to its latest compatible version
+		b.TargetModules(func(_ *ModuleReport, _, compatible string) (string, bool) {
+			return compatible, true
+		})
+	}
+
+	if !b.providersTargeted {
+		// Bump every constraint to the latest version it allows
+		b.TargetProviders(func(provider *ProviderReport, constraint string) (string, bool) {
+			bumped, err := version.BumpConstraint(constraint, provider.LatestVersion)
+			return bumped, err == nil
+		})
+	}
+
 	summary := &UpdateSummary{
 		ByVersionChange: make(map[string]int),
 	}
@@ -94,8 +307,8 @@ func (b *Builder) Build() *UpdateSummary {
 
 			// Build version change map
 			for ver, count := range mod.CurrentVersions {
-				if ver != mod.LatestVersion {
-					changeKey := fmt.Sprintf("%s → %s", ver, mod.LatestVersion)
+				if pendingUpdate(mod, ver) {
+					changeKey := fmt.Sprintf("%s → %s", ver, mod.Target(ver))
 					summary.ByVersionChange[changeKey] += count
 				}
 			}
@@ -104,10 +317,29 @@ func (b *Builder) Build() *UpdateSummary {
 				Source: mod.Source,
 				Type:   mod.Type,
 				Count:  mod.TotalUsages,
+				Usages: mod.Usages,
 			})
 		}
 	}
 
+	// Only registry sources can be pinned
+	for _, unpinned := range b.unpinned {
+		if _, registry := b.types[unpinned.Source]; registry {
+			summary.UnpinnedModules = append(summary.UnpinnedModules, *unpinned)
+		}
+	}
+
+	for _, provider := range b.providers {
+		summary.Providers = append(summary.Providers, *provider)
+	}
+	sort.Slice(summary.Providers, func(i, j int) bool {
+		return summary.Providers[i].Source < summary.Providers[j].Source
+	})
+
+	sort.Slice(supported, func(i, j int) bool {
+		return supported[i].Source < supported[j].Source
+	})
+
 	summary.Modules = supported
 	summary.UnsupportedModules = unsupported
 	summary.TotalUsages = totalUsages
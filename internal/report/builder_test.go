@@ -0,0 +1,123 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+const vpcSource = "terraform-aws-modules/vpc/aws"
+
+// vpcBuilder returns a builder with vpc module calls at the given versions
+func vpcBuilder(versions ...string) *Builder {
+	var usages []finder.ModuleWithPath
+	for _, ver := range versions {
+		usages = append(usages, finder.ModuleWithPath{
+			FilePath: "main.tf",
+			Usage:    finder.ModuleUsage{Source: vpcSource, Version: ver, BlockName: "vpc", BlockFile: "main.tf"},
+		})
+	}
+
+	builder := NewBuilder()
+	builder.AddModuleUsages(usages)
+	builder.AddSourceInfo(map[string]*source.Source{vpcSource: {Supported: true}})
+	builder.AddLatestVersions(map[string][]string{vpcSource: {"5.1.0", "5.0.0", "4.2.0", "4.0.0"}})
+	return builder
+}
+
+func TestBuildTargetsLatest(t *testing.T) {
+	summary := vpcBuilder("4.0.0", "4.0.0", "5.1.0").Build()
+
+	mod := summary.Modules[0]
+	if got := mod.Target("4.0.0"); got != "5.1.0" {
+		t.Errorf("Target(4.0.0) = %q, want 5.1.0", got)
+	}
+	if got := mod.Target("5.1.0"); got != "" {
+		t.Errorf("Target(5.1.0) = %q, want none", got)
+	}
+	if mod.UpdateCount != 2 || mod.UpcomingVersion != "5.1.0" {
+		t.Errorf("UpdateCount, UpcomingVersion = %d, %q, want 2, 5.1.0", mod.UpdateCount, mod.UpcomingVersion)
+	}
+}
+
+func TestTargetModulesPerCurrentVersion(t *testing.T) {
+	builder := vpcBuilder("4.0.0", "5.0.0", "5.0.0", "4.2.0")
+
+	// Stay within the current major version
+	builder.TargetModules(func(_ *ModuleReport, current, _ string) (string, bool) {
+		if strings.HasPrefix(current, "4.") {
+			return "4.2.0", true
+		}
+		return "5.1.0", true
+	})
+	summary := builder.Build()
+
+	mod := summary.Modules[0]
+	want := map[string]string{"4.0.0": "4.2.0", "5.0.0": "5.1.0"}
+	if len(mod.Targets) != len(want) {
+		t.Fatalf("Targets = %v, want %v", mod.Targets, want)
+	}
+	for ver, target := range want {
+		if mod.Target(ver) != target {
+			t.Errorf("Target(%s) = %q, want %q", ver, mod.Target(ver), target)
+		}
+	}
+	if mod.UpdateCount != 3 || mod.UpcomingVersion != "5.1.0" {
+		t.Errorf("UpdateCount, UpcomingVersion = %d, %q, want 3, 5.1.0", mod.UpdateCount, mod.UpcomingVersion)
+	}
+
+	wantChanges := map[string]int{"4.0.0 → 4.2.0": 1, "5.0.0 → 5.1.0": 2}
+	if len(summary.ByVersionChange) != len(wantChanges) {
+		t.Fatalf("ByVersionChange = %v, want %v", summary.ByVersionChange, wantChanges)
+	}
+	for change, count := range wantChanges {
+		if summary.ByVersionChange[change] != count {
+			t.Errorf("ByVersionChange[%s] = %d, want %d", change, summary.ByVersionChange[change], count)
+		}
+	}
+	if summary.TotalUpdated != 3 {
+		t.Errorf("TotalUpdated = %d, want 3", summary.TotalUpdated)
+	}
+}
+
+func TestTargetModulesHeld(t *testing.T) {
+	builder := vpcBuilder("4.0.0", "5.0.0")
+	builder.HoldModule(vpcSource, "4.2.0", "5.0.0 requires aws >= 5.0")
+
+	// Usages past the held version are never moved back
+	builder.TargetModules(func(_ *ModuleReport, _, compatible string) (string, bool) {
+		return compatible, true
+	})
+	mod := builder.Build().Modules[0]
+
+	if mod.Target("4.0.0") != "4.2.0" || mod.Target("5.0.0") != "" {
+		t.Errorf("Targets = %v, want only 4.0.0 → 4.2.0", mod.Targets)
+	}
+	if mod.UpdateCount != 1 || mod.UpcomingVersion != "4.2.0" {
+		t.Errorf("UpdateCount, UpcomingVersion = %d, %q, want 1, 4.2.0", mod.UpdateCount, mod.UpcomingVersion)
+	}
+}
+
+func TestBuildProviderTargets(t *testing.T) {
+	builder := NewBuilder()
+	builder.AddProviderUsages([]finder.ProviderUsage{
+		{Source: "hashicorp/aws", Constraint: "~> 5.0", File: "main.tf"},
+		{Source: "hashicorp/aws", Constraint: ">= 4.2, < 6.0", File: "legacy.tf"},
+		{Source: "hashicorp/aws", Constraint: "~> 6.2", File: "new.tf"},
+	})
+	builder.AddProviderVersions(map[string][]string{"hashicorp/aws": {"6.2.1", "5.9.0", "4.2.0"}})
+
+	// Rewrites that would exclude the latest version are not pending
+	provider := builder.Build().Providers[0]
+	if got := provider.Target("~> 5.0"); got != "~> 6.2" {
+		t.Errorf("Target(~> 5.0) = %q, want ~> 6.2", got)
+	}
+	if got := provider.Target(">= 4.2, < 6.0"); got != "" {
+		t.Errorf("Target(>= 4.2, < 6.0) = %q, want none", got)
+	}
+	if provider.UpdateCount != 1 {
+		t.Errorf("UpdateCount = %d, want 1", provider.UpdateCount)
+	}
+}
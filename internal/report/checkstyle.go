@@ -0,0 +1,99 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+)
+
+type (
+	checkstyleReport struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+
+	checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+
+	checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+)
+
+// checkstyleSeverities maps SARIF levels to Checkstyle severities
+var checkstyleSeverities = map[string]string{
+	"error":   "error",
+	"warning": "warning",
+	"note":    "info",
+}
+
+// WriteCheckstyle writes one Checkstyle error per outdated or unpinned module block
+func WriteCheckstyle(writer io.Writer, summary *UpdateSummary) error {
+	files := make(map[string][]checkstyleError)
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		for _, usage := range mod.Usages {
+			if !pendingUpdate(mod, usage.Version) {
+				continue
+			}
+
+			ruleID := updateRule(usage.Version, mod.Target(usage.Version))
+			line, column := usage.Line, 0
+			if usage.VersionRange != nil {
+				line, column = usage.VersionRange.StartLine, usage.VersionRange.StartColumn
+			}
+
+			files[usage.File] = append(files[usage.File], checkstyleError{
+				Line:     line,
+				Column:   column,
+				Severity: checkstyleSeverities[ruleLevel(ruleID)],
+				Message:  outdatedMessage(mod, usage),
+				Source:   toolName + "." + ruleID,
+			})
+		}
+	}
+
+	for _, mod := range summary.UnpinnedModules {
+		files[mod.File] = append(files[mod.File], checkstyleError{
+			Line:     mod.Line,
+			Severity: checkstyleSeverities[ruleLevel(RuleUnpinned)],
+			Message:  unpinnedMessage(mod),
+			Source:   toolName + "." + RuleUnpinned,
+		})
+	}
+
+	report := checkstyleReport{Version: "4.3"}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		errors := files[name]
+		sort.SliceStable(errors, func(i, j int) bool { return errors[i].Line < errors[j].Line })
+		report.Files = append(report.Files, checkstyleFile{Name: name, Errors: errors})
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteCheckstyle(t *testing.T) {
+	summary := testSummary()
+	summary.Modules[0].Usages[0].File = `/repo/R&D/"vpc" <main>.tf`
+
+	var out bytes.Buffer
+	if err := WriteCheckstyle(&out, summary); err != nil {
+		t.Fatalf("WriteCheckstyle() error = %v", err)
+	}
+	checkGolden(t, "checkstyle.golden", out.Bytes())
+
+	if !strings.HasPrefix(out.String(), xml.Header) {
+		t.Errorf("WriteCheckstyle() output does not start with the XML header")
+	}
+	var report checkstyleReport
+	if err := xml.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("WriteCheckstyle() wrote invalid XML: %v", err)
+	}
+	if report.Version != "4.3" {
+		t.Errorf("version = %q, want 4.3", report.Version)
+	}
+
+	// One error per outdated or unpinned block, files sorted; unsupported sources are left out
+	want := []struct {
+		file, source string
+		line, column int
+	}{
+		{`/repo/R&D/"vpc" <main>.tf`, toolName + "." + RuleMajorUpdate, 3, 13},
+		{"/repo/eks.tf", toolName + "." + RuleMinorUpdate, 3, 13},
+		{"/repo/network/net.tf", toolName + "." + RuleMinorUpdate, 5, 0},
+		{"/repo/storage.tf", toolName + "." + RuleUnpinned, 7, 0},
+	}
+	if len(report.Files) != len(want) {
+		t.Fatalf("files = %d, want %d", len(report.Files), len(want))
+	}
+	for i, w := range want {
+		file := report.Files[i]
+		if file.Name != w.file || len(file.Errors) != 1 {
+			t.Errorf("file %d = %q with %d errors, want %q with 1", i, file.Name, len(file.Errors), w.file)
+			continue
+		}
+		got := file.Errors[0]
+		if got.Source != w.source || got.Line != w.line || got.Column != w.column || got.Severity != "warning" {
+			t.Errorf("%s error = %+v, want %s at %d:%d", w.file, got, w.source, w.line, w.column)
+		}
+	}
+
+	if !strings.Contains(out.String(), `name="/repo/R&amp;D/&#34;vpc&#34; &lt;main&gt;.tf"`) {
+		t.Errorf("WriteCheckstyle() did not escape the file name:\n%s", out.String())
+	}
+}
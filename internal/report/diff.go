@@ -5,47 +5,237 @@ import (
 	"strings"
 )
 
-// FormatUnifiedDiff creates a simple unified diff for display.
+// DefaultDiffContext is the number of unchanged lines shown around each change
+const DefaultDiffContext = 3
+
+// FormatUnifiedDiff creates a unified diff for display, with the default context
 func FormatUnifiedDiff(filename, before, after string) (string, error) {
+	return UnifiedDiff(filename, filename, before, after, DefaultDiffContext), nil
+}
+
+// UnifiedDiff returns the unified diff turning before into after, in the format read by
+// patch and git apply, e.g. with names "a/main.tf" and "b/main.tf"
+// Changes separated by at most twice the context lines share a hunk
+// Returns an empty string when the contents are equal
+func UnifiedDiff(oldName, newName, before, after string, context int) string {
 	if before == after {
-		return "", nil
+		return ""
 	}
+	context = max(context, 0)
 
-	beforeLines := strings.Split(before, "\n")
-	afterLines := strings.Split(after, "\n")
+	a, b := splitLines(before), splitLines(after)
+	ops := diffLines(a, b)
 
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("--- %s\n", filename))
-	builder.WriteString(fmt.Sprintf("+++ %s\n", filename))
-	builder.WriteString(fmt.Sprintf("@@ -1,%d +1,%d @@\n", len(beforeLines), len(afterLines)))
+	fmt.Fprintf(&builder, "--- %s\n", oldName)
+	fmt.Fprintf(&builder, "+++ %s\n", newName)
+	for _, hunk := range hunks(ops, context) {
+		writeHunk(&builder, hunk, a, b)
+	}
+	return builder.String()
+}
+
+// opKind is the kind of a line in an edit script
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// diffOp is one line of an edit script, at line a of the old content and b of the new one
+// A deleted line has no new position and an inserted line no old one: a and b are then
+// where the line would be in the other content
+type diffOp struct {
+	kind opKind
+	a, b int
+}
+
+// splitLines splits content into lines that keep their newline, the last one may have none
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script turning a into b
+// Common leading and trailing lines are matched first, the rest with Myers' algorithm
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: opEqual, a: i, b: i})
+	}
+	for _, op := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		op.a += prefix
+		op.b += prefix
+		ops = append(ops, op)
+	}
+	for i := suffix; i > 0; i-- {
+		ops = append(ops, diffOp{kind: opEqual, a: len(a) - i, b: len(b) - i})
+	}
+	return ops
+}
+
+// myers returns the shortest edit script turning a into b
+// See "An O(ND) Difference Algorithm and Its Variations", Eugene W. Myers, 1986
+func myers(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1) // Furthest x reached on each diagonal k = x - y
 
-	maxLines := len(beforeLines)
-	if len(afterLines) > maxLines {
-		maxLines = len(afterLines)
+	// trace[d] holds the diagonals -d-1..d+1 of v before step d, to walk the path back
+	var trace [][]int
+	found := false
+	for d := 0; d <= n+m && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1] // Down: insertion
+			} else {
+				x = v[offset+k-1] + 1 // Right: deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
 	}
 
-	for i := 0; i < maxLines; i++ {
-		var beforeLine string
-		var afterLine string
+	// Walk back from the end, collecting operations in reverse
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d+1] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		if d == 0 {
+			prevX, prevY = 0, 0
+		}
 
-		if i < len(beforeLines) {
-			beforeLine = beforeLines[i]
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{kind: opEqual, a: x, b: y})
+		}
+		if d == 0 {
+			break
 		}
-		if i < len(afterLines) {
-			afterLine = afterLines[i]
+		if x == prevX {
+			ops = append(ops, diffOp{kind: opInsert, a: x, b: prevY})
+		} else {
+			ops = append(ops, diffOp{kind: opDelete, a: prevX, b: y})
 		}
+		x, y = prevX, prevY
+	}
 
-		if beforeLine == afterLine {
-			builder.WriteString(" " + beforeLine + "\n")
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// hunks groups the changes of an edit script with up to context unchanged lines around them
+func hunks(ops []diffOp, context int) [][]diffOp {
+	var groups [][]diffOp
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			i++
 			continue
 		}
-		if beforeLine != "" {
-			builder.WriteString("-" + beforeLine + "\n")
+
+		start := max(i-context, 0)
+		end := i // End of the last change in the hunk
+		for {
+			for end < len(ops) && ops[end].kind != opEqual {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
 		}
-		if afterLine != "" {
-			builder.WriteString("+" + afterLine + "\n")
+
+		stop := min(end+context, len(ops))
+		groups = append(groups, ops[start:stop])
+		i = stop
+	}
+	return groups
+}
+
+// writeHunk writes a hunk header and its lines
+func writeHunk(builder *strings.Builder, hunk []diffOp, a, b []string) {
+	oldCount, newCount := 0, 0
+	for _, op := range hunk {
+		if op.kind != opInsert {
+			oldCount++
+		}
+		if op.kind != opDelete {
+			newCount++
 		}
 	}
 
-	return builder.String(), nil
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(hunk[0].a, oldCount), hunkRange(hunk[0].b, newCount))
+	for _, op := range hunk {
+		switch op.kind {
+		case opEqual:
+			writeDiffLine(builder, " ", a[op.a])
+		case opDelete:
+			writeDiffLine(builder, "-", a[op.a])
+		case opInsert:
+			writeDiffLine(builder, "+", b[op.b])
+		}
+	}
+}
+
+// hunkRange formats the 1-based range of a hunk from its 0-based first line
+// An empty range starts at the line before it, and a single line has no count
+func hunkRange(first, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", first)
+	case 1:
+		return fmt.Sprintf("%d", first+1)
+	default:
+		return fmt.Sprintf("%d,%d", first+1, count)
+	}
+}
+
+func writeDiffLine(builder *strings.Builder, prefix, line string) {
+	builder.WriteString(prefix + line)
+	if !strings.HasSuffix(line, "\n") {
+		builder.WriteString("\n\\ No newline at end of file\n")
+	}
 }
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered returns lines "line 1" to "line n"
+func numbered(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func join(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestUnifiedDiffContext(t *testing.T) {
+	before := numbered(20)
+	after := append([]string(nil), before...)
+	after[4] = "changed 5"
+	after[16] = "changed 17"
+
+	got := UnifiedDiff("a/main.tf", "b/main.tf", join(before), join(after), 2)
+	want := `--- a/main.tf
++++ b/main.tf
+@@ -3,5 +3,5 @@
+ line 3
+ line 4
+-line 5
++changed 5
+ line 6
+ line 7
+@@ -15,5 +15,5 @@
+ line 15
+ line 16
+-line 17
++changed 17
+ line 18
+ line 19
+`
+	if got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Changes closer than twice the context share a hunk
+	got = UnifiedDiff("a/main.tf", "b/main.tf", join(before), join(after), 6)
+	if strings.Count(got, "@@ -") != 1 || !strings.Contains(got, "@@ -1,20 +1,20 @@\n") {
+		t.Errorf("UnifiedDiff() with context 6 =\n%s\nwant a single hunk", got)
+	}
+}
+
+func TestUnifiedDiffInsertDelete(t *testing.T) {
+	before := "a\nb\nc\n"
+	after := "a\nnew\nb\n"
+
+	got := UnifiedDiff("a/x.tf", "b/x.tf", before, after, 0)
+	want := `--- a/x.tf
++++ b/x.tf
+@@ -1,0 +2 @@
++new
+@@ -3 +3,0 @@
+-c
+`
+	if got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiffEmptyLines(t *testing.T) {
+	before := "a\n\nb\n"
+	after := "a\nb\n"
+
+	got := UnifiedDiff("x", "x", before, after, 3)
+	want := "--- x\n+++ x\n@@ -1,3 +1,2 @@\n a\n-\n b\n"
+	if got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedDiffNoNewlineAtEnd(t *testing.T) {
+	got := UnifiedDiff("x", "x", "a\nb", "a\nc", 3)
+	want := "--- x\n+++ x\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"
+	if got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+
+	if got := UnifiedDiff("x", "x", "same\n", "same\n", 3); got != "" {
+		t.Errorf("UnifiedDiff() of equal contents = %q, want empty", got)
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	a := strings.Split("a b c a b b a", " ")
+	b := strings.Split("c b a b a c", " ")
+
+	ops := diffLines(a, b)
+	changes := 0
+	var rebuilt []string
+	for _, op := range ops {
+		switch op.kind {
+		case opEqual:
+			rebuilt = append(rebuilt, a[op.a])
+		case opInsert:
+			rebuilt = append(rebuilt, b[op.b])
+			changes++
+		case opDelete:
+			changes++
+		}
+	}
+	if strings.Join(rebuilt, " ") != strings.Join(b, " ") {
+		t.Errorf("edit script rebuilds %v, want %v", rebuilt, b)
+	}
+	// The example of Myers' paper has an edit distance of 5
+	if changes != 5 {
+		t.Errorf("edit script has %d changes, want 5", changes)
+	}
+}
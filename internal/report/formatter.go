@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Built-in report formats
+const (
+	FormatText        = "text"
+	FormatJSON        = "json"
+	FormatMarkdown    = "markdown"
+	FormatSARIF       = "sarif"
+	FormatJUnit       = "junit"
+	FormatCheckstyle  = "checkstyle"
+	FormatOpenMetrics = "openmetrics"
+)
+
+// Formatter renders an update summary in one output format
+type Formatter interface {
+	Format(writer io.Writer, summary *UpdateSummary) error
+}
+
+// FormatterFunc adapts a function to the Formatter interface
+type FormatterFunc func(writer io.Writer, summary *UpdateSummary) error
+
+// Format calls f(writer, summary)
+func (f FormatterFunc) Format(writer io.Writer, summary *UpdateSummary) error {
+	return f(writer, summary)
+}
+
+var formatters = map[string]Formatter{
+	FormatText:        FormatterFunc(writeText),
+	FormatJSON:        FormatterFunc(WriteJSON),
+	FormatMarkdown:    FormatterFunc(WriteMarkdown),
+	FormatSARIF:       FormatterFunc(WriteSARIF),
+	FormatJUnit:       FormatterFunc(WriteJUnit),
+	FormatCheckstyle:  FormatterFunc(WriteCheckstyle),
+	FormatOpenMetrics: FormatterFunc(WriteOpenMetrics),
+}
+
+// RegisterFormatter makes a formatter available under a name, replacing any existing one
+func RegisterFormatter(name string, formatter Formatter) {
+	formatters[name] = formatter
+}
+
+// LookupFormatter returns the formatter registered under a name
+func LookupFormatter(name string) (Formatter, error) {
+	formatter, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid output format %q: must be one of %s", name, strings.Join(FormatterNames(), ", "))
+	}
+	return formatter, nil
+}
+
+// FormatterNames returns the registered format names, sorted
+func FormatterNames() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeText renders the summary with the console printer
+func writeText(writer io.Writer, summary *UpdateSummary) error {
+	NewPrinter(summary).Print(writer)
+	return nil
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLookupFormatter(t *testing.T) {
+	for _, name := range []string{FormatText, FormatJSON, FormatMarkdown, FormatSARIF, FormatJUnit, FormatCheckstyle, FormatOpenMetrics} {
+		formatter, err := LookupFormatter(name)
+		if err != nil || formatter == nil {
+			t.Errorf("LookupFormatter(%q) = %v, %v, want a formatter", name, formatter, err)
+		}
+	}
+
+	// Unknown formats are reported with the registered names
+	_, err := LookupFormatter("yaml")
+	if err == nil {
+		t.Fatal("LookupFormatter(yaml) succeeded, want an error")
+	}
+	for _, want := range []string{`"yaml"`, strings.Join(FormatterNames(), ", ")} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LookupFormatter(yaml) error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	t.Cleanup(func() { delete(formatters, "names") })
+
+	RegisterFormatter("names", FormatterFunc(func(writer io.Writer, summary *UpdateSummary) error {
+		for _, mod := range summary.Modules {
+			if _, err := io.WriteString(writer, mod.Source+"\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	formatter, err := LookupFormatter("names")
+	if err != nil {
+		t.Fatalf("LookupFormatter(names) error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := formatter.Format(&out, testSummary()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := vpcSource + "\nterraform-aws-modules/eks/aws\n"; out.String() != want {
+		t.Errorf("Format() = %q, want %q", out.String(), want)
+	}
+
+	names := FormatterNames()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("FormatterNames() = %v, want sorted", names)
+	}
+	found := false
+	for _, name := range names {
+		found = found || name == "names"
+	}
+	if !found {
+		t.Errorf("FormatterNames() = %v, want the registered formatter", names)
+	}
+}
@@ -0,0 +1,13 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSON writes the summary as indented JSON
+func WriteJSON(writer io.Writer, summary *UpdateSummary) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summary)
+}
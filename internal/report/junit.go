@@ -0,0 +1,146 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type (
+	junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Name     string           `xml:"name,attr"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Skipped  int              `xml:"skipped,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Skipped  int             `xml:"skipped,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		File      string        `xml:"file,attr,omitempty"`
+		Line      int           `xml:"line,attr,omitempty"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+		Skipped   *junitSkipped `xml:"skipped,omitempty"`
+		SystemOut string        `xml:"system-out,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+
+	junitSkipped struct {
+		Message string `xml:"message,attr"`
+	}
+)
+
+// WriteJUnit writes the summary as a JUnit XML report
+// Each module usage is a test case that fails when an update is pending,
+// unpinned registry modules fail and unsupported sources are skipped
+func WriteJUnit(writer io.Writer, summary *UpdateSummary) error {
+	report := junitTestSuites{Name: toolName}
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		suite := junitTestSuite{Name: mod.Source}
+
+		for _, usage := range mod.Usages {
+			testCase := usageTestCase(mod.Source, usage)
+			if pendingUpdate(mod, usage.Version) {
+				testCase.Failure = &junitFailure{
+					Message: outdatedMessage(mod, usage),
+					Type:    updateRule(usage.Version, mod.Target(usage.Version)),
+					Text:    fmt.Sprintf("%s:%d: %s → %s", usage.File, usage.Line, usage.Version, mod.Target(usage.Version)),
+				}
+			}
+			if mod.HoldReason != "" {
+				testCase.SystemOut = fmt.Sprintf("held at %s: %s", heldVersion(mod), mod.HoldReason)
+			}
+			suite.add(testCase)
+		}
+
+		report.add(suite)
+	}
+
+	if len(summary.UnpinnedModules) > 0 {
+		suite := junitTestSuite{Name: "unpinned"}
+		for _, mod := range summary.UnpinnedModules {
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("module.%s", mod.BlockName),
+				ClassName: mod.Source,
+				File:      mod.File,
+				Line:      mod.Line,
+				Failure: &junitFailure{
+					Message: unpinnedMessage(mod),
+					Type:    RuleUnpinned,
+				},
+			}
+			suite.add(testCase)
+		}
+		report.add(suite)
+	}
+
+	for _, src := range summary.UnsupportedModules {
+		suite := junitTestSuite{Name: src.Source}
+		for _, usage := range src.Usages {
+			testCase := usageTestCase(src.Source, usage)
+			testCase.Skipped = &junitSkipped{Message: fmt.Sprintf("%s sources cannot be checked for updates", src.Type)}
+			suite.add(testCase)
+		}
+		report.add(suite)
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func usageTestCase(source string, usage UsageReport) junitTestCase {
+	return junitTestCase{
+		Name:      fmt.Sprintf("module.%s (%s:%d)", usage.BlockName, usage.File, usage.Line),
+		ClassName: source,
+		File:      usage.File,
+		Line:      usage.Line,
+	}
+}
+
+func (s *junitTestSuite) add(testCase junitTestCase) {
+	s.Cases = append(s.Cases, testCase)
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	if testCase.Skipped != nil {
+		s.Skipped++
+	}
+}
+
+func (r *junitTestSuites) add(suite junitTestSuite) {
+	if suite.Tests == 0 {
+		return
+	}
+	r.Suites = append(r.Suites, suite)
+	r.Tests += suite.Tests
+	r.Failures += suite.Failures
+	r.Skipped += suite.Skipped
+}
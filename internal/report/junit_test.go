@@ -0,0 +1,64 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteJUnit(&out, testSummary()); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+	checkGolden(t, "junit.golden", out.Bytes())
+
+	if !strings.HasPrefix(out.String(), xml.Header) {
+		t.Errorf("WriteJUnit() output does not start with the XML header")
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("WriteJUnit() wrote invalid XML: %v", err)
+	}
+
+	// 4 module blocks, 3 of them outdated; 1 unpinned module; 1 unsupported source
+	if report.Tests != 6 || report.Failures != 4 || report.Skipped != 1 {
+		t.Errorf("tests, failures, skipped = %d, %d, %d, want 6, 4, 1", report.Tests, report.Failures, report.Skipped)
+	}
+	want := []struct {
+		name                     string
+		tests, failures, skipped int
+	}{
+		{vpcSource, 3, 2, 0},
+		{"terraform-aws-modules/eks/aws", 1, 1, 0},
+		{"unpinned", 1, 1, 0},
+		{"git::https://example.com/legacy.git?ref=v1&depth=1", 1, 0, 1},
+	}
+	if len(report.Suites) != len(want) {
+		t.Fatalf("testsuites = %d, want %d", len(report.Suites), len(want))
+	}
+	for i, w := range want {
+		suite := report.Suites[i]
+		if suite.Name != w.name || suite.Tests != w.tests || suite.Failures != w.failures || suite.Skipped != w.skipped {
+			t.Errorf("testsuite %d = %q %d/%d/%d, want %q %d/%d/%d", i,
+				suite.Name, suite.Tests, suite.Failures, suite.Skipped, w.name, w.tests, w.failures, w.skipped)
+		}
+	}
+
+	vpc := report.Suites[0].Cases[0]
+	if vpc.Failure == nil || vpc.Failure.Type != RuleMajorUpdate || vpc.File != "/repo/main.tf" || vpc.Line != 1 {
+		t.Errorf("module.vpc test case = %+v, want a major update failure at /repo/main.tf:1", vpc)
+	}
+	if report.Suites[0].Cases[2].Failure != nil {
+		t.Errorf("up-to-date module.core failed: %+v", report.Suites[0].Cases[2].Failure)
+	}
+
+	// Special characters are escaped and read back as written
+	if !strings.Contains(out.String(), "ref=v1&amp;depth=1") || !strings.Contains(out.String(), "&lt; 2.0") {
+		t.Errorf("WriteJUnit() did not escape & and <:\n%s", out.String())
+	}
+	if got := report.Suites[1].Cases[0].SystemOut; got != "held at 19.21.0: 20.8.0 requires Terraform >= 1.3.2 & < 2.0" {
+		t.Errorf("system-out = %q", got)
+	}
+}
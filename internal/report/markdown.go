@@ -0,0 +1,242 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// changeBadges maps change types to shields.io badges
+var changeBadges = map[version.ChangeType]string{
+	version.ChangeMajor: "![major](https://img.shields.io/badge/-major-red)",
+	version.ChangeMinor: "![minor](https://img.shields.io/badge/-minor-yellow)",
+	version.ChangePatch: "![patch](https://img.shields.io/badge/-patch-brightgreen)",
+}
+
+// WriteMarkdown writes the summary as GitHub-flavoured Markdown, suitable for pull request descriptions
+func WriteMarkdown(writer io.Writer, summary *UpdateSummary) error {
+	var b strings.Builder
+
+	b.WriteString("## Terraform Module Updates\n\n")
+	fmt.Fprintf(&b, "**%d** of **%d** module invocations can be updated.", summary.TotalUpdated, summary.TotalUsages)
+	if summary.Libyear > 0 {
+		fmt.Fprintf(&b, " Modules are **%.2f** libyears behind.", summary.Libyear)
+	}
+	b.WriteString("\n\n")
+
+	modules := append([]ModuleReport{}, summary.Modules...)
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Source < modules[j].Source })
+
+	writeMarkdownModules(&b, modules)
+	writeMarkdownProviders(&b, summary.Providers)
+	writeMarkdownLocations(&b, modules)
+	writeMarkdownUnpinned(&b, summary.UnpinnedModules)
+	writeMarkdownUnsupported(&b, summary.UnsupportedModules)
+
+	for _, mod := range modules {
+		if len(mod.Changelog) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "<details>\n<summary>Release notes for <code>%s</code></summary>\n\n", mod.Source)
+		writeMarkdownChangelog(&b, &mod)
+		b.WriteString("</details>\n\n")
+	}
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// writeMarkdownModules writes one table row per module and current version
+func writeMarkdownModules(b *strings.Builder, modules []ModuleReport) {
+	var rows []string
+	var held []string
+
+	for _, mod := range modules {
+		if mod.HoldReason != "" {
+			held = append(held, fmt.Sprintf("- `%s` held at %s: %s", mod.Source, heldVersion(&mod), mod.HoldReason))
+		}
+
+		currentVersions := make([]string, 0, len(mod.CurrentVersions))
+		for ver := range mod.CurrentVersions {
+			currentVersions = append(currentVersions, ver)
+		}
+		sort.Strings(currentVersions)
+
+		for _, ver := range currentVersions {
+			if !pendingUpdate(&mod, ver) {
+				continue
+			}
+
+			changeType, err := version.ClassifyChange(ver, mod.Target(ver))
+			badge := changeBadges[changeType]
+			if err != nil {
+				badge = "unknown"
+			}
+
+			target := mod.Target(ver)
+			if url := changelogURL(&mod, target); url != "" {
+				target = fmt.Sprintf("[%s](%s)", target, url)
+			}
+
+			rows = append(rows, fmt.Sprintf("| `%s` | %d | %s → %s | %s |",
+				mod.Source, len(uniqueStrings(mod.Locations)), ver, target, badge))
+		}
+	}
+
+	if len(rows) == 0 {
+		b.WriteString("All modules are up to date.\n\n")
+	} else {
+		b.WriteString("| Module | Files | Version | Change |\n")
+		b.WriteString("|--------|------:|---------|--------|\n")
+		b.WriteString(strings.Join(rows, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	if len(held) > 0 {
+		b.WriteString("**Held back**\n\n")
+		b.WriteString(strings.Join(held, "\n"))
+		b.WriteString("\n\n")
+	}
+}
+
+// writeMarkdownProviders writes a table of outdated provider constraints
+func writeMarkdownProviders(b *strings.Builder, providers []ProviderReport) {
+	var rows []string
+	for _, provider := range providers {
+		if provider.UpdateCount == 0 {
+			continue
+		}
+
+		constraints := make([]string, 0, len(provider.CurrentConstraints))
+		for c := range provider.CurrentConstraints {
+			constraints = append(constraints, fmt.Sprintf("`%s`", c))
+		}
+		sort.Strings(constraints)
+
+		rows = append(rows, fmt.Sprintf("| `%s` | %s | %s | %d |",
+			provider.Source, strings.Join(constraints, ", "), provider.LatestVersion, provider.UpdateCount))
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	b.WriteString("| Provider | Constraints | Latest | Updates |\n")
+	b.WriteString("|----------|-------------|--------|--------:|\n")
+	b.WriteString(strings.Join(rows, "\n"))
+	b.WriteString("\n\n")
+}
+
+// writeMarkdownLocations writes a collapsible list of files per outdated module
+func writeMarkdownLocations(b *strings.Builder, modules []ModuleReport) {
+	var sections []string
+	for _, mod := range modules {
+		if mod.UpdateCount == 0 || len(mod.Locations) == 0 {
+			continue
+		}
+
+		var section strings.Builder
+		fmt.Fprintf(&section, "- `%s`\n", mod.Source)
+		for _, loc := range uniqueStrings(mod.Locations) {
+			fmt.Fprintf(&section, "  - `%s`\n", loc)
+		}
+		for _, def := range mod.Definitions {
+			fmt.Fprintf(&section, "  - version defined in %s\n", def)
+		}
+		sections = append(sections, section.String())
+	}
+
+	if len(sections) == 0 {
+		return
+	}
+
+	b.WriteString("<details>\n<summary>Locations</summary>\n\n")
+	b.WriteString(strings.Join(sections, ""))
+	b.WriteString("\n</details>\n\n")
+}
+
+// writeMarkdownUnpinned writes a collapsible list of registry modules without a version
+func writeMarkdownUnpinned(b *strings.Builder, unpinned []UnpinnedModule) {
+	if len(unpinned) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "<details>\n<summary>Unpinned registry modules (%d)</summary>\n\n", len(unpinned))
+	for _, mod := range unpinned {
+		fmt.Fprintf(b, "- `%s` in `%s:%d` (module.%s)", mod.Source, mod.File, mod.Line, mod.BlockName)
+		if mod.LatestVersion != "" {
+			fmt.Fprintf(b, ", latest %s", mod.LatestVersion)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n</details>\n\n")
+}
+
+// writeMarkdownUnsupported writes a collapsible list of sources that cannot be updated
+func writeMarkdownUnsupported(b *strings.Builder, unsupported []UnsupportedSource) {
+	if len(unsupported) == 0 {
+		return
+	}
+
+	sorted := append([]UnsupportedSource{}, unsupported...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Source < sorted[j].Source })
+
+	fmt.Fprintf(b, "<details>\n<summary>Unsupported sources (%d)</summary>\n\n", len(sorted))
+	b.WriteString("| Source | Type | Usages |\n")
+	b.WriteString("|--------|------|-------:|\n")
+	for _, src := range sorted {
+		fmt.Fprintf(b, "| `%s` | %s | %d |\n", src.Source, src.Type, src.Count)
+	}
+	b.WriteString("\n</details>\n\n")
+}
+
+// writeMarkdownChangelog writes the release notes of a module
+func writeMarkdownChangelog(b *strings.Builder, mod *ModuleReport) {
+	for _, entry := range mod.Changelog {
+		title := entry.Version
+		if entry.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", entry.Version, entry.URL)
+		}
+		if date := publishedDate(entry.PublishedAt); date != "" {
+			title += " – " + date
+		}
+		fmt.Fprintf(b, "#### %s\n\n", title)
+
+		if entry.Notes != "" {
+			b.WriteString(entry.Notes)
+			b.WriteString("\n\n")
+		}
+	}
+}
+
+// changelogURL returns the release URL of a version when release notes were collected
+func changelogURL(mod *ModuleReport, ver string) string {
+	for _, entry := range mod.Changelog {
+		if entry.Version == ver {
+			return entry.URL
+		}
+	}
+	return ""
+}
+
+// heldVersion returns the version a held module stays at, for display
+func heldVersion(mod *ModuleReport) string {
+	if mod.UpcomingVersion == "" {
+		return "current version"
+	}
+	return mod.UpcomingVersion
+}
+
+// uniqueStrings returns values without duplicates, preserving order
+func uniqueStrings(values []string) []string {
+	var unique []string
+	for _, v := range values {
+		if !containsString(unique, v) {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
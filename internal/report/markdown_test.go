@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	summary := testSummary()
+	summary.Modules[0].Definitions = []string{"local.vpc_version (/repo/network/locals.tf:2)"}
+
+	var out bytes.Buffer
+	if err := WriteMarkdown(&out, summary); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	checkGolden(t, "markdown.golden", out.Bytes())
+
+	for _, want := range []string{
+		"**3** of **4** module invocations can be updated.",
+		"| `terraform-aws-modules/vpc/aws` | 2 | 4.0.0 → 5.1.0 | ![major]",
+		"| `terraform-aws-modules/vpc/aws` | 2 | 5.0.0 → 5.1.0 | ![minor]",
+		"- `terraform-aws-modules/eks/aws` held at 19.21.0: 20.8.0 requires Terraform >= 1.3.2 & < 2.0",
+		"| `hashicorp/aws` | `>= 4.0, < 5.0` | 5.31.0 | 1 |",
+		"- `terraform-aws-modules/s3-bucket/aws` in `/repo/storage.tf:7` (module.logs), latest 4.1.0",
+		"<summary>Unsupported sources (1)</summary>",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("WriteMarkdown() output lacks %q", want)
+		}
+	}
+	// Up-to-date versions get no row
+	if strings.Contains(out.String(), "5.1.0 → ") {
+		t.Errorf("WriteMarkdown() listed an up-to-date version:\n%s", out.String())
+	}
+}
+
+func TestWriteMarkdownUpToDate(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteMarkdown(&out, &UpdateSummary{}); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	want := "## Terraform Module Updates\n\n**0** of **0** module invocations can be updated.\n\nAll modules are up to date.\n\n"
+	if out.String() != want {
+		t.Errorf("WriteMarkdown() = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricPrefix namespaces the exported metric names
+const metricPrefix = "tfmv_"
+
+// openMetricsEscaper escapes label values as required by the exposition format
+var openMetricsEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricFamily is a named set of samples with the same type
+type metricFamily struct {
+	name    string
+	kind    string // gauge or counter
+	help    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels [][2]string
+	value  float64
+}
+
+func (f *metricFamily) add(value float64, labels ...[2]string) {
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+// WriteOpenMetrics writes module drift as OpenMetrics gauges and registry counters
+// The output can also be dropped in a node-exporter textfile collector directory
+func WriteOpenMetrics(writer io.Writer, summary *UpdateSummary) error {
+	behind := &metricFamily{name: "module_versions_behind", kind: "gauge",
+		help: "Releases between the current and the latest version of a module call"}
+	days := &metricFamily{name: "module_days_behind", kind: "gauge",
+		help: "Days since the current version of a module call was superseded"}
+	libyears := &metricFamily{name: "module_libyears", kind: "gauge",
+		help: "Years between the release of the current and the latest version of a module call"}
+	outdated := &metricFamily{name: "module_update_pending", kind: "gauge",
+		help: "Whether the module call will be updated (1) or not (0)"}
+	directories := &metricFamily{name: "directory_libyears", kind: "gauge",
+		help: "Libyears of all module calls in a directory"}
+	unpinned := &metricFamily{name: "module_unpinned", kind: "gauge",
+		help: "Registry module calls without a version constraint"}
+	info := &metricFamily{name: "module_info", kind: "gauge",
+		help: "Current and latest version of a module call, always 1"}
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		for _, usage := range mod.Usages {
+			// Versions are kept out of the value labels so that each call stays one series across upgrades
+			labels := [][2]string{
+				{"source", mod.Source},
+				{"path", usage.File},
+				{"block", usage.BlockName},
+			}
+			info.add(1, append(labels, [2]string{"version", usage.Version}, [2]string{"latest", mod.LatestVersion})...)
+
+			if metrics := usage.Staleness; metrics != nil {
+				behind.add(float64(metrics.ReleasesBehind), labels...)
+				days.add(float64(metrics.DaysBehind), labels...)
+				libyears.add(metrics.Libyear, labels...)
+			}
+
+			pending := 0.0
+			if pendingUpdate(mod, usage.Version) {
+				pending = 1
+			}
+			outdated.add(pending, labels...)
+		}
+	}
+
+	dirs := make([]string, 0, len(summary.LibyearByDirectory))
+	for dir := range summary.LibyearByDirectory {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		directories.add(summary.LibyearByDirectory[dir], [2]string{"path", dir})
+	}
+
+	for _, mod := range summary.UnpinnedModules {
+		unpinned.add(1, [2]string{"source", mod.Source}, [2]string{"path", mod.File}, [2]string{"block", mod.BlockName})
+	}
+
+	libyear := &metricFamily{name: "libyears", kind: "gauge", help: "Libyears of all module calls"}
+	libyear.add(summary.Libyear)
+
+	requests := &metricFamily{name: "registry_requests", kind: "counter", help: "HTTP requests sent to registries"}
+	requests.add(float64(summary.Registry.Requests))
+	errors := &metricFamily{name: "registry_errors", kind: "counter", help: "Modules or providers whose registry lookup failed"}
+	errors.add(float64(summary.Registry.Errors))
+	cacheHits := &metricFamily{name: "cache_hits", kind: "counter", help: "Registry lookups answered from the cache"}
+	cacheHits.add(float64(summary.Registry.CacheHits))
+
+	var b strings.Builder
+	for _, family := range []*metricFamily{info, behind, days, libyears, outdated, directories, unpinned, libyear, requests, errors, cacheHits} {
+		writeMetricFamily(&b, family)
+	}
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// writeMetricFamily writes the metadata and samples of a family
+// Counter samples get the _total suffix required by OpenMetrics
+func writeMetricFamily(b *strings.Builder, family *metricFamily) {
+	name := metricPrefix + family.name
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, family.kind)
+	fmt.Fprintf(b, "# HELP %s %s\n", name, family.help)
+
+	sampleName := name
+	if family.kind == "counter" {
+		sampleName += "_total"
+	}
+
+	for _, sample := range family.samples {
+		b.WriteString(sampleName)
+		if len(sample.labels) > 0 {
+			pairs := make([]string, 0, len(sample.labels))
+			for _, label := range sample.labels {
+				pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label[0], openMetricsEscaper.Replace(label[1])))
+			}
+			fmt.Fprintf(b, "{%s}", strings.Join(pairs, ","))
+		}
+		fmt.Fprintf(b, " %s\n", strconv.FormatFloat(sample.value, 'g', -1, 64))
+	}
+}
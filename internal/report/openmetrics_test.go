@@ -0,0 +1,80 @@
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/staleness"
+)
+
+// openMetricsLine matches the metadata and sample lines of the exposition format
+var openMetricsLine = regexp.MustCompile(`^(# (TYPE|HELP) tfmv_\w+ .+|tfmv_\w+(\{(\w+="([^"\\]|\\.)*",?)+\})? -?[0-9.e+]+)$`)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	summary := testSummary()
+	summary.Modules[0].Usages[0].File = `/repo/"quoted"\main.tf`
+	summary.Modules[0].Usages[0].Staleness = &staleness.Metrics{ReleasesBehind: 4, MajorDelta: 1, DaysBehind: 212, Libyear: 0.81}
+	summary.Libyear = 0.81
+	summary.LibyearByDirectory = map[string]float64{"/repo": 0.81, "/repo/network": 0}
+	summary.Registry = RegistryStats{Requests: 7, CacheHits: 3, Errors: 1}
+
+	var out bytes.Buffer
+	if err := WriteOpenMetrics(&out, summary); err != nil {
+		t.Fatalf("WriteOpenMetrics() error = %v", err)
+	}
+	checkGolden(t, "openmetrics.golden", out.Bytes())
+
+	if !strings.HasSuffix(out.String(), "\n# EOF\n") {
+		t.Fatalf("WriteOpenMetrics() output does not end with # EOF:\n%s", out.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n# EOF\n"), "\n")
+
+	// Each family declares its type before its samples, counters use the _total suffix
+	types := make(map[string]string)
+	for _, line := range lines {
+		if !openMetricsLine.MatchString(line) {
+			t.Errorf("invalid line %q", line)
+			continue
+		}
+		fields := strings.Fields(line)
+		if fields[0] == "#" {
+			if fields[1] == "TYPE" {
+				types[fields[2]] = fields[3]
+			}
+			continue
+		}
+
+		name := strings.SplitN(fields[0], "{", 2)[0]
+		family := strings.TrimSuffix(name, "_total")
+		switch kind, declared := types[family]; {
+		case !declared:
+			t.Errorf("sample %s before its # TYPE line", name)
+		case (kind == "counter") != strings.HasSuffix(name, "_total"):
+			t.Errorf("%s sample %s", kind, name)
+		}
+	}
+
+	// Value gauges keep one series per module call across version changes
+	for _, line := range lines {
+		if strings.HasPrefix(line, "tfmv_module_") && !strings.HasPrefix(line, "tfmv_module_info") && strings.Contains(line, "version=") {
+			t.Errorf("value sample %q has a version label", line)
+		}
+	}
+
+	for _, want := range []string{
+		`tfmv_module_info{source="terraform-aws-modules/vpc/aws",path="/repo/network/net.tf",block="edge",version="5.0.0",latest="5.1.0"} 1`,
+		`tfmv_module_versions_behind{source="terraform-aws-modules/vpc/aws",path="/repo/\"quoted\"\\main.tf",block="vpc"} 4`,
+		`tfmv_module_update_pending{source="terraform-aws-modules/vpc/aws",path="/repo/network/net.tf",block="core"} 0`,
+		`tfmv_directory_libyears{path="/repo"} 0.81`,
+		`tfmv_module_unpinned{source="terraform-aws-modules/s3-bucket/aws",path="/repo/storage.tf",block="logs"} 1`,
+		"tfmv_registry_requests_total 7",
+		"tfmv_registry_errors_total 1",
+		"tfmv_cache_hits_total 3",
+	} {
+		if !strings.Contains(out.String(), want+"\n") {
+			t.Errorf("WriteOpenMetrics() output lacks %s", want)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/staleness"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
 )
 
 // Printer handles output to console
@@ -47,6 +49,15 @@ func (p *Printer) Print(writer io.Writer) {
 		}
 	}
 
+	// Providers
+	if len(p.summary.Providers) > 0 {
+		fmt.Fprintln(writer, p.color.Sprintf(color.BoldBlue, "\nProviders"))
+		fmt.Fprintln(writer, p.color.Sprintf(color.Blue, "─────────"))
+		for _, provider := range p.summary.Providers {
+			p.printProviderReport(writer, &provider)
+		}
+	}
+
 	// Unsupported modules
 	if len(p.summary.UnsupportedModules) > 0 {
 		fmt.Fprintln(writer, p.color.Sprintf(color.BoldYellow, "\nUnsupported Modules"))
@@ -59,12 +70,44 @@ func (p *Printer) Print(writer io.Writer) {
 		fmt.Fprintln(writer)
 	}
 
+	// Unpinned registry modules
+	if len(p.summary.UnpinnedModules) > 0 {
+		fmt.Fprintln(writer, p.color.Sprintf(color.BoldYellow, "\nUnpinned Registry Modules"))
+		fmt.Fprintln(writer, p.color.Sprintf(color.Yellow, "─────────────────────────"))
+		for _, unpinned := range p.summary.UnpinnedModules {
+			fmt.Fprintf(writer, "\n%s\n", p.color.Warning("! %s (%s)", unpinned.Source, unpinned.Type.String()))
+			fmt.Fprintf(writer, "  Block:          module.%s\n", unpinned.BlockName)
+			fmt.Fprintf(writer, "  Location:       %s:%d\n", unpinned.File, unpinned.Line)
+			if unpinned.LatestVersion != "" {
+				fmt.Fprintf(writer, "  Latest Version: %s\n", p.color.Info("%s", unpinned.LatestVersion))
+			}
+			fmt.Fprintf(writer, "  Status:         %s\n", p.color.Warning("NO VERSION CONSTRAINT"))
+		}
+		fmt.Fprintln(writer)
+	}
+
 	// Summary stats
 	fmt.Fprintln(writer, p.color.Sprintf(color.BoldBlue, "\nSummary"))
 	fmt.Fprintln(writer, p.color.Sprintf(color.Blue, "───────"))
 	fmt.Fprintf(writer, "  Total Module Invocations:           %d\n", p.summary.TotalUsages)
 	fmt.Fprintf(writer, "  Module Invocations to Update:       %d\n", p.summary.TotalUpdated)
 	fmt.Fprintf(writer, "  Module Invocations Already Latest:  %d\n", p.summary.TotalUsages-p.summary.TotalUpdated)
+	if len(p.summary.UnpinnedModules) > 0 {
+		fmt.Fprintf(writer, "  Unpinned Registry Modules:          %d\n", len(p.summary.UnpinnedModules))
+	}
+	if p.summary.Libyear > 0 {
+		fmt.Fprintf(writer, "  Libyear:                            %.2f\n", p.summary.Libyear)
+		if len(p.summary.LibyearByDirectory) > 1 {
+			var dirs []string
+			for dir := range p.summary.LibyearByDirectory {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			for _, dir := range dirs {
+				fmt.Fprintf(writer, "    %-34s%.2f\n", dir, p.summary.LibyearByDirectory[dir])
+			}
+		}
+	}
 
 	// Version change details
 	if len(p.summary.ByVersionChange) > 0 {
@@ -100,10 +143,29 @@ func (p *Printer) printModuleReport(writer io.Writer, mod *ModuleReport) {
 	fmt.Fprintln(writer, strings.Join(versionLines, ", "))
 
 	fmt.Fprintf(writer, "  Latest Version:    %s\n", p.color.Info("%s", mod.LatestVersion))
+	if targets := targetLines(mod); len(targets) > 0 {
+		fmt.Fprintf(writer, "  Target Version:    %s\n", p.color.Info("%s", strings.Join(targets, ", ")))
+	}
+	for _, line := range stalenessLines(mod) {
+		fmt.Fprintf(writer, "  Behind:            %s\n", p.color.Warning("%s", line))
+	}
+	if mod.HoldReason != "" {
+		fmt.Fprintf(writer, "  Held:              %s\n", p.color.Warning("held at %s: %s", heldVersion(mod), mod.HoldReason))
+	}
+	for _, warning := range mod.Warnings {
+		fmt.Fprintf(writer, "  Warning:           %s\n", p.color.Warning("%s", warning))
+	}
 	fmt.Fprintf(writer, "  Modules to Update: %s\n", p.color.Status("%d", mod.UpdateCount))
+	if groups := usageGroups(mod); len(groups) > 0 {
+		fmt.Fprintf(writer, "  Group:             %s\n", p.color.Info("%s", strings.Join(groups, ", ")))
+	}
 
 	if mod.UpdateCount > 0 {
 		fmt.Fprintf(writer, "  Status:            %s\n", p.color.Warning("UPDATE AVAILABLE"))
+	} else if mod.HoldReason != "" {
+		fmt.Fprintf(writer, "  Status:            %s\n", p.color.Warning("HELD"))
+	} else if behindLatest(mod) {
+		fmt.Fprintf(writer, "  Status:            %s\n", p.color.Info("NO UPDATE SELECTED"))
 	} else {
 		fmt.Fprintf(writer, "  Status:            %s\n", p.color.Success("ALREADY AT LATEST"))
 	}
@@ -117,6 +179,153 @@ func (p *Printer) printModuleReport(writer io.Writer, mod *ModuleReport) {
 	} else if len(mod.Locations) > 5 {
 		fmt.Fprintf(writer, "  Files: %d files (...)\n", len(mod.Locations))
 	}
+
+	// Versions defined through locals or variables
+	if len(mod.Definitions) > 0 {
+		fmt.Fprintln(writer, "  Defined In:")
+		for _, def := range mod.Definitions {
+			fmt.Fprintf(writer, "    - %s\n", def)
+		}
+	}
+
+	// Breaking changes affecting module calls
+	if len(mod.Impacts) > 0 {
+		fmt.Fprintln(writer, "  Impact:")
+		for _, moduleImpact := range mod.Impacts {
+			fmt.Fprintf(writer, "    module.%s (%s:%d) %s → %s\n",
+				moduleImpact.BlockName, moduleImpact.File, moduleImpact.Line, moduleImpact.FromVersion, moduleImpact.ToVersion)
+			for _, finding := range moduleImpact.Findings {
+				fmt.Fprintf(writer, "      - %s\n", p.color.Warning("%s", finding))
+			}
+			if len(moduleImpact.RemovedResources) > 0 {
+				fmt.Fprintf(writer, "      - resources removed: %s\n", strings.Join(moduleImpact.RemovedResources, ", "))
+			}
+		}
+	}
+
+	// Release notes between current and upcoming version
+	if len(mod.Changelog) > 0 {
+		fmt.Fprintln(writer, "  Changelog:")
+		for _, entry := range mod.Changelog {
+			fmt.Fprintf(writer, "    %s", p.color.Info("%s", entry.Version))
+			if date := publishedDate(entry.PublishedAt); date != "" {
+				fmt.Fprintf(writer, " (%s)", date)
+			}
+			if entry.URL != "" {
+				fmt.Fprintf(writer, " %s", entry.URL)
+			}
+			fmt.Fprintln(writer)
+
+			if entry.Notes == "" {
+				continue
+			}
+			lines := strings.Split(entry.Notes, "\n")
+			for i, line := range lines {
+				if i == maxNotesLines {
+					fmt.Fprintf(writer, "      … %d more lines\n", len(lines)-maxNotesLines)
+					break
+				}
+				fmt.Fprintf(writer, "      %s\n", strings.TrimRight(line, "\r "))
+			}
+		}
+	}
+}
+
+// usageGroups returns the distinct update groups of a module's usages, sorted
+func usageGroups(mod *ModuleReport) []string {
+	var groups []string
+	for _, usage := range mod.Usages {
+		if usage.Group != "" && !containsString(groups, usage.Group) {
+			groups = append(groups, usage.Group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// stalenessLines describes how far each outdated current version is behind, by version
+func stalenessLines(mod *ModuleReport) []string {
+	byVersion := make(map[string]*staleness.Metrics)
+	for _, usage := range mod.Usages {
+		if usage.Staleness != nil && usage.Staleness.Behind() {
+			byVersion[usage.Version] = usage.Staleness
+		}
+	}
+
+	var lines []string
+	for ver, metrics := range byVersion {
+		line := fmt.Sprintf("%s is %s behind (%s)", ver, plural(metrics.ReleasesBehind, "release"), semverDelta(metrics))
+		if metrics.DaysBehind > 0 {
+			line += fmt.Sprintf(", superseded %s ago", plural(metrics.DaysBehind, "day"))
+		}
+		if metrics.Libyear > 0 {
+			line += fmt.Sprintf(", %.2f libyears", metrics.Libyear)
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// semverDelta formats the most significant semver distance, e.g., "+1 major"
+func semverDelta(metrics *staleness.Metrics) string {
+	switch {
+	case metrics.MajorDelta > 0:
+		return fmt.Sprintf("+%d major", metrics.MajorDelta)
+	case metrics.MinorDelta > 0:
+		return fmt.Sprintf("+%d minor", metrics.MinorDelta)
+	default:
+		return fmt.Sprintf("+%d patch", metrics.PatchDelta)
+	}
+}
+
+// maxNotesLines bounds the release notes lines printed per version in text output
+const maxNotesLines = 15
+
+// publishedDate returns the date part of an RFC 3339 timestamp
+func publishedDate(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
+
+func (p *Printer) printProviderReport(writer io.Writer, provider *ProviderReport) {
+	fmt.Fprintf(writer, "\n%s\n", p.color.Success("✓ %s (provider)", provider.Source))
+
+	fmt.Fprint(writer, "  Current Constraints: ")
+	var constraintLines []string
+	for c, count := range provider.CurrentConstraints {
+		constraintLines = append(constraintLines, fmt.Sprintf("%q (%d)", c, count))
+	}
+	sort.Strings(constraintLines)
+	fmt.Fprintln(writer, strings.Join(constraintLines, ", "))
+
+	fmt.Fprintf(writer, "  Latest Version:      %s\n", p.color.Info("%s", provider.LatestVersion))
+	var rewrites []string
+	for constraint, target := range provider.Targets {
+		rewrites = append(rewrites, fmt.Sprintf("%q → %q", constraint, target))
+	}
+	sort.Strings(rewrites)
+	if len(rewrites) > 0 {
+		fmt.Fprintf(writer, "  Rewritten To:        %s\n", p.color.Info("%s", strings.Join(rewrites, ", ")))
+	}
+	fmt.Fprintf(writer, "  Entries to Update:   %s\n", p.color.Status("%d", provider.UpdateCount))
+
+	if provider.UpdateCount > 0 {
+		fmt.Fprintf(writer, "  Status:              %s\n", p.color.Warning("UPDATE AVAILABLE"))
+	} else {
+		fmt.Fprintf(writer, "  Status:              %s\n", p.color.Success("ALREADY AT LATEST"))
+	}
+
+	if len(provider.Locations) > 0 && len(provider.Locations) <= 5 {
+		fmt.Fprintln(writer, "  Files:")
+		for _, loc := range provider.Locations {
+			fmt.Fprintf(writer, "    - %s\n", loc)
+		}
+	} else if len(provider.Locations) > 5 {
+		fmt.Fprintf(writer, "  Files: %d files (...)\n", len(provider.Locations))
+	}
 }
 
 // PrintError prints an error message
@@ -130,3 +339,36 @@ func PrintSuccess(message string) {
 	colored := color.New()
 	fmt.Printf("\n%s\n\n", colored.Success("✅ %s", message))
 }
+
+// behindLatest reports whether a module has usages older than its latest version,
+// e.g. when its strategy selected no newer version
+func behindLatest(mod *ModuleReport) bool {
+	for ver := range mod.CurrentVersions {
+		if newer, err := version.IsNewer(ver, mod.LatestVersion); err == nil && newer {
+			return true
+		}
+	}
+	return false
+}
+
+// targetLines describes the target versions that differ from the latest release, as a
+// single version when every current version shares it or as "from → to" changes otherwise
+func targetLines(mod *ModuleReport) []string {
+	distinct := make(map[string]bool)
+	for _, target := range mod.Targets {
+		distinct[target] = true
+	}
+	if len(distinct) == 0 || (len(distinct) == 1 && distinct[mod.LatestVersion]) {
+		return nil
+	}
+	if len(distinct) == 1 {
+		return []string{mod.UpcomingVersion}
+	}
+
+	var lines []string
+	for ver, target := range mod.Targets {
+		lines = append(lines, fmt.Sprintf("%s → %s", ver, target))
+	}
+	sort.Strings(lines)
+	return lines
+}
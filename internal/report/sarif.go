@@ -0,0 +1,311 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "terraform-module-versions"
+	sarifSrcRoot = "%SRCROOT%" // Base of the URIs of files under the source root
+)
+
+// SARIF rule IDs, one per kind of finding
+const (
+	RuleMajorUpdate = "module-major-update"
+	RuleMinorUpdate = "module-minor-update"
+	RulePatchUpdate = "module-patch-update"
+	RuleUnpinned    = "module-unpinned"
+	RuleUnsupported = "module-unsupported-source"
+)
+
+// sarifRules describes the rules reported by the tool, in reporting order
+var sarifRules = []sarifRule{
+	{ID: RuleMajorUpdate, Name: "MajorModuleUpdate", Level: "warning", Description: "A new major version of the module is available"},
+	{ID: RuleMinorUpdate, Name: "MinorModuleUpdate", Level: "warning", Description: "A new minor version of the module is available"},
+	{ID: RulePatchUpdate, Name: "PatchModuleUpdate", Level: "note", Description: "A new patch version of the module is available"},
+	{ID: RuleUnpinned, Name: "UnpinnedModule", Level: "warning", Description: "Registry module call has no version constraint"},
+	{ID: RuleUnsupported, Name: "UnsupportedModuleSource", Level: "note", Description: "Module source cannot be checked for updates"},
+}
+
+// Minimal SARIF 2.1.0 object model
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string                `json:"name"`
+		InformationURI string                `json:"informationUri"`
+		Rules          []sarifRuleDescriptor `json:"rules"`
+	}
+
+	sarifRuleDescriptor struct {
+		ID                   string            `json:"id"`
+		Name                 string            `json:"name"`
+		ShortDescription     sarifMessage      `json:"shortDescription"`
+		DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+	}
+
+	sarifRuleDefaults struct {
+		Level string `json:"level"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+
+	sarifFix struct {
+		Description     sarifMessage          `json:"description"`
+		ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+	}
+
+	sarifArtifactChange struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Replacements     []sarifReplacement    `json:"replacements"`
+	}
+
+	sarifReplacement struct {
+		DeletedRegion   sarifRegion  `json:"deletedRegion"`
+		InsertedContent sarifMessage `json:"insertedContent"`
+	}
+)
+
+type sarifRule struct {
+	ID          string
+	Name        string
+	Level       string
+	Description string
+}
+
+// WriteSARIF writes outdated, unpinned and unsupported module usages as a SARIF 2.1.0 log
+// Files are located relative to the summary's source root, or the working directory
+func WriteSARIF(writer io.Writer, summary *UpdateSummary) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: "https://github.com/vdesjardins/terraform-module-versions",
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, rule := range sarifRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleDescriptor{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifRuleDefaults{Level: rule.Level},
+		})
+	}
+
+	for i := range summary.Modules {
+		mod := &summary.Modules[i]
+		for _, usage := range mod.Usages {
+			if !pendingUpdate(mod, usage.Version) {
+				continue
+			}
+			run.Results = append(run.Results, outdatedResult(summary.SourceRoot, mod, usage))
+		}
+	}
+
+	for _, mod := range summary.UnpinnedModules {
+		run.Results = append(run.Results, newSARIFResult(summary.SourceRoot, RuleUnpinned, unpinnedMessage(mod), mod.File, sarifRegion{StartLine: mod.Line}))
+	}
+
+	for _, src := range summary.UnsupportedModules {
+		for _, usage := range src.Usages {
+			message := fmt.Sprintf("module.%s uses %s (%s), which cannot be checked for updates", usage.BlockName, src.Source, src.Type)
+			run.Results = append(run.Results, newSARIFResult(summary.SourceRoot, RuleUnsupported, message, usage.File, usageRegion(usage)))
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// outdatedResult reports a usage behind its target version, with a fix when the version is a literal
+func outdatedResult(root string, mod *ModuleReport, usage UsageReport) sarifResult {
+	target := mod.Target(usage.Version)
+	result := newSARIFResult(root, updateRule(usage.Version, target), outdatedMessage(mod, usage), usage.File, usageRegion(usage))
+
+	// Versions read from locals/variables are changed at their definition, not in the block
+	if usage.VersionRange != nil && usage.Definition == "" {
+		result.Fixes = []sarifFix{{
+			Description: sarifMessage{Text: fmt.Sprintf("Update %s to %s", mod.Source, target)},
+			ArtifactChanges: []sarifArtifactChange{{
+				ArtifactLocation: artifactLocation(root, usage.File),
+				Replacements: []sarifReplacement{{
+					DeletedRegion:   usageRegion(usage),
+					InsertedContent: sarifMessage{Text: strconv.Quote(target)},
+				}},
+			}},
+		}}
+	}
+
+	return result
+}
+
+// updateRule returns the rule ID matching the change type of an update
+// Versions that cannot be compared are reported as major updates
+func updateRule(current, target string) string {
+	changeType, err := version.ClassifyChange(current, target)
+	if err != nil {
+		return RuleMajorUpdate
+	}
+
+	switch changeType {
+	case version.ChangeMinor:
+		return RuleMinorUpdate
+	case version.ChangePatch:
+		return RulePatchUpdate
+	default:
+		return RuleMajorUpdate
+	}
+}
+
+// ruleLevel returns the SARIF level of a rule
+func ruleLevel(ruleID string) string {
+	for _, rule := range sarifRules {
+		if rule.ID == ruleID {
+			return rule.Level
+		}
+	}
+	return "warning"
+}
+
+// outdatedMessage describes a usage behind its target version
+func outdatedMessage(mod *ModuleReport, usage UsageReport) string {
+	message := fmt.Sprintf("module.%s uses %s %s; %s is available", usage.BlockName, mod.Source, usage.Version, mod.Target(usage.Version))
+	if usage.Definition != "" {
+		message += fmt.Sprintf(" (version set by %s)", usage.Definition)
+	}
+	return message
+}
+
+// unpinnedMessage describes a registry module call without a version
+func unpinnedMessage(mod UnpinnedModule) string {
+	message := fmt.Sprintf("module.%s uses %s without a version constraint", mod.BlockName, mod.Source)
+	if mod.LatestVersion != "" {
+		message += fmt.Sprintf("; latest is %s", mod.LatestVersion)
+	}
+	return message
+}
+
+func newSARIFResult(root, ruleID, message, file string, region sarifRegion) sarifResult {
+	result := sarifResult{
+		RuleID:  ruleID,
+		Message: sarifMessage{Text: message},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: artifactLocation(root, file),
+			Region:           region,
+		}}},
+	}
+
+	result.Level = ruleLevel(ruleID)
+	for i, rule := range sarifRules {
+		if rule.ID == ruleID {
+			result.RuleIndex = i
+		}
+	}
+
+	return result
+}
+
+// usageRegion returns the region of a usage's version value, or its block line when unknown
+func usageRegion(usage UsageReport) sarifRegion {
+	if rng := usage.VersionRange; rng != nil {
+		return regionOf(rng)
+	}
+	return sarifRegion{StartLine: usage.Line}
+}
+
+func regionOf(rng *finder.Range) sarifRegion {
+	return sarifRegion{
+		StartLine:   rng.StartLine,
+		StartColumn: rng.StartColumn,
+		EndLine:     rng.EndLine,
+		EndColumn:   rng.EndColumn,
+	}
+}
+
+// artifactLocation locates a file relative to the source root, as code scanning tools
+// resolve %SRCROOT% to their checkout; files outside of it get an absolute file URI
+func artifactLocation(root, file string) sarifArtifactLocation {
+	abs := resolvePath(file)
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if rel, err := filepath.Rel(resolvePath(root), abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return sarifArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(rel)}).String(), URIBaseID: sarifSrcRoot}
+	}
+	return sarifArtifactLocation{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()}
+}
+
+// resolvePath returns the absolute path of a file with symlinks resolved when possible
+func resolvePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteSARIF(&out, testSummary()); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+	checkGolden(t, "sarif.golden", out.Bytes())
+
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("WriteSARIF() wrote invalid JSON: %v", err)
+	}
+	if log.Schema != sarifSchema || log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("$schema, version, runs = %q, %q, %d", log.Schema, log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(sarifRules) {
+		t.Errorf("rules = %d, want %d", len(run.Tool.Driver.Rules), len(sarifRules))
+	}
+
+	want := []struct {
+		rule, uri, baseID string
+		line              int
+	}{
+		{RuleMajorUpdate, "main.tf", sarifSrcRoot, 3},
+		{RuleMinorUpdate, "network/net.tf", sarifSrcRoot, 5},
+		{RuleMinorUpdate, "eks.tf", sarifSrcRoot, 3},
+		{RuleUnpinned, "storage.tf", sarifSrcRoot, 7},
+		{RuleUnsupported, "file:///shared/legacy.tf", "", 2},
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("results = %d, want %d", len(run.Results), len(want))
+	}
+	for i, w := range want {
+		result := run.Results[i]
+		location := result.Locations[0].PhysicalLocation
+		if result.RuleID != w.rule || run.Tool.Driver.Rules[result.RuleIndex].ID != w.rule {
+			t.Errorf("result %d: ruleId = %q (index %d), want %q", i, result.RuleID, result.RuleIndex, w.rule)
+		}
+		if location.ArtifactLocation.URI != w.uri || location.ArtifactLocation.URIBaseID != w.baseID {
+			t.Errorf("result %d: location = %+v, want %q based on %q", i, location.ArtifactLocation, w.uri, w.baseID)
+		}
+		if location.Region.StartLine != w.line {
+			t.Errorf("result %d: startLine = %d, want %d", i, location.Region.StartLine, w.line)
+		}
+	}
+
+	// Only literal versions get a fix
+	if len(run.Results[0].Fixes) != 1 || len(run.Results[1].Fixes) != 0 {
+		t.Errorf("fixes = %d, %d, want 1, 0", len(run.Results[0].Fixes), len(run.Results[1].Fixes))
+	}
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/staleness"
+)
+
+// SummarizeStaleness totals the libyears of module usages per directory and for the whole tree
+func SummarizeStaleness(summary *UpdateSummary) {
+	summary.Libyear = 0
+	summary.LibyearByDirectory = make(map[string]float64)
+
+	for _, mod := range summary.Modules {
+		for _, usage := range mod.Usages {
+			if usage.Staleness == nil {
+				continue
+			}
+			summary.Libyear += usage.Staleness.Libyear
+			summary.LibyearByDirectory[filepath.Dir(usage.File)] += usage.Staleness.Libyear
+		}
+	}
+
+	summary.Libyear = roundLibyear(summary.Libyear)
+	for dir, libyear := range summary.LibyearByDirectory {
+		summary.LibyearByDirectory[dir] = roundLibyear(libyear)
+	}
+}
+
+// SortBySeverity orders modules from the most to the least outdated
+// Modules are ranked by their most outdated usage: semver distance first, then days
+// behind, libyears and releases behind
+func SortBySeverity(summary *UpdateSummary) {
+	worst := make(map[string]staleness.Metrics, len(summary.Modules))
+	for _, mod := range summary.Modules {
+		worst[mod.Source] = worstStaleness(&mod)
+	}
+
+	sort.SliceStable(summary.Modules, func(i, j int) bool {
+		a, b := worst[summary.Modules[i].Source], worst[summary.Modules[j].Source]
+		if cmp := compareStaleness(a, b); cmp != 0 {
+			return cmp > 0
+		}
+		return summary.Modules[i].Source < summary.Modules[j].Source
+	})
+}
+
+// worstStaleness returns the metrics of the most outdated usage of a module
+func worstStaleness(mod *ModuleReport) staleness.Metrics {
+	var worst staleness.Metrics
+	for _, usage := range mod.Usages {
+		if usage.Staleness != nil && compareStaleness(*usage.Staleness, worst) > 0 {
+			worst = *usage.Staleness
+		}
+	}
+	return worst
+}
+
+// compareStaleness returns 1 if a is more outdated than b, -1 if less and 0 if equal
+func compareStaleness(a, b staleness.Metrics) int {
+	keys := [][2]float64{
+		{float64(a.MajorDelta), float64(b.MajorDelta)},
+		{float64(a.MinorDelta), float64(b.MinorDelta)},
+		{float64(a.PatchDelta), float64(b.PatchDelta)},
+		{float64(a.DaysBehind), float64(b.DaysBehind)},
+		{a.Libyear, b.Libyear},
+		{float64(a.ReleasesBehind), float64(b.ReleasesBehind)},
+	}
+	for _, key := range keys {
+		switch {
+		case key[0] > key[1]:
+			return 1
+		case key[0] < key[1]:
+			return -1
+		}
+	}
+	return 0
+}
+
+func roundLibyear(libyear float64) float64 {
+	return math.Round(libyear*100) / 100
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/source"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of output formats")
+
+// testSummary returns a small report under /repo: outdated, held and up-to-date module
+// calls, an unpinned call, an unsupported source outside of the repository and a provider
+func testSummary() *UpdateSummary {
+	return &UpdateSummary{
+		SourceRoot: "/repo",
+		Modules: []ModuleReport{
+			{
+				Source:          vpcSource,
+				Type:            source.SourceTypeTerraformRegistry,
+				Supported:       true,
+				CurrentVersions: map[string]int{"4.0.0": 1, "5.0.0": 1, "5.1.0": 1},
+				LatestVersion:   "5.1.0",
+				TotalUsages:     3,
+				UpdateCount:     2,
+				UpcomingVersion: "5.1.0",
+				Targets:         map[string]string{"4.0.0": "5.1.0", "5.0.0": "5.1.0"},
+				Locations:       []string{"/repo/main.tf", "/repo/network/net.tf"},
+				Usages: []UsageReport{
+					{BlockName: "vpc", File: "/repo/main.tf", Line: 1, Version: "4.0.0",
+						VersionRange: &finder.Range{StartLine: 3, StartColumn: 13, EndLine: 3, EndColumn: 20}},
+					{BlockName: "edge", File: "/repo/network/net.tf", Line: 5, Version: "5.0.0", Definition: "local.vpc_version"},
+					{BlockName: "core", File: "/repo/network/net.tf", Line: 12, Version: "5.1.0"},
+				},
+			},
+			{
+				Source:          "terraform-aws-modules/eks/aws",
+				Type:            source.SourceTypeTerraformRegistry,
+				Supported:       true,
+				CurrentVersions: map[string]int{"19.0.0": 1},
+				LatestVersion:   "20.8.0",
+				TotalUsages:     1,
+				UpdateCount:     1,
+				UpcomingVersion: "19.21.0",
+				Targets:         map[string]string{"19.0.0": "19.21.0"},
+				Locations:       []string{"/repo/eks.tf"},
+				HoldReason:      "20.8.0 requires Terraform >= 1.3.2 & < 2.0",
+				Usages: []UsageReport{
+					{BlockName: "cluster", File: "/repo/eks.tf", Line: 1, Version: "19.0.0",
+						VersionRange: &finder.Range{StartLine: 3, StartColumn: 13, EndLine: 3, EndColumn: 21}},
+				},
+			},
+		},
+		UnpinnedModules: []UnpinnedModule{
+			{Source: "terraform-aws-modules/s3-bucket/aws", Type: source.SourceTypeTerraformRegistry,
+				BlockName: "logs", File: "/repo/storage.tf", Line: 7, LatestVersion: "4.1.0"},
+		},
+		UnsupportedModules: []UnsupportedSource{
+			{Source: "git::https://example.com/legacy.git?ref=v1&depth=1", Type: source.SourceTypeUnknown, Count: 1,
+				Usages: []UsageReport{{BlockName: "legacy", File: "/shared/legacy.tf", Line: 2, Version: "v1"}}},
+		},
+		Providers: []ProviderReport{
+			{
+				Source:             "hashicorp/aws",
+				CurrentConstraints: map[string]int{">= 4.0, < 5.0": 1},
+				LatestVersion:      "5.31.0",
+				TotalUsages:        1,
+				UpdateCount:        1,
+				Targets:            map[string]string{">= 4.0, < 5.0": ">= 4.67, < 5.0"},
+				Locations:          []string{"/repo/versions.tf"},
+			},
+		},
+		TotalUsages:      4,
+		TotalUpdated:     3,
+		ByVersionChange:  map[string]int{"4.0.0 → 5.1.0": 1, "5.0.0 → 5.1.0": 1, "19.0.0 → 19.21.0": 1},
+		SuportedCount:    2,
+		UnsupportedCount: 1,
+	}
+}
+
+// checkGolden compares output with testdata/<name>, rewriting the file with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("output differs from %s (go test -update rewrites it):\n%s", path, got)
+	}
+}
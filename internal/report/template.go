@@ -0,0 +1,130 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/color"
+	"github.com/vdesjardins/terraform-module-versions/internal/version"
+)
+
+// templateColors maps color names usable in templates to ANSI colors
+var templateColors = map[string]color.Color{
+	"red":         color.Red,
+	"green":       color.Green,
+	"yellow":      color.Yellow,
+	"blue":        color.Blue,
+	"cyan":        color.Cyan,
+	"bold-red":    color.BoldRed,
+	"bold-green":  color.BoldGreen,
+	"bold-yellow": color.BoldYellow,
+	"bold-blue":   color.BoldBlue,
+	"bold-cyan":   color.BoldCyan,
+}
+
+// TemplateFormatter renders the summary through a text/template
+type TemplateFormatter struct {
+	tmpl *template.Template
+}
+
+// NewTemplateFormatter parses a template file
+func NewTemplateFormatter(path string) (*TemplateFormatter, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return ParseTemplate(filepath.Base(path), string(content))
+}
+
+// ParseTemplate parses template text with the report helper functions
+func ParseTemplate(name, text string) (*TemplateFormatter, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs(color.New())).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+// Format executes the template with the summary as data
+func (f *TemplateFormatter) Format(writer io.Writer, summary *UpdateSummary) error {
+	if err := f.tmpl.Execute(writer, summary); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
+
+// TemplateFuncs returns the helper functions available to report templates:
+//
+//	changeType "1.2.0" "2.0.0"   major, minor, patch, none or unknown
+//	pending $module "1.2.0"      whether a usage at that version will be updated
+//	color "red" "text"           colorizes text when the terminal supports it
+//	since .PublishedAt           relative time, e.g., "3 months ago"
+//	date .PublishedAt            date part of a timestamp
+//	join .Locations ", "         strings.Join
+func TemplateFuncs(colors *color.ColoredOutput) template.FuncMap {
+	return template.FuncMap{
+		"changeType": func(from, to string) string {
+			changeType, err := version.ClassifyChange(from, to)
+			if err != nil {
+				return "unknown"
+			}
+			return string(changeType)
+		},
+		"pending": func(mod ModuleReport, ver string) bool {
+			return pendingUpdate(&mod, ver)
+		},
+		"color": func(name, text string) (string, error) {
+			c, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color %q", name)
+			}
+			return colors.Sprintf(c, "%s", text), nil
+		},
+		"since": func(timestamp string) string {
+			return relativeTime(timestamp, time.Now())
+		},
+		"date": publishedDate,
+		"join": strings.Join,
+	}
+}
+
+// relativeTime describes how long before now an RFC 3339 timestamp is
+// Unparsable timestamps are returned as is
+func relativeTime(timestamp string, now time.Time) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return plural(int(elapsed/time.Minute), "minute") + " ago"
+	case elapsed < 24*time.Hour:
+		return plural(int(elapsed/time.Hour), "hour") + " ago"
+	}
+
+	days := int(elapsed / (24 * time.Hour))
+	switch {
+	case days < 30:
+		return plural(days, "day") + " ago"
+	case days < 365:
+		return plural(days/30, "month") + " ago"
+	default:
+		return plural(days/365, "year") + " ago"
+	}
+}
+
+func plural(count int, unit string) string {
+	if count == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
+}
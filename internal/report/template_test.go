@@ -0,0 +1,74 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timestamp string
+		want      string
+	}{
+		{"2026-10-18T11:59:30Z", "just now"},
+		{"2026-10-18T11:59:00Z", "1 minute ago"},
+		{"2026-10-18T11:15:00Z", "45 minutes ago"},
+		{"2026-10-18T09:00:00Z", "3 hours ago"},
+		{"2026-10-17T12:00:00Z", "1 day ago"},
+		{"2026-10-01T12:00:00Z", "17 days ago"},
+		{"2026-08-18T12:00:00Z", "2 months ago"},
+		{"2025-10-18T12:00:00Z", "1 year ago"},
+		{"2023-06-01T12:00:00+02:00", "3 years ago"},
+		{"not a timestamp", "not a timestamp"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := relativeTime(tt.timestamp, now); got != tt.want {
+			t.Errorf("relativeTime(%q) = %q, want %q", tt.timestamp, got, tt.want)
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	text := `{{ range .Modules }}{{ $mod := . }}{{ .Source }}: {{ join .Locations ", " }}
+{{ range .Usages }}{{ if pending $mod .Version }}  {{ .Version }} → {{ $mod.Target .Version }} ({{ changeType .Version ($mod.Target .Version) }})
+{{ end }}{{ end }}{{ end }}`
+
+	formatter, err := ParseTemplate("report", text)
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := formatter.Format(&out, testSummary()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	want := `terraform-aws-modules/vpc/aws: /repo/main.tf, /repo/network/net.tf
+  4.0.0 → 5.1.0 (major)
+  5.0.0 → 5.1.0 (minor)
+terraform-aws-modules/eks/aws: /repo/eks.tf
+  19.0.0 → 19.21.0 (minor)
+`
+	if out.String() != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	if _, err := ParseTemplate("broken", "{{ .Modules "); err == nil || !strings.Contains(err.Error(), "failed to parse template broken") {
+		t.Errorf("ParseTemplate() error = %v, want a parse error", err)
+	}
+
+	formatter, err := ParseTemplate("colors", `{{ color "purple" "text" }}`)
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+	if err := formatter.Format(&bytes.Buffer{}, testSummary()); err == nil || !strings.Contains(err.Error(), `unknown color "purple"`) {
+		t.Errorf("Format() error = %v, want an unknown color", err)
+	}
+}
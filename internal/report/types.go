@@ -1,36 +1,117 @@
 package report
 
 import (
+	"github.com/vdesjardins/terraform-module-versions/internal/changelog"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/vdesjardins/terraform-module-versions/internal/impact"
 	"github.com/vdesjardins/terraform-module-versions/internal/source"
+	"github.com/vdesjardins/terraform-module-versions/internal/staleness"
 )
 
 // ModuleReport represents a summary report for one module source
 type ModuleReport struct {
-	Source          string                // Module source
-	Type            source.SourceTypeEnum // Registry type
-	Supported       bool                  // Whether we can fetch versions
-	CurrentVersions map[string]int        // Version -> count of usages
-	LatestVersion   string                // Latest available version
-	TotalUsages     int                   // Total module invocations
-	UpdateCount     int                   // Count that will be updated
-	UpcomingVersion string                // What version will be updated to
-	Locations       []string              // File paths with this module
+	Source          string                `json:"source"`                     // Module source
+	Type            source.SourceTypeEnum `json:"type"`                       // Registry type
+	Supported       bool                  `json:"supported"`                  // Whether we can fetch versions
+	CurrentVersions map[string]int        `json:"current_versions"`           // Version -> count of usages
+	LatestVersion   string                `json:"latest_version,omitempty"`   // Latest available version
+	TotalUsages     int                   `json:"total_usages"`               // Total module invocations
+	UpdateCount     int                   `json:"update_count"`               // Count that will be updated
+	UpcomingVersion string                `json:"upcoming_version,omitempty"` // Newest version usages are updated to
+	Targets         map[string]string     `json:"targets,omitempty"`          // Current version -> version it is updated to, for pending updates
+	Locations       []string              `json:"locations"`                  // File paths with this module
+	Definitions     []string              `json:"definitions,omitempty"`      // Where versions from locals/variables are defined
+	HoldReason      string                `json:"hold_reason,omitempty"`      // Why UpcomingVersion is older than LatestVersion
+	Warnings        []string              `json:"warnings,omitempty"`         // Compatibility warnings for UpcomingVersion
+	Impacts         []ModuleImpact        `json:"impacts,omitempty"`          // Breaking changes affecting module calls, with --impact
+	Changelog       []changelog.Entry     `json:"changelog,omitempty"`        // Releases between current and upcoming version, with --changelog
+	Usages          []UsageReport         `json:"usages,omitempty"`           // Module blocks using this source
+}
+
+// Target returns the version usages at current version ver are updated to
+// Returns an empty string when they are left alone
+func (m ModuleReport) Target(ver string) string {
+	return m.Targets[ver]
+}
+
+// UsageReport locates one module block using a source
+type UsageReport struct {
+	BlockName    string             `json:"block_name"`              // Module block name
+	File         string             `json:"file"`                    // .tf file declaring the block
+	Line         int                `json:"line"`                    // Line of the module block
+	Version      string             `json:"version"`                 // Current version
+	VersionRange *finder.Range      `json:"version_range,omitempty"` // Version value position, nil if unknown
+	Definition   string             `json:"definition,omitempty"`    // local/variable holding the version, if any
+	Staleness    *staleness.Metrics `json:"staleness,omitempty"`     // How far Version is behind the latest release
+	Group        string             `json:"group,omitempty"`         // Update group of the pending update, with grouping rules
+}
+
+// ModuleImpact lists the breaking changes of an upgrade that affect one module call
+type ModuleImpact struct {
+	BlockName        string           `json:"block_name"`                  // Module block name
+	File             string           `json:"file"`                        // .tf file declaring the block
+	Line             int              `json:"line"`                        // Line of the module block
+	FromVersion      string           `json:"from_version"`                // Current version
+	ToVersion        string           `json:"to_version"`                  // Target version
+	Findings         []impact.Finding `json:"findings,omitempty"`          // Changes the call is affected by
+	RemovedResources []string         `json:"removed_resources,omitempty"` // Resources the target version no longer declares
 }
 
 // UnsupportedSource represents a module source we can't update
 type UnsupportedSource struct {
-	Source string
-	Type   source.SourceTypeEnum
-	Count  int // Number of usages
+	Source string                `json:"source"`
+	Type   source.SourceTypeEnum `json:"type"`
+	Count  int                   `json:"count"`            // Number of usages
+	Usages []UsageReport         `json:"usages,omitempty"` // Module blocks using this source
+}
+
+// UnpinnedModule represents a registry module call without a version attribute
+type UnpinnedModule struct {
+	Source        string                `json:"source"`
+	Type          source.SourceTypeEnum `json:"type"`
+	BlockName     string                `json:"block_name"`               // Module block name
+	File          string                `json:"file"`                     // .tf file declaring the block
+	Line          int                   `json:"line"`                     // Line of the module block
+	LatestVersion string                `json:"latest_version,omitempty"` // Latest available version, empty if unknown
+}
+
+// ProviderReport represents a summary report for one provider source
+type ProviderReport struct {
+	Source             string            `json:"source"`                   // Provider source, e.g., "hashicorp/aws"
+	CurrentConstraints map[string]int    `json:"current_constraints"`      // Constraint -> count of usages
+	LatestVersion      string            `json:"latest_version,omitempty"` // Latest available version
+	TotalUsages        int               `json:"total_usages"`             // Total required_providers entries
+	UpdateCount        int               `json:"update_count"`             // Count of constraints that will be rewritten
+	Targets            map[string]string `json:"targets,omitempty"`        // Constraint -> constraint it is rewritten to, for pending rewrites
+	Locations          []string          `json:"locations"`                // File paths with this provider
+}
+
+// Target returns the constraint a current constraint is rewritten to
+// Returns an empty string when it is left alone
+func (p ProviderReport) Target(constraint string) string {
+	return p.Targets[constraint]
 }
 
 // UpdateSummary is the final report of all findings
 type UpdateSummary struct {
-	Modules            []ModuleReport
-	UnsupportedModules []UnsupportedSource
-	TotalUsages        int            // Total across all modules
-	TotalUpdated       int            // Total that would be changed
-	ByVersionChange    map[string]int // "1.0.0 → 2.0.0": count
-	SuportedCount      int            // Count of supported modules
-	UnsupportedCount   int            // Count of unsupported modules
+	Modules            []ModuleReport      `json:"modules"`
+	UnsupportedModules []UnsupportedSource `json:"unsupported_modules"`
+	UnpinnedModules    []UnpinnedModule    `json:"unpinned_modules"`
+	Providers          []ProviderReport    `json:"providers"`
+	TotalUsages        int                 `json:"total_usages"`                   // Total across all modules
+	TotalUpdated       int                 `json:"total_updated"`                  // Total that would be changed
+	ByVersionChange    map[string]int      `json:"by_version_change"`              // "1.0.0 → 2.0.0": count
+	SuportedCount      int                 `json:"supported_count"`                // Count of supported modules
+	UnsupportedCount   int                 `json:"unsupported_count"`              // Count of unsupported modules
+	Libyear            float64             `json:"libyear"`                        // Libyears across all module usages
+	LibyearByDirectory map[string]float64  `json:"libyear_by_directory,omitempty"` // Directory -> libyears of its usages
+	Registry           RegistryStats       `json:"registry"`                       // Registry lookups made for the report
+	SourceRoot         string              `json:"source_root,omitempty"`          // Directory SARIF locations are relative to, e.g., the repository root
+}
+
+// RegistryStats counts the registry lookups made while building a report
+type RegistryStats struct {
+	Requests  int `json:"requests"`   // HTTP requests sent to registries
+	CacheHits int `json:"cache_hits"` // Lookups answered from the cache
+	Errors    int `json:"errors"`     // Modules or providers whose lookup failed
 }
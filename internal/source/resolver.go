@@ -19,6 +19,25 @@ func (r *Resolver) Resolve(sourceStr string) (*Source, error) {
 		return nil, fmt.Errorf("empty source string")
 	}
 
+	// Handle local paths
+	if strings.HasPrefix(sourceStr, "./") || strings.HasPrefix(sourceStr, "../") {
+		return &Source{
+			Original:  sourceStr,
+			Type:      SourceTypeLocal,
+			Path:      sourceStr,
+			Supported: false,
+		}, nil
+	}
+
+	// Handle forced getters and URLs (git::, s3::, https://, git@...)
+	if strings.Contains(sourceStr, "::") || strings.Contains(sourceStr, "://") || strings.HasPrefix(sourceStr, "git@") {
+		return &Source{
+			Original:  sourceStr,
+			Type:      SourceTypeUnknown,
+			Supported: false,
+		}, nil
+	}
+
 	// Handle GitHub URLs
 	if strings.HasPrefix(sourceStr, "github.com/") {
 		parts := strings.Split(strings.TrimPrefix(sourceStr, "github.com/"), "//")
@@ -90,6 +109,44 @@ func (r *Resolver) parseRegistrySource(sourceStr string) (*Source, error) {
 	return source, nil
 }
 
+// ResolveProvider parses a provider source address: [host/]namespace/type
+func (r *Resolver) ResolveProvider(sourceStr string) (*Source, error) {
+	if sourceStr == "" {
+		return nil, fmt.Errorf("empty provider source string")
+	}
+
+	pathParts := strings.Split(sourceStr, "/")
+
+	var host, namespace, typeName string
+	switch len(pathParts) {
+	case 2:
+		host = "registry.terraform.io"
+		namespace = pathParts[0]
+		typeName = pathParts[1]
+	case 3:
+		host = pathParts[0]
+		namespace = pathParts[1]
+		typeName = pathParts[2]
+	default:
+		return nil, fmt.Errorf("invalid provider source format: %s", sourceStr)
+	}
+
+	src := &Source{
+		Original:  sourceStr,
+		Type:      SourceTypeCustomRegistry,
+		Host:      host,
+		Namespace: namespace,
+		Name:      typeName,
+		Supported: true,
+	}
+
+	if host == "registry.terraform.io" {
+		src.Type = SourceTypeTerraformRegistry
+	}
+
+	return src, nil
+}
+
 // String returns the canonical source string
 func (s *Source) String() string {
 	return s.Original
@@ -44,6 +44,18 @@ func TestResolverTerraformRegistry(t *testing.T) {
 			expectedHost: "github.com",
 			supported:    false,
 		},
+		{
+			name:         "Local path",
+			source:       "./modules/network/vpc",
+			expectedType: SourceTypeLocal,
+			supported:    false,
+		},
+		{
+			name:         "Git URL",
+			source:       "git::https://example.com/network.git//vpc?ref=v1.2.0",
+			expectedType: SourceTypeUnknown,
+			supported:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -114,3 +126,30 @@ func TestRegistryPath(t *testing.T) {
 		t.Errorf("RegistryPath() = %s, want %s", path, expected)
 	}
 }
+
+func TestResolveProvider(t *testing.T) {
+	resolver := NewResolver()
+
+	src, err := resolver.ResolveProvider("hashicorp/aws")
+	if err != nil {
+		t.Fatalf("ResolveProvider() error = %v", err)
+	}
+	if src.Host != "registry.terraform.io" || src.Namespace != "hashicorp" || src.Name != "aws" {
+		t.Errorf("ResolveProvider() = %s/%s/%s, want registry.terraform.io/hashicorp/aws", src.Host, src.Namespace, src.Name)
+	}
+	if src.Type != SourceTypeTerraformRegistry {
+		t.Errorf("Type = %v, want %v", src.Type, SourceTypeTerraformRegistry)
+	}
+
+	src, err = resolver.ResolveProvider("registry.example.com/acme/internal")
+	if err != nil {
+		t.Fatalf("ResolveProvider() error = %v", err)
+	}
+	if src.Host != "registry.example.com" || src.Type != SourceTypeCustomRegistry {
+		t.Errorf("ResolveProvider() host = %s type = %v", src.Host, src.Type)
+	}
+
+	if _, err := resolver.ResolveProvider("aws"); err == nil {
+		t.Error("ResolveProvider() should reject a bare provider name")
+	}
+}
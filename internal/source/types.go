@@ -9,6 +9,7 @@ const (
 	SourceTypeTerraformRegistry SourceTypeEnum = iota // registry.terraform.io
 	SourceTypeCustomRegistry                          // custom.registry.com
 	SourceTypeGitHub                                  // github.com/...
+	SourceTypeLocal                                   // ./modules/... or ../...
 	SourceTypeUnknown
 )
 
@@ -20,11 +21,18 @@ func (e SourceTypeEnum) String() string {
 		return "Custom Registry"
 	case SourceTypeGitHub:
 		return "GitHub"
+	case SourceTypeLocal:
+		return "Local Path"
 	default:
 		return "Unknown"
 	}
 }
 
+// MarshalText encodes the source type by name
+func (e SourceTypeEnum) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
 // Source represents a parsed module source with type information
 type Source struct {
 	Original  string         // Original source string as specified in terraform
@@ -0,0 +1,104 @@
+package staleness
+
+import (
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+)
+
+// daysPerYear is the average year length used for libyears
+const daysPerYear = 365.25
+
+// Metrics measures how far a module version is behind the latest release
+type Metrics struct {
+	ReleasesBehind int     `json:"releases_behind"` // Releases newer than the current version
+	MajorDelta     int     `json:"major_delta"`     // Semver distance to the latest version,
+	MinorDelta     int     `json:"minor_delta"`     // only the most significant differing
+	PatchDelta     int     `json:"patch_delta"`     // component is non-zero
+	DaysBehind     int     `json:"days_behind"`     // Days since the first newer release, 0 if dates are unknown
+	Libyear        float64 `json:"libyear"`         // Years between current and latest release dates, 0 if unknown
+}
+
+// Behind reports whether newer releases exist
+func (m Metrics) Behind() bool {
+	return m.ReleasesBehind > 0
+}
+
+// Measure computes the metrics of current against the latest version of a module
+// Publish dates are read from the registry metadata of each version
+func Measure(module *registry.Module, current, latest string, now time.Time) (Metrics, error) {
+	var metrics Metrics
+
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return metrics, err
+	}
+	latestVersion, err := semver.NewVersion(latest)
+	if err != nil {
+		return metrics, err
+	}
+	if !currentVersion.LessThan(latestVersion) {
+		return metrics, nil
+	}
+
+	switch {
+	case currentVersion.Major() != latestVersion.Major():
+		metrics.MajorDelta = int(latestVersion.Major()) - int(currentVersion.Major())
+	case currentVersion.Minor() != latestVersion.Minor():
+		metrics.MinorDelta = int(latestVersion.Minor()) - int(currentVersion.Minor())
+	default:
+		metrics.PatchDelta = int(latestVersion.Patch()) - int(currentVersion.Patch())
+	}
+
+	// The oldest newer release is the one that superseded the current version
+	var superseded time.Time
+	for _, v := range moduleVersions(module) {
+		sv, err := semver.NewVersion(v.Version)
+		if err != nil || !sv.GreaterThan(currentVersion) || sv.GreaterThan(latestVersion) {
+			continue
+		}
+		metrics.ReleasesBehind++
+
+		if published, ok := publishedAt(v); ok && (superseded.IsZero() || published.Before(superseded)) {
+			superseded = published
+		}
+	}
+
+	// Without the module's version list, latest is the only release known to be newer
+	if metrics.ReleasesBehind == 0 {
+		metrics.ReleasesBehind = 1
+	}
+
+	if !superseded.IsZero() && now.After(superseded) {
+		metrics.DaysBehind = int(now.Sub(superseded).Hours() / 24)
+	}
+
+	currentDate, currentOK := publishedAt(module.FindVersion(current))
+	latestDate, latestOK := publishedAt(module.FindVersion(latest))
+	if currentOK && latestOK && latestDate.After(currentDate) {
+		metrics.Libyear = latestDate.Sub(currentDate).Hours() / 24 / daysPerYear
+	}
+
+	return metrics, nil
+}
+
+func moduleVersions(module *registry.Module) []*registry.Version {
+	if module == nil {
+		return nil
+	}
+	return module.Versions
+}
+
+// publishedAt returns the publish date of a version from its registry metadata
+func publishedAt(v *registry.Version) (time.Time, bool) {
+	if v == nil || v.RegistryModuleInfo == nil || v.RegistryModuleInfo.PublishedAt == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, v.RegistryModuleInfo.PublishedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
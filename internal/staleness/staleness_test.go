@@ -0,0 +1,92 @@
+package staleness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/registry"
+)
+
+func testModule(published map[string]string) *registry.Module {
+	module := &registry.Module{}
+	for v, date := range published {
+		module.Versions = append(module.Versions, &registry.Version{
+			Version:            v,
+			RegistryModuleInfo: &registry.ModuleInfo{PublishedAt: date},
+		})
+	}
+	return module
+}
+
+func TestMeasure(t *testing.T) {
+	module := testModule(map[string]string{
+		"4.0.0": "2023-01-01T00:00:00Z",
+		"4.1.0": "2023-04-01T00:00:00Z",
+		"4.2.0": "2023-07-01T00:00:00Z",
+		"5.0.0": "2024-01-01T00:00:00Z",
+		"5.1.0": "2025-01-01T00:00:00Z",
+	})
+	now := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+		want    Metrics
+	}{
+		{
+			name:    "major behind",
+			current: "4.0.0",
+			latest:  "5.1.0",
+			want:    Metrics{ReleasesBehind: 4, MajorDelta: 1, DaysBehind: 651, Libyear: 2.0},
+		},
+		{
+			name:    "minor behind",
+			current: "5.0.0",
+			latest:  "5.1.0",
+			want:    Metrics{ReleasesBehind: 1, MinorDelta: 1, DaysBehind: 10, Libyear: 1.0},
+		},
+		{
+			name:    "up to date",
+			current: "5.1.0",
+			latest:  "5.1.0",
+			want:    Metrics{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Measure(module, tt.current, tt.latest, now)
+			if err != nil {
+				t.Fatalf("Measure returned error: %v", err)
+			}
+
+			// Libyears depend on leap days, compare with a tolerance
+			if diff := got.Libyear - tt.want.Libyear; diff > 0.01 || diff < -0.01 {
+				t.Errorf("Libyear = %.3f, want %.3f", got.Libyear, tt.want.Libyear)
+			}
+			got.Libyear, tt.want.Libyear = 0, 0
+			if got != tt.want {
+				t.Errorf("Measure = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureWithoutDates(t *testing.T) {
+	got, err := Measure(nil, "1.0.0", "1.0.3", time.Now())
+	if err != nil {
+		t.Fatalf("Measure returned error: %v", err)
+	}
+
+	want := Metrics{ReleasesBehind: 1, PatchDelta: 3}
+	if got != want {
+		t.Errorf("Measure = %+v, want %+v", got, want)
+	}
+}
+
+func TestMeasureInvalidVersion(t *testing.T) {
+	if _, err := Measure(nil, "main", "1.0.0", time.Now()); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
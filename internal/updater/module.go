@@ -0,0 +1,44 @@
+package updater
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// ReplaceModuleVersion replaces the literal version of module block blockName
+// when it currently holds oldVersion, preserving surrounding formatting
+// Returns the new content and whether a replacement happened
+func ReplaceModuleVersion(content []byte, filename, blockName, oldVersion, newVersion string) ([]byte, bool, error) {
+	file, diags := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("failed to parse %s: %s", filename, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported syntax in %s", filename)
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != "module" || len(block.Labels) != 1 || block.Labels[0] != blockName {
+			continue
+		}
+		attr, ok := block.Body.Attributes["version"]
+		if !ok || strings.TrimSpace(stringValue(attr.Expr)) != oldVersion {
+			return content, false, nil
+		}
+
+		rng := attr.Expr.Range()
+		var updated []byte
+		updated = append(updated, content[:rng.Start.Byte]...)
+		updated = append(updated, strconv.Quote(newVersion)...)
+		updated = append(updated, content[rng.End.Byte:]...)
+		return updated, true, nil
+	}
+
+	return content, false, nil
+}
@@ -0,0 +1,78 @@
+package updater
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// InsertVersion adds `version = "<version>"` on the line following the source
+// attribute of module block blockName, matching its indentation and alignment
+// Returns the new content and whether the block was changed
+func InsertVersion(content []byte, filename, blockName, version string) ([]byte, bool, error) {
+	file, diags := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("failed to parse %s: %s", filename, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported syntax in %s", filename)
+	}
+
+	var block *hclsyntax.Block
+	for _, b := range body.Blocks {
+		if b.Type == "module" && len(b.Labels) == 1 && b.Labels[0] == blockName {
+			block = b
+			break
+		}
+	}
+	if block == nil {
+		return nil, false, fmt.Errorf("module %q not found in %s", blockName, filename)
+	}
+
+	if _, hasVersion := block.Body.Attributes["version"]; hasVersion {
+		return content, false, nil
+	}
+
+	sourceAttr, ok := block.Body.Attributes["source"]
+	if !ok {
+		return nil, false, fmt.Errorf("module %q in %s has no source", blockName, filename)
+	}
+
+	if sourceAttr.SrcRange.Start.Line == block.OpenBraceRange.Start.Line ||
+		sourceAttr.SrcRange.End.Line == block.CloseBraceRange.Start.Line {
+		return nil, false, fmt.Errorf("module %q in %s is a single-line block", blockName, filename)
+	}
+
+	// Locate the source line to copy its indentation and '=' alignment
+	lineStart := bytes.LastIndexByte(content[:sourceAttr.SrcRange.Start.Byte], '\n') + 1
+	lineEnd := sourceAttr.SrcRange.End.Byte
+	if idx := bytes.IndexByte(content[lineEnd:], '\n'); idx >= 0 {
+		lineEnd += idx
+	} else {
+		lineEnd = len(content)
+	}
+
+	indent := string(content[lineStart:sourceAttr.SrcRange.Start.Byte])
+	keyWidth := sourceAttr.EqualsRange.Start.Byte - sourceAttr.SrcRange.Start.Byte
+	key := "version"
+	if keyWidth > len(key) {
+		key += strings.Repeat(" ", keyWidth-len(key))
+	} else {
+		key += " "
+	}
+
+	line := fmt.Sprintf("\n%s%s= %s", indent, key, strconv.Quote(version))
+
+	var updated []byte
+	updated = append(updated, content[:lineEnd]...)
+	updated = append(updated, line...)
+	updated = append(updated, content[lineEnd:]...)
+
+	return updated, true, nil
+}
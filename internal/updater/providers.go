@@ -0,0 +1,90 @@
+package updater
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ReplaceProviderConstraint replaces the version constraint of provider name inside
+// terraform { required_providers { ... } } blocks, preserving surrounding formatting
+// Returns the new content and whether a replacement happened
+func ReplaceProviderConstraint(content []byte, filename, name, oldConstraint, newConstraint string) ([]byte, bool, error) {
+	file, diags := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("failed to parse %s: %s", filename, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported syntax in %s", filename)
+	}
+
+	var ranges []hcl.Range
+	for _, block := range body.Blocks {
+		if block.Type != "terraform" {
+			continue
+		}
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "required_providers" {
+				continue
+			}
+			attr, ok := inner.Body.Attributes[name]
+			if !ok {
+				continue
+			}
+			if expr := providerVersionExpr(attr.Expr); expr != nil && stringValue(expr) == oldConstraint {
+				ranges = append(ranges, expr.Range())
+			}
+		}
+	}
+
+	if len(ranges) == 0 {
+		return content, false, nil
+	}
+
+	// Replace in reverse order to preserve byte offsets
+	updated := append([]byte{}, content...)
+	for i := len(ranges) - 1; i >= 0; i-- {
+		rng := ranges[i]
+		var next []byte
+		next = append(next, updated[:rng.Start.Byte]...)
+		next = append(next, strconv.Quote(newConstraint)...)
+		next = append(next, updated[rng.End.Byte:]...)
+		updated = next
+	}
+
+	return updated, true, nil
+}
+
+// providerVersionExpr returns the expression holding the version constraint of a
+// required_providers entry, either the legacy string form or the object's version key
+func providerVersionExpr(expr hclsyntax.Expression) hclsyntax.Expression {
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return expr
+	}
+
+	for _, item := range object.Items {
+		if hcl.ExprAsKeyword(item.KeyExpr) == "version" {
+			return item.ValueExpr
+		}
+	}
+	return nil
+}
+
+// stringValue returns the value of a literal string expression, or "" otherwise
+func stringValue(expr hclsyntax.Expression) string {
+	if len(expr.Variables()) > 0 {
+		return ""
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return ""
+	}
+	return value.AsString()
+}
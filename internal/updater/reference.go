@@ -0,0 +1,85 @@
+package updater
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ReplaceReference replaces the literal value of the attribute defining ref
+// Only the value expression is replaced; surrounding formatting is preserved
+// Returns the new content and whether a replacement happened
+func ReplaceReference(content []byte, ref *finder.VersionRef, oldVersion, newVersion string) ([]byte, bool, error) {
+	rng, found, err := locateReference(content, ref, oldVersion)
+	if err != nil || !found {
+		return content, false, err
+	}
+
+	var updated []byte
+	updated = append(updated, content[:rng.Start.Byte]...)
+	updated = append(updated, strconv.Quote(newVersion)...)
+	updated = append(updated, content[rng.End.Byte:]...)
+
+	return updated, true, nil
+}
+
+// locateReference returns the range of the value expression defining ref
+// when it currently holds oldVersion
+func locateReference(content []byte, ref *finder.VersionRef, oldVersion string) (hcl.Range, bool, error) {
+	file, diags := hclsyntax.ParseConfig(content, ref.FilePath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return hcl.Range{}, false, fmt.Errorf("failed to parse %s: %s", ref.FilePath, diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return hcl.Range{}, false, fmt.Errorf("unsupported syntax in %s", ref.FilePath)
+	}
+
+	attr := findDefinition(body, ref)
+	if attr == nil || len(attr.Expr.Variables()) > 0 {
+		return hcl.Range{}, false, nil
+	}
+
+	value, valueDiags := attr.Expr.Value(nil)
+	if valueDiags.HasErrors() || !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return hcl.Range{}, false, nil
+	}
+	if strings.TrimSpace(value.AsString()) != oldVersion {
+		return hcl.Range{}, false, nil
+	}
+
+	return attr.Expr.Range(), true, nil
+}
+
+// findDefinition locates the attribute holding the value of a local or variable
+func findDefinition(body *hclsyntax.Body, ref *finder.VersionRef) *hclsyntax.Attribute {
+	// Variable files assign values as top-level attributes
+	if filepath.Ext(ref.FilePath) == ".tfvars" {
+		if ref.Kind != finder.RefKindVariable {
+			return nil
+		}
+		return body.Attributes[ref.Name]
+	}
+
+	for _, block := range body.Blocks {
+		switch {
+		case ref.Kind == finder.RefKindLocal && block.Type == "locals":
+			if attr, ok := block.Body.Attributes[ref.Name]; ok {
+				return attr
+			}
+		case ref.Kind == finder.RefKindVariable && block.Type == "variable":
+			if len(block.Labels) == 1 && block.Labels[0] == ref.Name {
+				return block.Body.Attributes["default"]
+			}
+		}
+	}
+
+	return nil
+}
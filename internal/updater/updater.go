@@ -2,13 +2,10 @@ package updater
 
 import (
 	"fmt"
-	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
-
-	"github.com/vdesjardins/terraform-module-versions/internal/report"
 )
 
 // FileUpdater handles updating .tf files with new module versions
@@ -57,58 +54,6 @@ func (u *FileUpdater) Update(filePath, source, oldVersion, newVersion string) (i
 	return countBefore, nil
 }
 
-// WriteDiff outputs a unified diff for the requested update.
-func (u *FileUpdater) WriteDiff(writer io.Writer, dirPath, source, oldVersion, newVersion string) error {
-	if writer == nil {
-		writer = os.Stdout
-	}
-
-	return u.walkTerraformFiles(dirPath, func(path string) error {
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("failed to read file %s: %w", path, err)
-		}
-
-		contentStr := string(content)
-		if countOccurrences(contentStr, source, oldVersion) == 0 {
-			return nil
-		}
-
-		replacer := NewSimpleVersionReplacer(source, oldVersion, newVersion)
-		updated, err := replacer.Replace(contentStr)
-		if err != nil {
-			return fmt.Errorf("failed to replace version: %w", err)
-		}
-		if updated == contentStr {
-			return nil
-		}
-
-		diffOutput, err := report.FormatUnifiedDiff(path, contentStr, updated)
-		if err != nil {
-			return err
-		}
-		if diffOutput == "" {
-			return nil
-		}
-
-		formatted, err := report.RenderOutput(diffOutput)
-		if err != nil {
-			return err
-		}
-
-		_, err = io.WriteString(writer, formatted)
-		return err
-	})
-}
-
-// WriteDiffWithTool outputs diff through an external tool.
-func (u *FileUpdater) WriteDiffWithTool(writer io.Writer, dirPath, source, oldVersion, newVersion, tool string) error {
-	if err := report.SetOutput(tool); err != nil {
-		return err
-	}
-	return u.WriteDiff(writer, dirPath, source, oldVersion, newVersion)
-}
-
 // Count counts occurrences of a module source+version in a file without updating it
 // Returns the number of matches found
 func (u *FileUpdater) Count(filePath, source, oldVersion string) (int, error) {
@@ -121,29 +66,6 @@ func (u *FileUpdater) Count(filePath, source, oldVersion string) (int, error) {
 	return countOccurrences(contentStr, source, oldVersion), nil
 }
 
-// UpdateDirectory updates all .tf files in a directory tree
-// Returns a map of file paths to number of replacements made
-func (u *FileUpdater) UpdateDirectory(dirPath, source, oldVersion, newVersion string) (map[string]int, error) {
-	results := make(map[string]int)
-
-	err := u.walkTerraformFiles(dirPath, func(path string) error {
-		count, err := u.Update(path, source, oldVersion, newVersion)
-		if err != nil {
-			// Log error but continue processing other files
-			fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", path, err)
-			return nil
-		}
-
-		if count > 0 {
-			results[path] = count
-		}
-
-		return nil
-	})
-
-	return results, err
-}
-
 // CountDirectory counts matches in all .tf files in a directory tree without updating
 // Returns a map of file paths to number of matches found
 func (u *FileUpdater) CountDirectory(dirPath, source, oldVersion string) (map[string]int, error) {
@@ -186,6 +108,11 @@ func (u *FileUpdater) walkTerraformFiles(dirPath string, handler func(path strin
 
 // writeAtomically writes to a file atomically using temp file + rename
 func (u *FileUpdater) writeAtomically(filePath string, content []byte) error {
+	return WriteFile(filePath, content)
+}
+
+// WriteFile replaces the content of a file atomically using temp file + rename
+func WriteFile(filePath string, content []byte) error {
 	dir := filepath.Dir(filePath)
 	tempFile, err := os.CreateTemp(dir, ".tf-tmp-")
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/vdesjardins/terraform-module-versions/internal/finder"
 )
 
 func TestReplacer(t *testing.T) {
@@ -159,6 +161,119 @@ func TestFileUpdaterCountDirectory(t *testing.T) {
 	}
 }
 
+func TestReplaceReference(t *testing.T) {
+	locals := `locals {
+  vpc_version = "5.1.0" # pinned
+  other       = "5.1.0"
+}
+`
+	localRef := &finder.VersionRef{Kind: finder.RefKindLocal, Name: "vpc_version", FilePath: "locals.tf", Line: 2}
+	updated, changed, err := ReplaceReference([]byte(locals), localRef, "5.1.0", "5.2.0")
+	if err != nil || !changed {
+		t.Fatalf("ReplaceReference() = %v, %v; want change", changed, err)
+	}
+
+	want := `locals {
+  vpc_version = "5.2.0" # pinned
+  other       = "5.1.0"
+}
+`
+	if string(updated) != want {
+		t.Errorf("locals file = %q, want %q", updated, want)
+	}
+
+	// A second run finds nothing left to change
+	_, changed, err = ReplaceReference(updated, localRef, "5.1.0", "5.2.0")
+	if err != nil || changed {
+		t.Errorf("ReplaceReference() second run = %v, %v; want no change", changed, err)
+	}
+
+	varRef := &finder.VersionRef{Kind: finder.RefKindVariable, Name: "eks_module_version", FilePath: "terraform.tfvars", Line: 1}
+	updated, changed, err = ReplaceReference([]byte("eks_module_version = \"19.0.0\"\n"), varRef, "19.0.0", "19.5.1")
+	if err != nil || !changed {
+		t.Fatalf("ReplaceReference() = %v, %v; want change", changed, err)
+	}
+	if string(updated) != "eks_module_version = \"19.5.1\"\n" {
+		t.Errorf("tfvars file = %q", updated)
+	}
+}
+
+func TestInsertVersion(t *testing.T) {
+	content := `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  name    = "main"
+}
+
+module "pinned" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+
+module "short" {
+  source = "terraform-aws-modules/eks/aws" # no version
+}
+`
+
+	updated, changed, err := InsertVersion([]byte(content), "main.tf", "vpc", "5.1.2")
+	if err != nil || !changed {
+		t.Fatalf("InsertVersion() = %v, %v; want change", changed, err)
+	}
+	if !contains(string(updated), "  source  = \"terraform-aws-modules/vpc/aws\"\n  version = \"5.1.2\"\n  name    = \"main\"") {
+		t.Errorf("InsertVersion() did not align version after source:\n%s", updated)
+	}
+
+	updated, changed, err = InsertVersion(updated, "main.tf", "short", "~> 19.0")
+	if err != nil || !changed {
+		t.Fatalf("InsertVersion() = %v, %v; want change", changed, err)
+	}
+	if !contains(string(updated), "# no version\n  version = \"~> 19.0\"\n}") {
+		t.Errorf("InsertVersion() did not insert after trailing comment:\n%s", updated)
+	}
+
+	_, changed, err = InsertVersion(updated, "main.tf", "pinned", "5.1.2")
+	if err != nil || changed {
+		t.Errorf("InsertVersion() on pinned block = %v, %v; want no change", changed, err)
+	}
+
+	if _, _, err := InsertVersion(updated, "main.tf", "missing", "5.1.2"); err == nil {
+		t.Error("InsertVersion() should fail for unknown block")
+	}
+}
+
+func TestReplaceProviderConstraint(t *testing.T) {
+	content := `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0" # keep
+    }
+    random = "~> 3.1"
+  }
+}
+`
+
+	updated, changed, err := ReplaceProviderConstraint([]byte(content), "versions.tf", "aws", "~> 5.0", "~> 5.31")
+	if err != nil || !changed {
+		t.Fatalf("ReplaceProviderConstraint() = %v, %v; want change", changed, err)
+	}
+	if !contains(string(updated), `version = "~> 5.31" # keep`) {
+		t.Errorf("ReplaceProviderConstraint() did not rewrite aws:\n%s", updated)
+	}
+
+	updated, changed, err = ReplaceProviderConstraint(updated, "versions.tf", "random", "~> 3.1", "~> 3.6")
+	if err != nil || !changed {
+		t.Fatalf("ReplaceProviderConstraint() = %v, %v; want change", changed, err)
+	}
+	if !contains(string(updated), `random = "~> 3.6"`) {
+		t.Errorf("ReplaceProviderConstraint() did not rewrite legacy form:\n%s", updated)
+	}
+
+	_, changed, err = ReplaceProviderConstraint(updated, "versions.tf", "aws", "~> 5.0", "~> 6.0")
+	if err != nil || changed {
+		t.Errorf("ReplaceProviderConstraint() with stale constraint = %v, %v; want no change", changed, err)
+	}
+}
+
 func TestIsTerraformFile(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -0,0 +1,189 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/vdesjardins/terraform-module-versions/internal/updater"
+)
+
+// ParseErrors parses files with HCL and re-loads their directory with tfconfig
+// Returns the error of each file that fails to parse or that tfconfig reports
+func ParseErrors(dir string, files []string) map[string]error {
+	errs := make(map[string]error)
+
+	parser := hclparse.NewParser()
+	for _, file := range files {
+		var diagErr error
+		if strings.HasSuffix(file, ".json") {
+			_, diags := parser.ParseJSONFile(file)
+			if diags.HasErrors() {
+				diagErr = diags
+			}
+		} else {
+			_, diags := parser.ParseHCLFile(file)
+			if diags.HasErrors() {
+				diagErr = diags
+			}
+		}
+		if diagErr != nil {
+			errs[file] = diagErr
+		}
+	}
+
+	_, diags := tfconfig.LoadModule(dir)
+	for _, diag := range diags {
+		if diag.Severity != tfconfig.DiagError || diag.Pos == nil {
+			continue
+		}
+		for _, file := range files {
+			if _, exists := errs[file]; !exists && samePath(file, diag.Pos.Filename) {
+				errs[file] = fmt.Errorf("%s:%d: %s; %s", diag.Pos.Filename, diag.Pos.Line, diag.Summary, diag.Detail)
+			}
+		}
+	}
+
+	return errs
+}
+
+// RunHook runs a shell command in dir and returns its combined output
+// The command is killed after timeout when timeout is positive
+func RunHook(ctx context.Context, dir, command string, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.WaitDelay = time.Second // Children of the shell may keep the output open once it is killed
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return out.String(), fmt.Errorf("%q timed out after %s", command, timeout)
+		}
+		return out.String(), fmt.Errorf("%q failed: %w", command, err)
+	}
+	return out.String(), nil
+}
+
+// snapshotPatterns match the files of a directory that hooks may change, e.g.
+// terraform fmt and terraform init -upgrade
+var snapshotPatterns = []string{"*.tf", "*.tf.json", ".terraform.lock.hcl"}
+
+// Snapshot holds the Terraform files and lock file of a directory, so that the
+// files a hook creates, modifies or deletes can be found and restored
+type Snapshot struct {
+	dir   string
+	files map[string][]byte // Content of each file, by absolute path
+}
+
+// TakeSnapshot reads the Terraform files and lock file of a directory
+func TakeSnapshot(dir string) (*Snapshot, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	paths, err := snapshotPaths(abs)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		files[path] = content
+	}
+	return &Snapshot{dir: abs, files: files}, nil
+}
+
+// Changes returns the absolute paths of the files created, modified or deleted
+// since the snapshot was taken, sorted
+func (s *Snapshot) Changes() ([]string, error) {
+	paths, err := snapshotPaths(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	current := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		current[path] = true
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		if original, existed := s.files[path]; !existed || !bytes.Equal(original, content) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range s.files {
+		if !current[path] {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Original returns the content of a file when the snapshot was taken, false when
+// the file did not exist
+func (s *Snapshot) Original(path string) ([]byte, bool) {
+	content, existed := s.files[path]
+	return content, existed
+}
+
+// Restore puts a file back as it was when the snapshot was taken, removing it
+// when it did not exist
+func (s *Snapshot) Restore(path string) error {
+	content, existed := s.files[path]
+	if !existed {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := updater.WriteFile(path, content); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", path, err)
+	}
+	return nil
+}
+
+// snapshotPaths returns the files of dir matching snapshotPatterns
+func snapshotPaths(dir string) ([]string, error) {
+	var paths []string
+	for _, pattern := range snapshotPatterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+				paths = append(paths, match)
+			}
+		}
+	}
+	return paths, nil
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,131 @@
+package validate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-validate-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	valid := filepath.Join(tmpDir, "main.tf")
+	broken := filepath.Join(tmpDir, "broken.tf")
+	files := map[string]string{
+		valid: `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.0"
+}
+`,
+		broken: `module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "20.0.0
+}
+`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	errs := ParseErrors(tmpDir, []string{valid, broken})
+	if _, ok := errs[valid]; ok {
+		t.Errorf("ParseErrors() reported %s: %v", valid, errs[valid])
+	}
+	if _, ok := errs[broken]; !ok {
+		t.Errorf("ParseErrors() did not report %s", broken)
+	}
+}
+
+func TestRunHook(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-hook-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	out, err := RunHook(context.Background(), tmpDir, "pwd", 0)
+	if err != nil {
+		t.Fatalf("RunHook() error = %v", err)
+	}
+	if resolved, _ := filepath.EvalSymlinks(tmpDir); !strings.Contains(out, resolved) && !strings.Contains(out, tmpDir) {
+		t.Errorf("RunHook() ran in %q, want %s", strings.TrimSpace(out), tmpDir)
+	}
+
+	out, err = RunHook(context.Background(), tmpDir, "echo invalid >&2; exit 2", 0)
+	if err == nil || !strings.Contains(out, "invalid") {
+		t.Errorf("RunHook() = %q, %v, want a failure with its output", out, err)
+	}
+
+	if _, err := RunHook(context.Background(), tmpDir, "sleep 5", 50*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("RunHook() error = %v, want a timeout", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-snapshot-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if tmpDir, err = filepath.Abs(tmpDir); err != nil {
+		t.Fatalf("failed to resolve temp dir: %v", err)
+	}
+
+	for name, content := range map[string]string{"main.tf": "main", "other.tf": "other", "vars.tf": "vars", "notes.txt": "notes"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	snapshot, err := TakeSnapshot(tmpDir)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error = %v", err)
+	}
+
+	// What terraform fmt and terraform init could do, and a file hooks are not tracked for
+	hook := "echo formatted > other.tf; rm vars.tf; echo lock > .terraform.lock.hcl; echo changed > notes.txt"
+	if _, err := RunHook(context.Background(), tmpDir, hook, 0); err != nil {
+		t.Fatalf("RunHook() error = %v", err)
+	}
+
+	changed, err := snapshot.Changes()
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(tmpDir, ".terraform.lock.hcl"),
+		filepath.Join(tmpDir, "other.tf"),
+		filepath.Join(tmpDir, "vars.tf"),
+	}
+	if strings.Join(changed, ",") != strings.Join(want, ",") {
+		t.Fatalf("Changes() = %v, want %v", changed, want)
+	}
+
+	if original, existed := snapshot.Original(filepath.Join(tmpDir, "other.tf")); !existed || string(original) != "other" {
+		t.Errorf("Original(other.tf) = %q, %v", original, existed)
+	}
+	if _, existed := snapshot.Original(filepath.Join(tmpDir, ".terraform.lock.hcl")); existed {
+		t.Errorf("Original(.terraform.lock.hcl) existed before the hook")
+	}
+
+	for _, path := range changed {
+		if err := snapshot.Restore(path); err != nil {
+			t.Fatalf("Restore(%s) error = %v", path, err)
+		}
+	}
+	if changed, err := snapshot.Changes(); err != nil || len(changed) != 0 {
+		t.Errorf("Changes() after Restore() = %v, %v, want none", changed, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".terraform.lock.hcl")); !os.IsNotExist(err) {
+		t.Errorf("Restore() kept the created lock file")
+	}
+}
@@ -86,3 +86,38 @@ func GetLatestVersion(versions []string) (string, error) {
 
 	return sorted[0], nil
 }
+
+// ChangeType classifies the semver distance between two versions
+type ChangeType string
+
+const (
+	ChangeMajor ChangeType = "major"
+	ChangeMinor ChangeType = "minor"
+	ChangePatch ChangeType = "patch"
+	ChangeNone  ChangeType = "none"
+)
+
+// ClassifyChange returns the kind of change from v1 to v2
+// Versions differing only by pre-release are reported as a patch change
+func ClassifyChange(v1, v2 string) (ChangeType, error) {
+	sv1, err := semver.NewVersion(v1)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %s: %w", v1, err)
+	}
+
+	sv2, err := semver.NewVersion(v2)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %s: %w", v2, err)
+	}
+
+	switch {
+	case sv1.Major() != sv2.Major():
+		return ChangeMajor, nil
+	case sv1.Minor() != sv2.Minor():
+		return ChangeMinor, nil
+	case sv1.Equal(sv2):
+		return ChangeNone, nil
+	default:
+		return ChangePatch, nil
+	}
+}
@@ -105,3 +105,33 @@ func TestGetLatestVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyChange(t *testing.T) {
+	tests := []struct {
+		name      string
+		v1        string
+		v2        string
+		expected  ChangeType
+		wantError bool
+	}{
+		{"major", "4.2.0", "5.0.0", ChangeMajor, false},
+		{"minor", "5.0.0", "5.1.3", ChangeMinor, false},
+		{"patch", "5.1.0", "5.1.3", ChangePatch, false},
+		{"pre-release", "5.1.0-rc1", "5.1.0", ChangePatch, false},
+		{"same", "5.1.0", "v5.1.0", ChangeNone, false},
+		{"invalid", "latest", "5.1.0", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ClassifyChange(tt.v1, tt.v2)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ClassifyChange() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("ClassifyChange() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
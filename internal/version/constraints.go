@@ -93,6 +93,33 @@ func ParseConstraints(expr string) (Constraints, error) {
 	return constraints, nil
 }
 
+// ParseTerraformConstraints parses a comma-separated constraint string as Terraform
+// does, where a version without an operator means "=".
+//
+// Example:
+//   - "1.5.7" is parsed as "= 1.5.7"
+//   - ">= 1.3, 1.5.7" is parsed as ">= 1.3, = 1.5.7"
+func ParseTerraformConstraints(expr string) (Constraints, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty constraints expression")
+	}
+
+	var constraints Constraints
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if _, err := semver.NewVersion(part); err == nil {
+			part = "= " + part
+		}
+		constraint, err := ParseConstraint(part)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints, nil
+}
+
 // Matches checks if a version satisfies a single constraint.
 //
 // Examples:
@@ -216,3 +243,140 @@ func (c Constraints) String() string {
 	}
 	return strings.Join(parts, ", ")
 }
+
+// constraintTermPattern matches one term of a Terraform version constraint string,
+// capturing the optional operator and the version as written
+var constraintTermPattern = regexp.MustCompile(`(!=|>=|<=|~>|=|>|<)?\s*(v?\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.-]+)?)`)
+
+// ConstraintBase returns the version a constraint string is anchored on: the highest
+// lower bound among its =, >=, > and ~> terms. A bare version counts as "=".
+//
+// Examples:
+//   - "~> 5.0" returns "5.0.0"
+//   - ">= 4.2, < 6.0" returns "4.2.0"
+func ConstraintBase(expr string) (string, error) {
+	term, err := baseTerm(expr)
+	if err != nil {
+		return "", err
+	}
+
+	v, err := semver.NewVersion(expr[term[4]:term[5]])
+	if err != nil {
+		return "", fmt.Errorf("invalid version in constraint %q: %w", expr, err)
+	}
+	return v.String(), nil
+}
+
+// ConstraintBounds returns the terms of a constraint string other than its base term,
+// e.g. the upper bound of ">= 4.2, < 6.0". A bare version counts as "=".
+// Versions a constraint is bumped to must satisfy them.
+func ConstraintBounds(expr string) (Constraints, error) {
+	base, err := baseTerm(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var bounds Constraints
+	for _, term := range constraintTermPattern.FindAllStringSubmatchIndex(expr, -1) {
+		if term[0] == base[0] {
+			continue
+		}
+		constraint, err := parseTerm(expr, term)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, constraint)
+	}
+	return bounds, nil
+}
+
+// BumpConstraint rewrites the base term of a constraint string to newVersion,
+// keeping its operator, spacing, precision and all other terms.
+// Returns an error when the rewritten constraint would not allow newVersion,
+// e.g. when another term is an upper bound below it.
+//
+// Examples:
+//   - BumpConstraint("~> 5.0", "6.2.1") returns "~> 6.2"
+//   - BumpConstraint(">= 4.0.0, != 4.1.0", "6.2.1") returns ">= 6.2.1, != 4.1.0"
+//   - BumpConstraint(">= 4.2, < 6.0", "6.2.1") returns an error
+func BumpConstraint(expr, newVersion string) (string, error) {
+	term, err := baseTerm(expr)
+	if err != nil {
+		return "", err
+	}
+
+	v, err := semver.NewVersion(newVersion)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %q: %w", newVersion, err)
+	}
+
+	// Keep the precision the constraint was written with
+	written := expr[term[4]:term[5]]
+	var replacement string
+	switch strings.Count(strings.SplitN(written, "-", 2)[0], ".") {
+	case 0:
+		replacement = fmt.Sprintf("%d", v.Major())
+	case 1:
+		replacement = fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+	default:
+		replacement = v.String()
+	}
+	if strings.HasPrefix(written, "v") {
+		replacement = "v" + replacement
+	}
+
+	bumped := expr[:term[4]] + replacement + expr[term[5]:]
+	for _, t := range constraintTermPattern.FindAllStringSubmatchIndex(bumped, -1) {
+		constraint, err := parseTerm(bumped, t)
+		if err != nil {
+			return "", err
+		}
+		if !constraint.Matches(v) {
+			return "", fmt.Errorf("%q would exclude %s", bumped, newVersion)
+		}
+	}
+	return bumped, nil
+}
+
+// parseTerm parses one term matched by constraintTermPattern, a bare version counting as "="
+func parseTerm(expr string, term []int) (*Constraint, error) {
+	operator := "="
+	if term[2] >= 0 {
+		operator = expr[term[2]:term[3]]
+	}
+	return ParseConstraint(operator + " " + expr[term[4]:term[5]])
+}
+
+// baseTerm returns the submatch indexes of the term ConstraintBase anchors on
+func baseTerm(expr string) ([]int, error) {
+	terms := constraintTermPattern.FindAllStringSubmatchIndex(expr, -1)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("invalid constraint expression: %q", expr)
+	}
+
+	var base []int
+	var baseVersion *semver.Version
+	for _, term := range terms {
+		operator := ""
+		if term[2] >= 0 {
+			operator = expr[term[2]:term[3]]
+		}
+		if operator != "" && operator != "=" && operator != ">=" && operator != ">" && operator != "~>" {
+			continue
+		}
+
+		v, err := semver.NewVersion(expr[term[4]:term[5]])
+		if err != nil {
+			continue
+		}
+		if baseVersion == nil || v.GreaterThan(baseVersion) {
+			base = term
+			baseVersion = v
+		}
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("constraint %q has no lower bound", expr)
+	}
+	return base, nil
+}
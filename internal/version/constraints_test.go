@@ -180,6 +180,32 @@ func TestConstraintMatches(t *testing.T) {
 	}
 }
 
+func TestParseTerraformConstraints(t *testing.T) {
+	tests := []struct {
+		expr       string
+		wantString string
+		wantErr    bool
+	}{
+		{"1.5.7", "= 1.5.7", false},
+		{" >= 1.3,  1.5.7 ", ">= 1.3.0, = 1.5.7", false},
+		{"~> 1.5.0", "~> 1.5.0", false},
+		{"", "", true},
+		{">= 1.x", "", true},
+		{"latest", "", true},
+	}
+
+	for _, tt := range tests {
+		constraints, err := ParseTerraformConstraints(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTerraformConstraints(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if err == nil && constraints.String() != tt.wantString {
+			t.Errorf("ParseTerraformConstraints(%q) = %q, want %q", tt.expr, constraints.String(), tt.wantString)
+		}
+	}
+}
+
 func TestParseConstraints(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -560,3 +586,104 @@ func TestConstraintStringWithNilVersion(t *testing.T) {
 		t.Errorf("Constraint.String() with nil version = %q, want <nil>", got)
 	}
 }
+
+func TestConstraintBase(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{"~> 5.0", "5.0.0", false},
+		{">= 4.2, < 6.0", "4.2.0", false},
+		{"3.1.0", "3.1.0", false},
+		{">= 3.0, >= 3.5.1", "3.5.1", false},
+		{"< 6.0", "", true},
+		{"latest", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ConstraintBase(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConstraintBase(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConstraintBase(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpConstraint(t *testing.T) {
+	tests := []struct {
+		expr       string
+		newVersion string
+		want       string
+	}{
+		{"~> 5.0", "6.2.1", "~> 6.2"},
+		{"~>5", "6.2.1", "~>6"},
+		{">= 4.0.0, != 4.1.0", "6.2.1", ">= 6.2.1, != 4.1.0"},
+		{"= 3.1.0", "3.2.0", "= 3.2.0"},
+		{"3.1.0", "3.2.0", "3.2.0"},
+		{">= 4.2, < 6.0", "5.9.1", ">= 5.9, < 6.0"},
+		{">= 3.0, >= 3.5.1", "4.0.0", ">= 3.0, >= 4.0.0"},
+		{"~> 5.0, != 5.3.0", "5.4.1", "~> 5.4, != 5.3.0"},
+		{"< 7.0, >= 5.0", "6.1.0", "< 7.0, >= 6.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := BumpConstraint(tt.expr, tt.newVersion)
+			if err != nil {
+				t.Fatalf("BumpConstraint(%q) error = %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("BumpConstraint(%q, %q) = %q, want %q", tt.expr, tt.newVersion, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpConstraintExcludesTarget(t *testing.T) {
+	tests := []struct {
+		expr       string
+		newVersion string
+	}{
+		{">= 4.2, < 6.0", "6.2.1"},
+		{">= 4.0, <= 5.9.0", "6.0.0"},
+		{">= 4.0.0, != 6.2.1", "6.2.1"},
+		{"> 4.0.0", "6.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got, err := BumpConstraint(tt.expr, tt.newVersion); err == nil {
+				t.Errorf("BumpConstraint(%q, %q) = %q, want an error", tt.expr, tt.newVersion, got)
+			}
+		})
+	}
+}
+
+func TestConstraintBounds(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{">= 4.2, < 6.0", "< 6.0.0"},
+		{"~> 5.0, != 5.3.0", "!= 5.3.0"},
+		{">= 3.0, >= 3.5.1", ">= 3.0.0"},
+		{"~> 5.0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			bounds, err := ConstraintBounds(tt.expr)
+			if err != nil {
+				t.Fatalf("ConstraintBounds(%q) error = %v", tt.expr, err)
+			}
+			if got := bounds.String(); got != tt.want {
+				t.Errorf("ConstraintBounds(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
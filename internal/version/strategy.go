@@ -14,22 +14,73 @@ const (
 	StrategyLatest Strategy = "latest"
 )
 
+// Filter reports whether a candidate version may be selected.
+// When it may not, reason explains why (e.g. "requires aws >= 5.0").
+type Filter func(version string) (ok bool, reason string)
+
+// Exclusion records a candidate version rejected by a Filter
+type Exclusion struct {
+	Version string
+	Reason  string
+}
+
+// RequiredVersionFilter returns a Filter rejecting candidates whose Terraform core
+// constraints do not allow terraformVersion.
+// requiredCore maps candidate versions to their required_version constraints;
+// candidates without known constraints are accepted, and candidates whose
+// constraints cannot be parsed are rejected, as they cannot be checked.
+func RequiredVersionFilter(terraformVersion string, requiredCore map[string][]string) (Filter, error) {
+	current, err := semver.NewVersion(terraformVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid terraform version %q: %w", terraformVersion, err)
+	}
+
+	return func(version string) (bool, string) {
+		for _, expr := range requiredCore[version] {
+			constraints, err := ParseTerraformConstraints(expr)
+			if err != nil {
+				return false, fmt.Sprintf("has an invalid terraform constraint %q", expr)
+			}
+			if !constraints.Matches(current) {
+				return false, fmt.Sprintf("requires terraform %s", expr)
+			}
+		}
+		return true, ""
+	}, nil
+}
+
 // SelectVersion chooses the appropriate version from available versions
 // based on the strategy, current version, and optional constraints.
 // If constraints are provided, only versions satisfying all constraints are considered.
+// If filters are provided, versions rejected by any filter are excluded.
 //
 // Arguments:
 //   - currentVersion: The currently installed version
 //   - availableVersions: List of available versions (assumed to be sorted in descending order)
 //   - strategy: The version selection strategy (StrategyLatest or StrategyMinor)
 //   - constraints: Optional constraints to filter versions (nil = no filtering)
+//   - filters: Optional compatibility filters
 //
 // Returns:
 //   - The selected version string
 //   - An error if no versions are available or match the criteria
-func SelectVersion(currentVersion string, availableVersions []string, strategy Strategy, constraints Constraints) (string, error) {
+func SelectVersion(currentVersion string, availableVersions []string, strategy Strategy, constraints Constraints, filters ...Filter) (string, error) {
+	selected, _, err := SelectVersionWithExclusions(currentVersion, availableVersions, strategy, constraints, filters...)
+	return selected, err
+}
+
+// SelectVersionWithExclusions behaves like SelectVersion and also returns the
+// versions the strategy would have preferred over the selected one but that were
+// rejected by a filter, highest first.
+func SelectVersionWithExclusions(
+	currentVersion string,
+	availableVersions []string,
+	strategy Strategy,
+	constraints Constraints,
+	filters ...Filter,
+) (string, []Exclusion, error) {
 	if len(availableVersions) == 0 {
-		return "", fmt.Errorf("no available versions")
+		return "", nil, fmt.Errorf("no available versions")
 	}
 
 	// Filter versions by constraints if provided
@@ -37,10 +88,66 @@ func SelectVersion(currentVersion string, availableVersions []string, strategy S
 	if len(constraints) > 0 {
 		candidateVersions = filterVersionsByConstraints(availableVersions, constraints)
 		if len(candidateVersions) == 0 {
-			return "", fmt.Errorf("no versions satisfy the constraints")
+			return "", nil, fmt.Errorf("no versions satisfy the constraints")
+		}
+	}
+
+	if len(filters) == 0 {
+		selected, err := selectByStrategy(currentVersion, candidateVersions, strategy)
+		return selected, nil, err
+	}
+
+	// What the strategy would pick without compatibility filtering
+	preferred, err := selectByStrategy(currentVersion, candidateVersions, strategy)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var compatible []string
+	rejected := make(map[string]string)
+	for _, candidate := range candidateVersions {
+		if ok, reason := applyFilters(candidate, filters); ok {
+			compatible = append(compatible, candidate)
+		} else {
+			rejected[candidate] = reason
+		}
+	}
+
+	var exclusions []Exclusion
+	selected, selectErr := "", fmt.Errorf("no compatible versions")
+	if len(compatible) > 0 {
+		selected, selectErr = selectByStrategy(currentVersion, compatible, strategy)
+	}
+
+	// Report rejected versions between the selected and the preferred version
+	for _, candidate := range candidateVersions {
+		reason, wasRejected := rejected[candidate]
+		if !wasRejected {
+			continue
+		}
+		if newer, err := IsNewer(preferred, candidate); err != nil || newer {
+			continue
+		}
+		if selected != "" {
+			if newer, err := IsNewer(selected, candidate); err != nil || !newer {
+				continue
+			}
 		}
+		exclusions = append(exclusions, Exclusion{Version: candidate, Reason: reason})
 	}
 
+	if selectErr != nil {
+		if len(exclusions) > 0 {
+			return "", exclusions, fmt.Errorf("no compatible versions: %s %s", exclusions[0].Version, exclusions[0].Reason)
+		}
+		return "", nil, selectErr
+	}
+
+	return selected, exclusions, nil
+}
+
+// selectByStrategy applies the strategy to candidate versions sorted in descending order
+func selectByStrategy(currentVersion string, candidateVersions []string, strategy Strategy) (string, error) {
 	switch strategy {
 	case StrategyLatest:
 		// Return the highest version (assumed to be first after sorting in descending order)
@@ -55,6 +162,19 @@ func SelectVersion(currentVersion string, availableVersions []string, strategy S
 	}
 }
 
+// applyFilters runs all filters against a version, returning the first rejection reason
+func applyFilters(version string, filters []Filter) (bool, string) {
+	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
+		if ok, reason := filter(version); !ok {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
 // filterVersionsByConstraints filters a list of version strings through constraint evaluation.
 // Returns only versions that satisfy all constraints (AND semantics).
 func filterVersionsByConstraints(versions []string, constraints Constraints) []string {
@@ -102,3 +222,33 @@ func selectMinorVersion(currentVersion string, availableVersions []string) (stri
 func IsValidStrategy(s string) bool {
 	return s == string(StrategyMinor) || s == string(StrategyLatest)
 }
+
+// PinStyle controls how a version is written when pinning an unversioned module
+type PinStyle string
+
+const (
+	PinStyleExact       PinStyle = "exact"       // version = "5.1.2"
+	PinStylePessimistic PinStyle = "pessimistic" // version = "~> 5.1"
+)
+
+// PinConstraint renders the version attribute value for pinning to version
+// Example: PinConstraint("5.1.2", PinStylePessimistic) returns "~> 5.1"
+func PinConstraint(version string, style PinStyle) (string, error) {
+	switch style {
+	case PinStyleExact, "":
+		return version, nil
+	case PinStylePessimistic:
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %q: %w", version, err)
+		}
+		return fmt.Sprintf("~> %d.%d", v.Major(), v.Minor()), nil
+	default:
+		return "", fmt.Errorf("unknown pin style: %s", style)
+	}
+}
+
+// IsValidPinStyle checks if a pin style string is valid
+func IsValidPinStyle(s string) bool {
+	return s == string(PinStyleExact) || s == string(PinStylePessimistic)
+}
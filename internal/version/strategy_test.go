@@ -361,3 +361,144 @@ func TestIsValidStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestPinConstraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		style   PinStyle
+		want    string
+		wantErr bool
+	}{
+		{"exact", "5.1.2", PinStyleExact, "5.1.2", false},
+		{"default is exact", "5.1.2", "", "5.1.2", false},
+		{"pessimistic", "5.1.2", PinStylePessimistic, "~> 5.1", false},
+		{"pessimistic invalid version", "latest", PinStylePessimistic, "", true},
+		{"unknown style", "5.1.2", PinStyle("loose"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PinConstraint(tt.version, tt.style)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PinConstraint() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PinConstraint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectVersionWithExclusions(t *testing.T) {
+	requiresAWS5 := func(v string) (bool, string) {
+		if v == "5.1.0" || v == "5.0.0" {
+			return false, "requires aws >= 5.0"
+		}
+		return true, ""
+	}
+
+	available := []string{"5.1.0", "5.0.0", "4.2.0", "4.1.0"}
+
+	selected, exclusions, err := SelectVersionWithExclusions("4.1.0", available, StrategyLatest, nil, requiresAWS5)
+	if err != nil {
+		t.Fatalf("SelectVersionWithExclusions() error = %v", err)
+	}
+	if selected != "4.2.0" {
+		t.Errorf("selected = %s, want 4.2.0", selected)
+	}
+	if len(exclusions) != 2 || exclusions[0].Version != "5.1.0" || exclusions[0].Reason != "requires aws >= 5.0" {
+		t.Errorf("exclusions = %+v, want 5.1.0 and 5.0.0", exclusions)
+	}
+
+	// Minor strategy never preferred a 5.x release, so nothing is reported
+	selected, exclusions, err = SelectVersionWithExclusions("4.1.0", available, StrategyMinor, nil, requiresAWS5)
+	if err != nil || selected != "4.2.0" || len(exclusions) != 0 {
+		t.Errorf("minor strategy = %s, %+v, %v; want 4.2.0 without exclusions", selected, exclusions, err)
+	}
+
+	// SelectVersion keeps its behavior and honors filters
+	got, err := SelectVersion("4.1.0", available, StrategyLatest, nil, requiresAWS5)
+	if err != nil || got != "4.2.0" {
+		t.Errorf("SelectVersion() = %s, %v; want 4.2.0", got, err)
+	}
+
+	rejectAll := func(v string) (bool, string) { return false, "requires aws >= 6.0" }
+	if _, exclusions, err := SelectVersionWithExclusions("4.1.0", available, StrategyLatest, nil, rejectAll); err == nil || len(exclusions) != 4 {
+		t.Errorf("rejecting all versions = %+v, %v; want error with 4 exclusions", exclusions, err)
+	}
+}
+
+func TestRequiredVersionFilter(t *testing.T) {
+	requiredCore := map[string][]string{
+		"5.0.0": {">= 1.5.0"},
+		"4.2.0": {">= 1.3"},
+	}
+
+	filter, err := RequiredVersionFilter("1.3.9", requiredCore)
+	if err != nil {
+		t.Fatalf("RequiredVersionFilter() error = %v", err)
+	}
+
+	if ok, reason := filter("5.0.0"); ok || reason != "requires terraform >= 1.5.0" {
+		t.Errorf("filter(5.0.0) = (%v, %q), want rejection", ok, reason)
+	}
+	if ok, _ := filter("4.2.0"); !ok {
+		t.Error("filter(4.2.0) should accept a compatible version")
+	}
+	if ok, _ := filter("4.0.0"); !ok {
+		t.Error("filter(4.0.0) should accept a version without known constraints")
+	}
+
+	selected, exclusions, err := SelectVersionWithExclusions("4.0.0", []string{"5.0.0", "4.2.0", "4.0.0"}, StrategyLatest, nil, filter)
+	if err != nil || selected != "4.2.0" || len(exclusions) != 1 {
+		t.Errorf("SelectVersionWithExclusions() = %s, %+v, %v; want 4.2.0 excluding 5.0.0", selected, exclusions, err)
+	}
+
+	if _, err := RequiredVersionFilter("not-a-version", requiredCore); err == nil {
+		t.Error("RequiredVersionFilter() should reject an invalid terraform version")
+	}
+}
+
+func TestRequiredVersionFilterExactAndInvalid(t *testing.T) {
+	requiredCore := map[string][]string{
+		"5.0.0": {"1.5.7"},
+		"4.2.0": {">= 1.3, 1.5.7"},
+		"4.1.0": {"~> 1.5.0"},
+		"4.0.0": {">= 1.x.y"},
+	}
+
+	filter, err := RequiredVersionFilter("1.6.0", requiredCore)
+	if err != nil {
+		t.Fatalf("RequiredVersionFilter() error = %v", err)
+	}
+
+	tests := []struct {
+		version string
+		ok      bool
+		reason  string
+	}{
+		// A bare version only allows that version
+		{"5.0.0", false, "requires terraform 1.5.7"},
+		{"4.2.0", false, "requires terraform >= 1.3, 1.5.7"},
+		{"4.1.0", false, "requires terraform ~> 1.5.0"},
+		{"4.0.0", false, `has an invalid terraform constraint ">= 1.x.y"`},
+		{"3.0.0", true, ""},
+	}
+	for _, tt := range tests {
+		if ok, reason := filter(tt.version); ok != tt.ok || reason != tt.reason {
+			t.Errorf("filter(%s) = (%v, %q), want (%v, %q)", tt.version, ok, reason, tt.ok, tt.reason)
+		}
+	}
+
+	exact, err := RequiredVersionFilter("1.5.7", requiredCore)
+	if err != nil {
+		t.Fatalf("RequiredVersionFilter() error = %v", err)
+	}
+	for _, version := range []string{"5.0.0", "4.2.0", "4.1.0"} {
+		if ok, reason := exact(version); !ok {
+			t.Errorf("filter(%s) = %q, want terraform 1.5.7 accepted", version, reason)
+		}
+	}
+}